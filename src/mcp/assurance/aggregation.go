@@ -0,0 +1,184 @@
+package assurance
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Evidence aggregation modes: how a holon's own evidence combines into SelfScore
+const (
+	AggregateWeighted = "weighted" // weighted mean of verdict scores
+	AggregateMin      = "min"      // the weakest piece of evidence decides
+	AggregateBayesian = "bayesian" // posterior mean of a Beta prior updated by weighted verdicts
+)
+
+// DefaultCarrierWeights rank evidence by the carrier that produced it: a quick
+// internal sanity check counts for less than a test run
+var DefaultCarrierWeights = map[string]float64{
+	"internal-logic": 0.5,
+	"formal-logic":   0.8,
+	"test-runner":    1.0,
+	"research":       0.7,
+	"auditor":        0.8,
+}
+
+// DefaultTypeWeights rank evidence by type; second-hand research counts for
+// less than an internal test
+var DefaultTypeWeights = map[string]float64{
+	"external": 0.7,
+	"research": 0.7,
+}
+
+// AggregationConfig selects the aggregation mode and overrides the default
+// weights per evidence type or carrier. Unlisted types and carriers weigh 1.
+type AggregationConfig struct {
+	Mode           string             `json:"mode,omitempty"`
+	TypeWeights    map[string]float64 `json:"type_weights,omitempty"`
+	CarrierWeights map[string]float64 `json:"carrier_weights,omitempty"`
+	Prior          []float64          `json:"prior,omitempty"` // Beta(α, β) for bayesian, default [1, 1]
+}
+
+// Aggregation combines evidence scores into a SelfScore
+type Aggregation struct {
+	Mode           string
+	TypeWeights    map[string]float64
+	CarrierWeights map[string]float64
+	PriorAlpha     float64
+	PriorBeta      float64
+}
+
+// DefaultAggregation is the weighted mean with the default weights
+var DefaultAggregation = Aggregation{
+	Mode:           AggregateWeighted,
+	TypeWeights:    DefaultTypeWeights,
+	CarrierWeights: DefaultCarrierWeights,
+	PriorAlpha:     1,
+	PriorBeta:      1,
+}
+
+// Aggregation resolves the configured aggregation on top of the defaults
+func (c AggregationConfig) Aggregation() (Aggregation, error) {
+	agg := Aggregation{
+		Mode:           c.Mode,
+		TypeWeights:    mergeWeights(DefaultTypeWeights, c.TypeWeights),
+		CarrierWeights: mergeWeights(DefaultCarrierWeights, c.CarrierWeights),
+		PriorAlpha:     1,
+		PriorBeta:      1,
+	}
+
+	switch agg.Mode {
+	case "":
+		agg.Mode = AggregateWeighted
+	case AggregateWeighted, AggregateMin, AggregateBayesian:
+	default:
+		return Aggregation{}, fmt.Errorf("unknown evidence aggregation mode %q (use weighted, min or bayesian)", c.Mode)
+	}
+
+	for _, weights := range []map[string]float64{c.TypeWeights, c.CarrierWeights} {
+		for k, w := range weights {
+			if w < 0 || w > 1 {
+				return Aggregation{}, fmt.Errorf("weight for %s must be between 0 and 1, got %.2f", k, w)
+			}
+		}
+	}
+
+	if c.Prior != nil {
+		if len(c.Prior) != 2 || c.Prior[0] <= 0 || c.Prior[1] <= 0 {
+			return Aggregation{}, fmt.Errorf("prior needs two positive values [alpha, beta], got %v", c.Prior)
+		}
+		agg.PriorAlpha, agg.PriorBeta = c.Prior[0], c.Prior[1]
+	}
+	return agg, nil
+}
+
+func mergeWeights(defaults, overrides map[string]float64) map[string]float64 {
+	merged := make(map[string]float64, len(defaults)+len(overrides))
+	for k, w := range defaults {
+		merged[k] = w
+	}
+	for k, w := range overrides {
+		merged[k] = w
+	}
+	return merged
+}
+
+// Weight of a piece of evidence: its type weight times its carrier weight
+func (a Aggregation) Weight(evidenceType, carrier string) float64 {
+	w := 1.0
+	if tw, ok := a.TypeWeights[strings.ToLower(evidenceType)]; ok {
+		w *= tw
+	}
+	if cw, ok := a.CarrierWeights[strings.ToLower(carrier)]; ok {
+		w *= cw
+	}
+	return w
+}
+
+// String identifies the aggregation in reports
+func (a Aggregation) String() string {
+	if a.Mode == AggregateBayesian {
+		return fmt.Sprintf("bayesian(Beta(%g, %g))", a.PriorAlpha, a.PriorBeta)
+	}
+	return a.Mode
+}
+
+// evidenceScore is one piece of evidence entering the aggregation
+type evidenceScore struct {
+	ID     string
+	Score  float64
+	Weight float64
+}
+
+// aggregate combines evidence scores and explains each item's contribution.
+// Items with zero weight are listed but do not count.
+func (a Aggregation) aggregate(items []evidenceScore) (float64, []string) {
+	var factors []string
+	var counted []evidenceScore
+	for _, e := range items {
+		if e.Weight > 0 {
+			counted = append(counted, e)
+		} else {
+			factors = append(factors, fmt.Sprintf("Evidence %s: score %.2f, weight 0 (ignored)", e.ID, e.Score))
+		}
+	}
+	if len(counted) == 0 {
+		return 0, append(factors, "No weighted evidence found")
+	}
+
+	switch a.Mode {
+	case AggregateMin:
+		sort.SliceStable(counted, func(i, j int) bool { return counted[i].Score < counted[j].Score })
+		for i, e := range counted {
+			role := "above the weakest"
+			if i == 0 {
+				role = "weakest, sets R"
+			}
+			factors = append(factors, fmt.Sprintf("Evidence %s: score %.2f (%s)", e.ID, e.Score, role))
+		}
+		return counted[0].Score, factors
+
+	case AggregateBayesian:
+		alpha, beta := a.PriorAlpha, a.PriorBeta
+		for _, e := range counted {
+			alpha += e.Weight * e.Score
+			beta += e.Weight * (1 - e.Score)
+			factors = append(factors, fmt.Sprintf("Evidence %s: score %.2f × weight %.2f (+%.2f α, +%.2f β)",
+				e.ID, e.Score, e.Weight, e.Weight*e.Score, e.Weight*(1-e.Score)))
+		}
+		return alpha / (alpha + beta), factors
+
+	default:
+		var total, weights float64
+		for _, e := range counted {
+			total += e.Weight * e.Score
+			weights += e.Weight
+		}
+		for _, e := range counted {
+			factors = append(factors, fmt.Sprintf("Evidence %s: score %.2f × weight %.2f (%.0f%% of the weight)",
+				e.ID, e.Score, e.Weight, math.Round(100*e.Weight/weights)))
+		}
+		return total / weights, factors
+	}
+}
@@ -0,0 +1,110 @@
+package assurance
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func insertWeightedEvidence(t *testing.T, calc *Calculator) {
+	t.Helper()
+	validUntil := time.Now().Add(24 * time.Hour)
+	// A passing internal sanity check and a failing test run
+	if _, err := calc.DB.Exec("INSERT INTO evidence (id, holon_id, type, carrier_ref, verdict, valid_until) VALUES ('sanity', 'A', 'verification', 'internal-logic', 'pass', ?)", validUntil); err != nil {
+		t.Fatalf("failed to insert evidence: %v", err)
+	}
+	if _, err := calc.DB.Exec("INSERT INTO evidence (id, holon_id, type, carrier_ref, verdict, valid_until) VALUES ('bench', 'A', 'internal', 'test-runner', 'fail', ?)", validUntil); err != nil {
+		t.Fatalf("failed to insert evidence: %v", err)
+	}
+}
+
+func TestCalculateReliability_WeightedEvidence(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	calc := New(db)
+	insertWeightedEvidence(t, calc)
+
+	report, err := calc.CalculateReliability(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+
+	// internal-logic weighs 0.5, test-runner 1.0: (0.5×1 + 1×0) / 1.5
+	if math.Abs(report.SelfScore-1.0/3) > 1e-9 {
+		t.Errorf("Expected the test run to outweigh the sanity check (R=0.33), got %.4f", report.SelfScore)
+	}
+	if report.Aggregation != AggregateWeighted {
+		t.Errorf("Expected weighted aggregation by default, got %q", report.Aggregation)
+	}
+
+	factors := strings.Join(report.Factors, "\n")
+	for _, want := range []string{"Evidence bench: score 0.00 × weight 1.00 (67% of the weight)", "Evidence sanity: score 1.00 × weight 0.50 (33% of the weight)"} {
+		if !strings.Contains(factors, want) {
+			t.Errorf("Expected factor %q in:\n%s", want, factors)
+		}
+	}
+}
+
+func TestCalculateReliability_AggregationModes(t *testing.T) {
+	tests := []struct {
+		cfg      AggregationConfig
+		expected float64
+		name     string
+	}{
+		{AggregationConfig{Mode: "min"}, 0.0, "min"},
+		// Beta(1,1) + 0.5 pass + 1.0 fail: α=1.5, β=2
+		{AggregationConfig{Mode: "bayesian"}, 1.5 / 3.5, "bayesian(Beta(1, 1))"},
+		{AggregationConfig{CarrierWeights: map[string]float64{"internal-logic": 1}}, 0.5, "weighted"},
+		{AggregationConfig{CarrierWeights: map[string]float64{"test-runner": 0}}, 1.0, "weighted"},
+	}
+
+	for _, tt := range tests {
+		db := setupTestDB(t)
+		calc, err := NewFromConfig(db, Config{EvidenceAggregation: tt.cfg})
+		if err != nil {
+			t.Fatalf("NewFromConfig failed: %v", err)
+		}
+		insertWeightedEvidence(t, calc)
+
+		report, err := calc.CalculateReliability(context.Background(), "A")
+		if err != nil {
+			t.Fatalf("CalculateReliability failed: %v", err)
+		}
+		if math.Abs(report.SelfScore-tt.expected) > 1e-9 {
+			t.Errorf("%+v: expected R=%.4f, got %.4f", tt.cfg, tt.expected, report.SelfScore)
+		}
+		if report.Aggregation != tt.name {
+			t.Errorf("Expected aggregation %q, got %q", tt.name, report.Aggregation)
+		}
+		_ = db.Close()
+	}
+}
+
+func TestLoadConfig_EvidenceAggregation(t *testing.T) {
+	tests := []struct {
+		json    string
+		wantErr bool
+	}{
+		{`{"evidence_aggregation": {"mode": "bayesian", "prior": [2, 1]}}`, false},
+		{`{"evidence_aggregation": {"carrier_weights": {"research": 0.2}, "type_weights": {"benchmark": 1}}}`, false},
+		{`{"evidence_aggregation": {"mode": "median"}}`, true},
+		{`{"evidence_aggregation": {"carrier_weights": {"auditor": 1.5}}}`, true},
+		{`{"evidence_aggregation": {"mode": "bayesian", "prior": [0, 1]}}`, true},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, []byte(tt.json), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		_, err := LoadConfig(path)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("LoadConfig(%s): wantErr=%v, got %v", tt.json, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package assurance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Artifact kinds: what an evidence item's artifact_ref points at
+const (
+	ArtifactJUnit     = "junit"     // JUnit XML test report
+	ArtifactGoTest    = "gotest"    // go test -json output
+	ArtifactBenchmark = "benchmark" // benchmark output
+	ArtifactLog       = "log"       // log file
+	ArtifactFile      = "file"      // any other file
+	ArtifactCommit    = "commit"    // git commit; artifact_ref is its SHA
+)
+
+// ArtifactKinds lists the accepted artifact kinds
+var ArtifactKinds = []string{ArtifactJUnit, ArtifactGoTest, ArtifactBenchmark, ArtifactLog, ArtifactFile, ArtifactCommit}
+
+// HashFile returns the hex SHA-256 of a file's content
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close() //nolint:errcheck
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ArtifactTrackedPath is the file whose content an artifact vouches for: the
+// original source when it lies in the project, else the stored copy
+func ArtifactTrackedPath(ref, source string) string {
+	if source != "" {
+		return source
+	}
+	return ref
+}
+
+// artifactStale re-checks a file artifact against the hash recorded with its
+// evidence. Commits are addressed by their hash and never go stale. The
+// reason is empty when the artifact is intact.
+func (c *Calculator) artifactStale(kind, path, hash string) string {
+	if c.RootDir == "" || path == "" || hash == "" || kind == ArtifactCommit {
+		return ""
+	}
+	current, err := HashFile(filepath.Join(c.RootDir, filepath.FromSlash(path)))
+	if os.IsNotExist(err) {
+		return "is missing"
+	}
+	if err != nil {
+		return "is unreadable"
+	}
+	if current != hash {
+		return "changed since it was recorded"
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package assurance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalculateReliability_StaleArtifact(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	root := t.TempDir()
+	report := filepath.Join(root, "report.xml")
+	if err := os.WriteFile(report, []byte(`<testsuite tests="1" failures="0"/>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := HashFile(report)
+	if err != nil {
+		t.Fatalf("HashFile failed: %v", err)
+	}
+
+	calc := New(db)
+	calc.RootDir = root
+	// The stored copy never changes; staleness follows the source file
+	if _, err := db.Exec("INSERT INTO evidence (id, holon_id, type, verdict, valid_until, artifact_kind, artifact_ref, artifact_hash, artifact_source) VALUES ('e1', 'A', 'internal', 'pass', ?, 'junit', '.quint/evidence/artifacts/copy-report.xml', ?, 'report.xml')",
+		time.Now().Add(24*time.Hour), hash); err != nil {
+		t.Fatalf("failed to insert evidence: %v", err)
+	}
+	// A waiver does not cover a changed artifact
+	if _, err := db.Exec("INSERT INTO waivers (id, evidence_id, waived_by, waived_until, rationale) VALUES ('w1', 'e1', 'user', ?, 'known')", time.Now().Add(24*time.Hour)); err != nil {
+		t.Fatalf("failed to insert waiver: %v", err)
+	}
+
+	ctx := context.Background()
+	r, err := calc.CalculateReliability(ctx, "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	if r.SelfScore != 1.0 {
+		t.Errorf("Expected an intact artifact to keep R=1.0, got %.2f", r.SelfScore)
+	}
+
+	if err := os.WriteFile(report, []byte(`<testsuite tests="1" failures="1"/>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r, err = calc.CalculateReliability(ctx, "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	if r.SelfScore != DefaultDecayFloor {
+		t.Errorf("Expected a changed artifact to drop R to the floor, got %.2f", r.SelfScore)
+	}
+	if !strings.Contains(strings.Join(r.Factors, "\n"), "artifact report.xml changed since it was recorded") {
+		t.Errorf("Expected a stale artifact factor, got %v", r.Factors)
+	}
+
+	if err := os.Remove(report); err != nil {
+		t.Fatal(err)
+	}
+	r, _ = calc.CalculateReliability(ctx, "A")
+	if !strings.Contains(strings.Join(r.Factors, "\n"), "artifact report.xml is missing") {
+		t.Errorf("Expected a missing artifact factor, got %v", r.Factors)
+	}
+
+	// Without a root directory artifacts are not re-checked
+	calc.RootDir = ""
+	if r, _ = calc.CalculateReliability(ctx, "A"); r.SelfScore != 1.0 {
+		t.Errorf("Expected R=1.0 without a root directory, got %.2f", r.SelfScore)
+	}
+}
@@ -3,11 +3,15 @@ package assurance
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"math"
 	"strings"
 	"time"
 )
 
+// MaxFormality is the top of the F scale (F0 informal prose .. F9 machine-checked proof)
+const MaxFormality = 9
+
 // AssuranceReport contains details of the reliability calculation for AI explanation
 type AssuranceReport struct {
 	HolonID      string
@@ -15,58 +19,197 @@ type AssuranceReport struct {
 	SelfScore    float64 // Score based on own evidence
 	WeakestLink  string  // ID of the dependency pulling the score down
 	DecayPenalty float64
+	Formality    int        // F: min over self and dependencies (WLNK)
+	ClaimScope   ClaimScope // G: intersection over self and dependencies
+	PenaltyModel string     // Φ(CL) model that produced the CL penalties
+	Aggregation  string     // How own evidence was combined into SelfScore
+	Projection   []ProjectedR
 	Factors      []string // Textual explanations for AI
 }
 
+// ProjectedR is the R a holon will have after Days if no evidence is refreshed
+type ProjectedR struct {
+	Days int
+	At   time.Time
+	R    float64
+}
+
+// ProjectionHorizons are the days ahead at which CalculateAssurance projects R
+var ProjectionHorizons = []int{7, 30, 90}
+
+// Tuple renders the assurance tuple ⟨F,G,R⟩
+func (r *AssuranceReport) Tuple() string {
+	return fmt.Sprintf("⟨F%d, G%s, R%.2f⟩", r.Formality, r.ClaimScope, r.FinalScore)
+}
+
 // Calculator handles assurance logic
 type Calculator struct {
-	DB *sql.DB
+	DB          *sql.DB
+	Penalty     PenaltyModel
+	Aggregation Aggregation
+	Decay       Decay
+	RootDir     string // Project root artifact paths are relative to; artifacts are not re-checked when empty
 }
 
-// New creates a new Calculator
+// New creates a new Calculator with the linear congruence penalty, weighted
+// evidence aggregation and step decay
 func New(db *sql.DB) *Calculator {
-	return &Calculator{DB: db}
+	return &Calculator{DB: db, Penalty: LinearPenalty, Aggregation: DefaultAggregation, Decay: DefaultDecay}
 }
 
-// CalculateReliability calculates R for a holon (public API)
+func (c *Calculator) penaltyModel() PenaltyModel {
+	if c.Penalty.Name == "" {
+		return LinearPenalty
+	}
+	return c.Penalty
+}
+
+func (c *Calculator) aggregation() Aggregation {
+	if c.Aggregation.Mode == "" {
+		return DefaultAggregation
+	}
+	return c.Aggregation
+}
+
+// CalculateAssurance calculates the full F-G-R tuple for a holon (public API).
+// F propagates by weakest link (min), G by intersection, R by WLNK with CL penalty.
+// The report projects R over ProjectionHorizons as evidence decays.
+func (c *Calculator) CalculateAssurance(ctx context.Context, holonID string) (*AssuranceReport, error) {
+	report, err := c.CalculateReliability(ctx, holonID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, days := range ProjectionHorizons {
+		at := now.AddDate(0, 0, days)
+		r, err := c.ProjectReliability(ctx, holonID, at)
+		if err != nil {
+			return nil, err
+		}
+		report.Projection = append(report.Projection, ProjectedR{Days: days, At: at, R: r})
+	}
+	return report, nil
+}
+
+// ProjectReliability calculates the R a holon will have at a given time if no
+// evidence is refreshed. The cached score is left untouched.
+func (c *Calculator) ProjectReliability(ctx context.Context, holonID string, at time.Time) (float64, error) {
+	report, err := c.calculateReliabilityWithVisited(ctx, holonID, make(map[string]bool), at, false)
+	if err != nil {
+		return 0, err
+	}
+	return report.FinalScore, nil
+}
+
+// ForecastDrop finds the first day within the horizon on which a holon's R
+// falls below the threshold. Expiring evidence only loses score over time, so
+// the day is found by bisection. ok is false when R stays at or above the
+// threshold.
+func (c *Calculator) ForecastDrop(ctx context.Context, holonID string, threshold float64, days int) (day int, r float64, ok bool, err error) {
+	now := time.Now()
+	below := func(d int) (bool, float64, error) {
+		r, err := c.ProjectReliability(ctx, holonID, now.AddDate(0, 0, d))
+		return r < threshold, r, err
+	}
+
+	isBelow, last, err := below(days)
+	if err != nil || !isBelow {
+		return 0, 0, false, err
+	}
+
+	lo, hi := 0, days
+	r = last
+	for lo < hi {
+		mid := (lo + hi) / 2
+		isBelow, midR, err := below(mid)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if isBelow {
+			hi, r = mid, midR
+		} else {
+			lo = mid + 1
+		}
+	}
+	return hi, r, true, nil
+}
+
+// CalculateReliability calculates R for a holon (public API) and updates the
+// cached score. The returned report carries the full F-G-R tuple as well, but
+// no projections; use CalculateAssurance where those are shown.
 func (c *Calculator) CalculateReliability(ctx context.Context, holonID string) (*AssuranceReport, error) {
-	visited := make(map[string]bool)
-	return c.calculateReliabilityWithVisited(ctx, holonID, visited)
+	return c.calculateReliabilityWithVisited(ctx, holonID, make(map[string]bool), time.Now(), true)
 }
 
 // calculateReliabilityWithVisited is the internal implementation with cycle detection
-func (c *Calculator) calculateReliabilityWithVisited(ctx context.Context, holonID string, visited map[string]bool) (*AssuranceReport, error) {
+// at is the time evidence freshness is judged against; the cached score is
+// only updated for the present.
+func (c *Calculator) calculateReliabilityWithVisited(ctx context.Context, holonID string, visited map[string]bool, at time.Time, updateCache bool) (*AssuranceReport, error) {
 	// Cycle detection: if already visited, return neutral score to break cycle
 	if visited[holonID] {
 		return &AssuranceReport{
-			HolonID:    holonID,
-			FinalScore: 1.0, // Neutral - don't penalize for cycle
-			SelfScore:  1.0,
-			Factors:    []string{"Cycle detected, skipping re-evaluation"},
+			HolonID:      holonID,
+			FinalScore:   1.0, // Neutral - don't penalize for cycle
+			SelfScore:    1.0,
+			Formality:    MaxFormality,
+			PenaltyModel: c.penaltyModel().String(),
+			Aggregation:  c.aggregation().String(),
+			Factors:      []string{"Cycle detected, skipping re-evaluation"},
 		}, nil
 	}
 	visited[holonID] = true
 
-	report := &AssuranceReport{HolonID: holonID}
+	report := &AssuranceReport{HolonID: holonID, PenaltyModel: c.penaltyModel().String(), Aggregation: c.aggregation().String()}
 
-	// 1. Calculate Self Score (based on Evidence)
+	// 0. Own Formality and ClaimScope as declared on the holon
+	var holonF sql.NullInt64
+	var holonG sql.NullString
+	err := c.DB.QueryRowContext(ctx, "SELECT formality, claim_scope FROM holons WHERE id = ?", holonID).Scan(&holonF, &holonG)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	report.Formality = int(holonF.Int64)
+	report.ClaimScope = ParseClaimScope(holonG.String)
+
+	// 1. Calculate Self Score (based on Evidence), weighted by type and carrier
 	// B.3.4: Check for expired evidence
-	rows, err := c.DB.QueryContext(ctx, "SELECT verdict, valid_until FROM evidence WHERE holon_id = ?", holonID)
+	rows, err := c.DB.QueryContext(ctx, `
+		SELECT id, type, carrier_ref, verdict, valid_until, formality, artifact_kind, artifact_ref, artifact_hash, artifact_source
+		FROM evidence WHERE holon_id = ? ORDER BY id`, holonID)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close() //nolint:errcheck
 
-	var totalScore, count float64
+	// Collect evidence first: waivers are looked up per expired item
+	type evidenceRow struct {
+		id, verdict                                             string
+		evidenceType, carrier                                   sql.NullString
+		validUntil                                              *time.Time
+		formality                                               sql.NullInt64
+		artifactKind, artifactRef, artifactHash, artifactSource sql.NullString
+	}
+	var evidence []evidenceRow
 	for rows.Next() {
-		var verdict string
-		var validUntil *time.Time
-		if err := rows.Scan(&verdict, &validUntil); err != nil {
+		var e evidenceRow
+		if err := rows.Scan(&e.id, &e.evidenceType, &e.carrier, &e.verdict, &e.validUntil, &e.formality, &e.artifactKind, &e.artifactRef, &e.artifactHash, &e.artifactSource); err != nil {
 			continue
 		}
+		evidence = append(evidence, e)
+	}
+	_ = rows.Close()
+
+	agg := c.aggregation()
+	var items []evidenceScore
+	for _, e := range evidence {
+		// A claim cannot be more formal than the evidence backing it
+		if e.formality.Valid && int(e.formality.Int64) < report.Formality {
+			report.Formality = int(e.formality.Int64)
+			report.Factors = append(report.Factors, fmt.Sprintf("Formality capped at F%d by evidence", e.formality.Int64))
+		}
 
 		score := 0.0
-		switch strings.ToLower(verdict) {
+		switch strings.ToLower(e.verdict) {
 		case "pass":
 			score = 1.0
 		case "degrade":
@@ -75,18 +218,43 @@ func (c *Calculator) calculateReliabilityWithVisited(ctx context.Context, holonI
 			score = 0.0
 		}
 
-		// Evidence Decay Logic
-		if validUntil != nil && time.Now().After(*validUntil) {
-			report.Factors = append(report.Factors, "Evidence expired (Decay applied)")
-			score = 0.1                // Penalty for expiration, not zero but close
-			report.DecayPenalty += 0.9 // Track how much was lost
+		// Evidence backed by an artifact is stale once the artifact changes:
+		// it drops to the decay floor and no waiver covers it
+		tracked := ArtifactTrackedPath(e.artifactRef.String, e.artifactSource.String)
+		if stale := c.artifactStale(e.artifactKind.String, tracked, e.artifactHash.String); stale != "" {
+			floor := c.Decay.For(e.evidenceType.String).Floor
+			report.Factors = append(report.Factors, fmt.Sprintf("Evidence %s: artifact %s %s (stale) %.2f → %.2f",
+				e.id, tracked, stale, score, floor))
+			report.DecayPenalty += math.Max(0, score-floor)
+			items = append(items, evidenceScore{ID: e.id, Score: floor, Weight: agg.Weight(e.evidenceType.String, e.carrier.String)})
+			continue
+		}
+
+		// Evidence Decay Logic: expired evidence decays towards the curve's floor,
+		// unless a waiver accepts the risk until after the time judged
+		if e.validUntil != nil && at.After(*e.validUntil) {
+			waived, err := c.waived(ctx, e.id, at, report)
+			if err != nil {
+				return nil, err
+			}
+			if !waived {
+				overdue := at.Sub(*e.validUntil)
+				curve := c.Decay.For(e.evidenceType.String)
+				decayed := curve.Apply(score, overdue)
+				report.Factors = append(report.Factors, "Evidence expired (Decay applied)")
+				report.Factors = append(report.Factors, fmt.Sprintf("Evidence %s: %.0f days past valid_until, %s decay %.2f → %.2f",
+					e.id, math.Floor(overdue.Hours()/24), curve, score, decayed))
+				report.DecayPenalty += math.Max(0, score-decayed) // Track how much was lost
+				score = decayed
+			}
 		}
-		totalScore += score
-		count++
+		items = append(items, evidenceScore{ID: e.id, Score: score, Weight: agg.Weight(e.evidenceType.String, e.carrier.String)})
 	}
 
-	if count > 0 {
-		report.SelfScore = totalScore / count // Or other aggregation logic
+	if len(items) > 0 {
+		var factors []string
+		report.SelfScore, factors = agg.aggregate(items)
+		report.Factors = append(report.Factors, factors...)
 	} else {
 		report.SelfScore = 0.0 // L0: Unsubstantiated
 		report.Factors = append(report.Factors, "No evidence found (L0)")
@@ -129,13 +297,26 @@ func (c *Calculator) calculateReliabilityWithVisited(ctx context.Context, holonI
 	minDepScore := 1.0
 	for _, d := range deps {
 		// Recursive call for dependency with visited map for cycle detection
-		depReport, err := c.calculateReliabilityWithVisited(ctx, d.id, visited)
+		depReport, err := c.calculateReliabilityWithVisited(ctx, d.id, visited, at, updateCache)
 		if err != nil {
 			depReport = &AssuranceReport{FinalScore: 0.0}
 		}
 
-		// CL Penalty: CL=3 (0.0), CL=2 (0.1), CL=1 (0.4), CL=0 (0.9)
-		penalty := calculateCLPenalty(d.cl)
+		// F: weakest link across dependencies
+		if depReport.Formality < report.Formality {
+			report.Formality = depReport.Formality
+			report.Factors = append(report.Factors, fmt.Sprintf("Formality capped at F%d by %s", depReport.Formality, d.id))
+		}
+
+		// G: the claim only holds where all dependencies hold
+		narrowed := report.ClaimScope.Intersect(depReport.ClaimScope)
+		if !narrowed.Equal(report.ClaimScope) {
+			report.ClaimScope = narrowed
+			report.Factors = append(report.Factors, "Claim scope narrowed by "+d.id)
+		}
+
+		// CL Penalty: Φ(CL) from the configured penalty model
+		penalty := c.penaltyModel().Penalty(d.cl)
 		effectiveR := math.Max(0, depReport.FinalScore-penalty)
 
 		if effectiveR < minDepScore {
@@ -150,6 +331,10 @@ func (c *Calculator) calculateReliabilityWithVisited(ctx context.Context, holonI
 
 	hasDeps := len(deps) > 0
 
+	if report.ClaimScope.IsEmpty() {
+		report.Factors = append(report.Factors, "Claim scope is empty: dependencies hold in disjoint scopes")
+	}
+
 	// 3. Weakest Link Principle (WLNK)
 	// The final rating cannot be higher than the weakest link (self or dependency)
 	if hasDeps {
@@ -159,6 +344,9 @@ func (c *Calculator) calculateReliabilityWithVisited(ctx context.Context, holonI
 	}
 
 	// Update cache (non-critical, log warning on failure)
+	if !updateCache {
+		return report, nil
+	}
 	if _, err := c.DB.ExecContext(ctx, "UPDATE holons SET cached_r_score = ? WHERE id = ?", report.FinalScore, holonID); err != nil {
 		report.Factors = append(report.Factors, "Warning: cache update failed")
 	}
@@ -166,15 +354,26 @@ func (c *Calculator) calculateReliabilityWithVisited(ctx context.Context, holonI
 	return report, nil
 }
 
-func calculateCLPenalty(cl int) float64 {
-	switch cl {
-	case 3:
-		return 0.0
-	case 2:
-		return 0.1
-	case 1:
-		return 0.4
-	default:
-		return 0.9
+// waived reports whether expired evidence is covered at the given time by its
+// latest unrevoked waiver, and records the waiver as a factor either way
+func (c *Calculator) waived(ctx context.Context, evidenceID string, at time.Time, report *AssuranceReport) (bool, error) {
+	var until time.Time
+	var by string
+	err := c.DB.QueryRowContext(ctx, `
+		SELECT waived_until, waived_by FROM waivers
+		WHERE evidence_id = ? AND revoked_at IS NULL
+		ORDER BY waived_until DESC LIMIT 1`, evidenceID).Scan(&until, &by)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if !until.After(at) {
+		report.Factors = append(report.Factors, fmt.Sprintf("Evidence %s: waiver expired on %s", evidenceID, until.Format("2006-01-02")))
+		return false, nil
 	}
+	report.Factors = append(report.Factors, fmt.Sprintf("Evidence %s: expired, waived until %s by %s", evidenceID, until.Format("2006-01-02"), by))
+	return true, nil
 }
@@ -3,6 +3,7 @@ package assurance
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,9 +19,10 @@ func setupTestDB(t *testing.T) *sql.DB {
 	db.SetMaxOpenConns(1) // Ensure single connection to avoid issues
 
 	schema := `
-	CREATE TABLE holons (id TEXT PRIMARY KEY, cached_r_score REAL DEFAULT 0.0);
-	CREATE TABLE evidence (id TEXT PRIMARY KEY, holon_id TEXT, verdict TEXT, valid_until DATETIME);
+	CREATE TABLE holons (id TEXT PRIMARY KEY, cached_r_score REAL DEFAULT 0.0, formality INTEGER DEFAULT 0, claim_scope TEXT);
+	CREATE TABLE evidence (id TEXT PRIMARY KEY, holon_id TEXT, type TEXT, carrier_ref TEXT, verdict TEXT, valid_until DATETIME, formality INTEGER, artifact_kind TEXT, artifact_ref TEXT, artifact_hash TEXT, artifact_source TEXT);
 	CREATE TABLE relations (source_id TEXT, target_id TEXT, relation_type TEXT, congruence_level INTEGER);
+	CREATE TABLE waivers (id TEXT PRIMARY KEY, evidence_id TEXT, waived_by TEXT, waived_until DATETIME, rationale TEXT, revoked_at DATETIME);
 	`
 	if _, err := db.Exec(schema); err != nil {
 		t.Fatalf("failed to init schema: %v", err)
@@ -143,3 +145,98 @@ func TestCalculateReliability_CycleDetection(t *testing.T) {
 		t.Errorf("Expected score 1.0 (cycle handled gracefully), got %f", report.FinalScore)
 	}
 }
+
+func TestCalculateAssurance_FormalityWeakestLink(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, _ = db.Exec("INSERT INTO holons (id, formality) VALUES ('A', 6), ('B', 3)")
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e1', 'A', 'pass', ?)", time.Now().Add(24*time.Hour))
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e2', 'B', 'pass', ?)", time.Now().Add(24*time.Hour))
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('B', 'A', 'componentOf', 3)")
+
+	calc := New(db)
+	report, err := calc.CalculateAssurance(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateAssurance failed: %v", err)
+	}
+
+	if report.Formality != 3 {
+		t.Errorf("Expected F3 (min of F6 and F3), got F%d", report.Formality)
+	}
+}
+
+func TestCalculateAssurance_EvidenceCapsFormality(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, _ = db.Exec("INSERT INTO holons (id, formality) VALUES ('A', 7)")
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until, formality) VALUES ('e1', 'A', 'pass', ?, 2)", time.Now().Add(24*time.Hour))
+
+	calc := New(db)
+	report, err := calc.CalculateAssurance(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateAssurance failed: %v", err)
+	}
+
+	if report.Formality != 2 {
+		t.Errorf("Expected F2 (capped by evidence), got F%d", report.Formality)
+	}
+}
+
+func TestCalculateAssurance_ClaimScopeIntersection(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, _ = db.Exec(`INSERT INTO holons (id, claim_scope) VALUES ('A', '["api","eu"]'), ('B', '["api","us"]'), ('C', NULL)`)
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('B', 'A', 'componentOf', 3)")
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('A', 'C', 'dependsOn', 3)")
+
+	calc := New(db)
+	report, err := calc.CalculateAssurance(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateAssurance failed: %v", err)
+	}
+
+	if report.ClaimScope.String() != "{api}" {
+		t.Errorf("Expected G {api}, got %s", report.ClaimScope)
+	}
+	if report.Tuple() != "⟨F0, G{api}, R0.00⟩" {
+		t.Errorf("Unexpected tuple: %s", report.Tuple())
+	}
+}
+
+func TestCalculateReliability_Waivers(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e1', 'A', 'pass', ?)", now.Add(-24*time.Hour))
+	_, _ = db.Exec("INSERT INTO waivers (id, evidence_id, waived_by, waived_until) VALUES ('old', 'e1', 'bob', ?)", now.Add(-time.Hour))
+	_, _ = db.Exec("INSERT INTO waivers (id, evidence_id, waived_by, waived_until, revoked_at) VALUES ('revoked', 'e1', 'carol', ?, ?)", now.AddDate(1, 0, 0), now)
+	_, _ = db.Exec("INSERT INTO waivers (id, evidence_id, waived_by, waived_until) VALUES ('w1', 'e1', 'alice', ?)", now.AddDate(0, 0, 10))
+
+	calc := New(db)
+	report, err := calc.CalculateAssurance(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateAssurance failed: %v", err)
+	}
+	if report.FinalScore != 1.0 {
+		t.Errorf("Expected the active waiver to keep R at 1.0, got %f", report.FinalScore)
+	}
+	want := "Evidence e1: expired, waived until " + now.AddDate(0, 0, 10).Format("2006-01-02") + " by alice"
+	if !strings.Contains(strings.Join(report.Factors, "\n"), want) {
+		t.Errorf("Expected factor %q, got %v", want, report.Factors)
+	}
+
+	// The waiver lapses before the 30-day projection; the revoked one never counts
+	if report.Projection[0].R != 1.0 || report.Projection[1].R != 0.1 {
+		t.Errorf("Expected R to drop once the waiver expires, got %+v", report.Projection)
+	}
+
+	_, _ = db.Exec("UPDATE waivers SET revoked_at = ? WHERE id = 'w1'", now)
+	report, _ = calc.CalculateReliability(context.Background(), "A")
+	if report.FinalScore != 0.1 {
+		t.Errorf("Expected R 0.1 once every waiver is revoked or expired, got %f", report.FinalScore)
+	}
+}
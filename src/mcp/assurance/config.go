@@ -0,0 +1,87 @@
+package assurance
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config holds per-project assurance settings, read from .quint/config.json
+type Config struct {
+	CongruencePenalty   PenaltyConfig     `json:"congruence_penalty"`
+	EvidenceAggregation AggregationConfig `json:"evidence_aggregation"`
+	EvidenceDecay       DecayConfig       `json:"evidence_decay"`
+}
+
+// PenaltyConfig selects Φ(CL): a built-in preset ("linear", "fpf") or
+// "custom" with an explicit table indexed by CL (CL0 first).
+type PenaltyConfig struct {
+	Preset string    `json:"preset,omitempty"`
+	Table  []float64 `json:"table,omitempty"`
+}
+
+// LoadConfig reads the project config. A missing file yields the defaults.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if _, err := cfg.PenaltyModel(); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if _, err := cfg.EvidenceAggregation.Aggregation(); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if _, err := cfg.EvidenceDecay.Decay(); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// PenaltyModel resolves the configured congruence penalty function
+func (c Config) PenaltyModel() (PenaltyModel, error) {
+	if c.CongruencePenalty.Preset == "custom" {
+		return CustomPenalty(c.CongruencePenalty.Table)
+	}
+
+	model, ok := PenaltyPreset(c.CongruencePenalty.Preset)
+	if !ok {
+		return PenaltyModel{}, fmt.Errorf("unknown congruence penalty preset %q (use linear, fpf or custom)", c.CongruencePenalty.Preset)
+	}
+	return model, nil
+}
+
+// NewFromConfig creates a Calculator using the project's assurance settings
+func NewFromConfig(db *sql.DB, cfg Config) (*Calculator, error) {
+	penalty, err := cfg.PenaltyModel()
+	if err != nil {
+		return nil, err
+	}
+
+	aggregation, err := cfg.EvidenceAggregation.Aggregation()
+	if err != nil {
+		return nil, err
+	}
+
+	decay, err := cfg.EvidenceDecay.Decay()
+	if err != nil {
+		return nil, err
+	}
+
+	calc := New(db)
+	calc.Penalty = penalty
+	calc.Aggregation = aggregation
+	calc.Decay = decay
+	return calc, nil
+}
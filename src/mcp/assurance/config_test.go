@@ -0,0 +1,90 @@
+package assurance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig_MissingFileUsesDefaults(t *testing.T) {
+	cfg, err := LoadConfig(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	model, err := cfg.PenaltyModel()
+	if err != nil {
+		t.Fatalf("PenaltyModel failed: %v", err)
+	}
+	if model.Name != LinearPenalty.Name {
+		t.Errorf("Expected linear preset by default, got %s", model.Name)
+	}
+}
+
+func TestLoadConfig_Presets(t *testing.T) {
+	tests := []struct {
+		json     string
+		expected string
+		wantErr  bool
+	}{
+		{`{"congruence_penalty": {"preset": "fpf"}}`, "fpf", false},
+		{`{"congruence_penalty": {"preset": "linear"}}`, "linear", false},
+		{`{"congruence_penalty": {"preset": "custom", "table": [1, 0.6, 0.2, 0]}}`, "custom(CL0=1.00, CL1=0.60, CL2=0.20, CL3=0.00)", false},
+		{`{"congruence_penalty": {"preset": "custom", "table": [1, 0.6]}}`, "", true},
+		{`{"congruence_penalty": {"preset": "custom", "table": [1, 0.6, 0.2, -1]}}`, "", true},
+		{`{"congruence_penalty": {"preset": "quadratic"}}`, "", true},
+		{`not json`, "", true},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, []byte(tt.json), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+
+		cfg, err := LoadConfig(path)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Expected error for %s", tt.json)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("LoadConfig(%s) failed: %v", tt.json, err)
+			continue
+		}
+
+		model, _ := cfg.PenaltyModel()
+		if model.String() != tt.expected {
+			t.Errorf("Expected model %s, got %s", tt.expected, model.String())
+		}
+	}
+}
+
+func TestCalculateReliability_FPFPenaltyPreset(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e1', 'A', 'pass', ?)", time.Now().Add(24*time.Hour))
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e2', 'B', 'pass', ?)", time.Now().Add(24*time.Hour))
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('B', 'A', 'componentOf', 2)")
+
+	calc, err := NewFromConfig(db, Config{CongruencePenalty: PenaltyConfig{Preset: "fpf"}})
+	if err != nil {
+		t.Fatalf("NewFromConfig failed: %v", err)
+	}
+	report, err := calc.CalculateReliability(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+
+	// FPF B.1.3: CL2 costs 0.5
+	if report.FinalScore != 0.5 {
+		t.Errorf("Expected score 0.5 with fpf preset, got %f", report.FinalScore)
+	}
+	if report.PenaltyModel != "fpf" {
+		t.Errorf("Expected report to record penalty model fpf, got %q", report.PenaltyModel)
+	}
+}
@@ -0,0 +1,145 @@
+package assurance
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Decay curves: how an expired piece of evidence loses its score
+const (
+	DecayStep        = "step"        // full score until valid_until, the floor after
+	DecayLinear      = "linear"      // from full score at valid_until down to the floor over Days
+	DecayExponential = "exponential" // halves the distance to the floor every HalfLifeDays
+)
+
+// DefaultDecayFloor is the score expired evidence decays towards: not zero,
+// since stale evidence is unverified rather than disproven
+const DefaultDecayFloor = 0.1
+
+// DecayCurveConfig selects one decay curve
+type DecayCurveConfig struct {
+	Curve        string   `json:"curve,omitempty"`
+	Days         float64  `json:"days,omitempty"`           // linear: days from valid_until to the floor
+	HalfLifeDays float64  `json:"half_life_days,omitempty"` // exponential
+	Floor        *float64 `json:"floor,omitempty"`
+}
+
+// DecayConfig selects the decay curve per evidence type. Types without an
+// entry use Default, which is the step curve unless configured.
+type DecayConfig struct {
+	Default DecayCurveConfig            `json:"default"`
+	Types   map[string]DecayCurveConfig `json:"types,omitempty"`
+}
+
+// DecayCurve maps time past valid_until to the share of the score that remains
+type DecayCurve struct {
+	Name         string
+	Days         float64
+	HalfLifeDays float64
+	Floor        float64
+}
+
+// StepDecay is the original quint-code behavior: expired evidence scores 0.1
+var StepDecay = DecayCurve{Name: DecayStep, Floor: DefaultDecayFloor}
+
+// Decay is the resolved decay configuration
+type Decay struct {
+	Default DecayCurve
+	Types   map[string]DecayCurve
+}
+
+// DefaultDecay applies the step curve to all evidence
+var DefaultDecay = Decay{Default: StepDecay}
+
+// DecayCurve resolves one curve configuration
+func (c DecayCurveConfig) DecayCurve() (DecayCurve, error) {
+	curve := DecayCurve{Name: c.Curve, Days: c.Days, HalfLifeDays: c.HalfLifeDays, Floor: DefaultDecayFloor}
+	if c.Floor != nil {
+		if *c.Floor < 0 || *c.Floor > 1 {
+			return DecayCurve{}, fmt.Errorf("decay floor must be between 0 and 1, got %.2f", *c.Floor)
+		}
+		curve.Floor = *c.Floor
+	}
+
+	switch curve.Name {
+	case "", DecayStep:
+		curve.Name = DecayStep
+	case DecayLinear:
+		if curve.Days <= 0 {
+			return DecayCurve{}, fmt.Errorf("linear decay needs days > 0")
+		}
+	case DecayExponential:
+		if curve.HalfLifeDays <= 0 {
+			return DecayCurve{}, fmt.Errorf("exponential decay needs half_life_days > 0")
+		}
+	default:
+		return DecayCurve{}, fmt.Errorf("unknown decay curve %q (use step, linear or exponential)", c.Curve)
+	}
+	return curve, nil
+}
+
+// Decay resolves the per-type decay configuration
+func (c DecayConfig) Decay() (Decay, error) {
+	def, err := c.Default.DecayCurve()
+	if err != nil {
+		return Decay{}, err
+	}
+
+	decay := Decay{Default: def, Types: make(map[string]DecayCurve, len(c.Types))}
+	for evidenceType, cfg := range c.Types {
+		curve, err := cfg.DecayCurve()
+		if err != nil {
+			return Decay{}, fmt.Errorf("decay for %s: %w", evidenceType, err)
+		}
+		decay.Types[strings.ToLower(evidenceType)] = curve
+	}
+	return decay, nil
+}
+
+// For returns the curve applied to an evidence type
+func (d Decay) For(evidenceType string) DecayCurve {
+	if curve, ok := d.Types[strings.ToLower(evidenceType)]; ok {
+		return curve
+	}
+	if d.Default.Name == "" {
+		return StepDecay
+	}
+	return d.Default
+}
+
+// Remaining returns the share of the score left after overdue time past valid_until
+func (c DecayCurve) Remaining(overdue time.Duration) float64 {
+	if overdue <= 0 {
+		return 1
+	}
+	days := overdue.Hours() / 24
+
+	switch c.Name {
+	case DecayLinear:
+		return math.Max(0, 1-days/c.Days)
+	case DecayExponential:
+		return math.Pow(0.5, days/c.HalfLifeDays)
+	default:
+		return 0
+	}
+}
+
+// Apply decays a verdict score towards the floor
+func (c DecayCurve) Apply(score float64, overdue time.Duration) float64 {
+	remaining := c.Remaining(overdue)
+	return score*remaining + c.Floor*(1-remaining)
+}
+
+// String identifies the curve in reports
+func (c DecayCurve) String() string {
+	switch c.Name {
+	case DecayLinear:
+		return fmt.Sprintf("linear(%gd → %.2f)", c.Days, c.Floor)
+	case DecayExponential:
+		return fmt.Sprintf("exponential(half-life %gd → %.2f)", c.HalfLifeDays, c.Floor)
+	default:
+		return fmt.Sprintf("step(→ %.2f)", c.Floor)
+	}
+}
@@ -0,0 +1,112 @@
+package assurance
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecayCurve_Apply(t *testing.T) {
+	day := 24 * time.Hour
+	linear, _ := DecayCurveConfig{Curve: "linear", Days: 10}.DecayCurve()
+	exponential, _ := DecayCurveConfig{Curve: "exponential", HalfLifeDays: 5}.DecayCurve()
+
+	tests := []struct {
+		curve    DecayCurve
+		overdue  time.Duration
+		expected float64
+	}{
+		{StepDecay, 0, 1.0},
+		{StepDecay, day, 0.1},
+		{linear, 5 * day, 0.55},
+		{linear, 20 * day, 0.1},
+		{exponential, 5 * day, 0.55},
+		{exponential, 10 * day, 0.325},
+	}
+	for _, tt := range tests {
+		if got := tt.curve.Apply(1.0, tt.overdue); math.Abs(got-tt.expected) > 1e-9 {
+			t.Errorf("%s after %v: expected %.3f, got %.3f", tt.curve, tt.overdue, tt.expected, got)
+		}
+	}
+}
+
+func TestDecayConfig_Invalid(t *testing.T) {
+	negative := -0.5
+	for _, cfg := range []DecayConfig{
+		{Default: DecayCurveConfig{Curve: "cosine"}},
+		{Default: DecayCurveConfig{Curve: "linear"}},
+		{Types: map[string]DecayCurveConfig{"test": {Curve: "exponential"}}},
+		{Default: DecayCurveConfig{Floor: &negative}},
+	} {
+		if _, err := cfg.Decay(); err == nil {
+			t.Errorf("Expected %+v to be rejected", cfg)
+		}
+	}
+}
+
+func TestCalculateAssurance_ProjectsDecay(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	// Research expires in 5 days and halves every 10; the test has no expiry
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, type, verdict, valid_until) VALUES ('paper', 'A', 'research', 'pass', ?)", time.Now().Add(5*24*time.Hour))
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, type, verdict) VALUES ('bench', 'A', 'test', 'pass')")
+
+	calc, err := NewFromConfig(db, Config{EvidenceDecay: DecayConfig{
+		Types: map[string]DecayCurveConfig{"research": {Curve: "exponential", HalfLifeDays: 10}},
+	}})
+	if err != nil {
+		t.Fatalf("NewFromConfig failed: %v", err)
+	}
+
+	report, err := calc.CalculateAssurance(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateAssurance failed: %v", err)
+	}
+	if report.FinalScore != 1.0 || len(report.Projection) != len(ProjectionHorizons) {
+		t.Fatalf("Expected R 1.0 now with %d projections, got %.2f and %+v", len(ProjectionHorizons), report.FinalScore, report.Projection)
+	}
+
+	// research weighs 0.7 against the test's 1.0; after 25 days overdue it keeps 0.5^2.5
+	paper := 0.1 + 0.9*math.Pow(0.5, 2.5)
+	expected := (0.7*paper + 1.0) / 1.7
+	if p := report.Projection[1]; p.Days != 30 || math.Abs(p.R-expected) > 1e-6 {
+		t.Errorf("Expected projected R %.4f at 30 days, got %+v", expected, p)
+	}
+	if report.Projection[0].R >= 1.0 || report.Projection[2].R >= report.Projection[1].R {
+		t.Errorf("Expected R to keep falling once the research expires, got %+v", report.Projection)
+	}
+
+	// Projections must not overwrite the cached score
+	var cached float64
+	_ = db.QueryRow("SELECT cached_r_score FROM holons WHERE id = 'A'").Scan(&cached)
+	if cached != 0 && cached != 1.0 {
+		t.Errorf("Expected the cache to hold the present R, got %.2f", cached)
+	}
+
+	// Recomputing R alone skips the projections
+	plain, err := calc.CalculateReliability(context.Background(), "A")
+	if err != nil || plain.FinalScore != 1.0 || len(plain.Projection) != 0 {
+		t.Errorf("Expected R 1.0 without projections, got %+v (err=%v)", plain, err)
+	}
+
+	day, r, ok, err := calc.ForecastDrop(context.Background(), "A", 0.8, 90)
+	if err != nil || !ok {
+		t.Fatalf("Expected a drop below 0.8 within 90 days (err=%v)", err)
+	}
+	if r >= 0.8 || day <= 5 {
+		t.Errorf("Expected the drop after expiry with R below 0.8, got day %d R %.2f", day, r)
+	}
+	if before, _ := calc.ProjectReliability(context.Background(), "A", time.Now().AddDate(0, 0, day-1)); before < 0.8 {
+		t.Errorf("Expected day %d to be the first below 0.8, R was %.2f the day before", day, before)
+	}
+
+	if _, _, ok, _ := calc.ForecastDrop(context.Background(), "A", 0.8, 5); ok {
+		t.Error("Expected no drop before the research expires")
+	}
+	if !strings.Contains(strings.Join(report.Factors, "\n"), "Evidence bench") {
+		t.Errorf("Expected per-item factors, got %v", report.Factors)
+	}
+}
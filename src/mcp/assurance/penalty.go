@@ -0,0 +1,68 @@
+package assurance
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PenaltyModel is a congruence penalty function Φ(CL), tabulated for CL0..CL3.
+// The penalty is subtracted from a dependency's R before WLNK is applied.
+type PenaltyModel struct {
+	Name  string
+	Table [4]float64
+}
+
+var (
+	// LinearPenalty is the original quint-code model: CL3=0, CL2=0.1, CL1=0.4, CL0=0.9
+	LinearPenalty = PenaltyModel{Name: "linear", Table: [4]float64{0.9, 0.4, 0.1, 0.0}}
+
+	// FPFPenalty is the normative table from FPF B.1.3: CL3=0, CL2=0.5, CL1=1.0, CL0=1.0
+	FPFPenalty = PenaltyModel{Name: "fpf", Table: [4]float64{1.0, 1.0, 0.5, 0.0}}
+)
+
+// PenaltyPreset returns a built-in penalty model by name
+func PenaltyPreset(name string) (PenaltyModel, bool) {
+	switch name {
+	case "", LinearPenalty.Name:
+		return LinearPenalty, true
+	case FPFPenalty.Name:
+		return FPFPenalty, true
+	}
+	return PenaltyModel{}, false
+}
+
+// CustomPenalty builds a penalty model from a table indexed by CL (CL0 first)
+func CustomPenalty(table []float64) (PenaltyModel, error) {
+	if len(table) != 4 {
+		return PenaltyModel{}, fmt.Errorf("custom penalty table needs 4 values (CL0..CL3), got %d", len(table))
+	}
+
+	model := PenaltyModel{Name: "custom"}
+	for cl, p := range table {
+		if p < 0 || p > 1 {
+			return PenaltyModel{}, fmt.Errorf("penalty for CL%d must be between 0 and 1, got %.2f", cl, p)
+		}
+		model.Table[cl] = p
+	}
+	return model, nil
+}
+
+// Penalty returns Φ(CL). Levels outside 0..3 are treated as CL0.
+func (m PenaltyModel) Penalty(cl int) float64 {
+	if cl < 0 || cl > 3 {
+		cl = 0
+	}
+	return m.Table[cl]
+}
+
+// String identifies the model in reports; custom tables are spelled out
+func (m PenaltyModel) String() string {
+	if m.Name != "custom" {
+		return m.Name
+	}
+	parts := make([]string, len(m.Table))
+	for cl, p := range m.Table {
+		parts[cl] = fmt.Sprintf("CL%d=%.2f", cl, p)
+	}
+	return "custom(" + strings.Join(parts, ", ") + ")"
+}
@@ -0,0 +1,123 @@
+package assurance
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// ClaimScope is the G component of the F-G-R tuple: the set of contexts a claim
+// is asserted to hold in. The zero value is unbounded (no restriction).
+type ClaimScope struct {
+	Bounded bool
+	Terms   []string
+}
+
+// ParseClaimScope reads a stored claim scope. Accepts a JSON array or a
+// comma-separated list. Empty input, "*" and "global" mean unbounded.
+func ParseClaimScope(raw string) ClaimScope {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ClaimScope{}
+	}
+
+	var items []string
+	if strings.HasPrefix(raw, "[") {
+		if err := json.Unmarshal([]byte(raw), &items); err != nil {
+			items = strings.Split(strings.Trim(raw, "[]"), ",")
+		}
+	} else {
+		items = strings.Split(raw, ",")
+	}
+
+	return NewClaimScope(items)
+}
+
+// NewClaimScope builds a bounded scope from terms, normalizing case and order.
+// A "*" or "global" term makes the whole scope unbounded.
+func NewClaimScope(terms []string) ClaimScope {
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, term := range terms {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term == "" || seen[term] {
+			continue
+		}
+		if term == "*" || term == "global" {
+			return ClaimScope{}
+		}
+		seen[term] = true
+		normalized = append(normalized, term)
+	}
+
+	if len(normalized) == 0 {
+		return ClaimScope{}
+	}
+
+	sort.Strings(normalized)
+	return ClaimScope{Bounded: true, Terms: normalized}
+}
+
+// Intersect returns the scope in which both claims hold
+func (g ClaimScope) Intersect(other ClaimScope) ClaimScope {
+	if !g.Bounded {
+		return other
+	}
+	if !other.Bounded {
+		return g
+	}
+
+	inOther := make(map[string]bool, len(other.Terms))
+	for _, term := range other.Terms {
+		inOther[term] = true
+	}
+
+	result := ClaimScope{Bounded: true}
+	for _, term := range g.Terms {
+		if inOther[term] {
+			result.Terms = append(result.Terms, term)
+		}
+	}
+	return result
+}
+
+// IsEmpty reports whether the claim holds nowhere
+func (g ClaimScope) IsEmpty() bool {
+	return g.Bounded && len(g.Terms) == 0
+}
+
+// Equal reports whether both scopes cover the same contexts
+func (g ClaimScope) Equal(other ClaimScope) bool {
+	if g.Bounded != other.Bounded || len(g.Terms) != len(other.Terms) {
+		return false
+	}
+	for i := range g.Terms {
+		if g.Terms[i] != other.Terms[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Encode returns the storage form: a JSON array, or "" when unbounded
+func (g ClaimScope) Encode() string {
+	if !g.Bounded {
+		return ""
+	}
+	terms := g.Terms
+	if terms == nil {
+		terms = []string{}
+	}
+	data, _ := json.Marshal(terms)
+	return string(data)
+}
+
+func (g ClaimScope) String() string {
+	if !g.Bounded {
+		return "*"
+	}
+	if len(g.Terms) == 0 {
+		return "∅"
+	}
+	return "{" + strings.Join(g.Terms, ", ") + "}"
+}
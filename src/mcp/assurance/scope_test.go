@@ -0,0 +1,52 @@
+package assurance
+
+import "testing"
+
+func TestParseClaimScope(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{"", "*"},
+		{"global", "*"},
+		{`["API", "eu-region", "api"]`, "{api, eu-region}"},
+		{"eu-region, api", "{api, eu-region}"},
+		{`[]`, "*"},
+	}
+
+	for _, tt := range tests {
+		if got := ParseClaimScope(tt.raw).String(); got != tt.expected {
+			t.Errorf("ParseClaimScope(%q) = %s, expected %s", tt.raw, got, tt.expected)
+		}
+	}
+}
+
+func TestClaimScope_Intersect(t *testing.T) {
+	unbounded := ClaimScope{}
+	apiEU := NewClaimScope([]string{"api", "eu"})
+	apiUS := NewClaimScope([]string{"api", "us"})
+	batch := NewClaimScope([]string{"batch"})
+
+	if got := unbounded.Intersect(apiEU); !got.Equal(apiEU) {
+		t.Errorf("unbounded ∩ X should be X, got %s", got)
+	}
+	if got := apiEU.Intersect(unbounded); !got.Equal(apiEU) {
+		t.Errorf("X ∩ unbounded should be X, got %s", got)
+	}
+	if got := apiEU.Intersect(apiUS); got.String() != "{api}" {
+		t.Errorf("Expected {api}, got %s", got)
+	}
+	if got := apiEU.Intersect(batch); !got.IsEmpty() {
+		t.Errorf("Expected empty scope for disjoint claims, got %s", got)
+	}
+}
+
+func TestClaimScope_EncodeRoundTrip(t *testing.T) {
+	g := NewClaimScope([]string{"eu", "api"})
+	if got := ParseClaimScope(g.Encode()); !got.Equal(g) {
+		t.Errorf("Round trip mismatch: %s vs %s", got, g)
+	}
+	if (ClaimScope{}).Encode() != "" {
+		t.Error("Unbounded scope should encode to empty string")
+	}
+}
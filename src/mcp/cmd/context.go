@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var contextDescription string
+
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Manage bounded contexts",
+	Long: `Manage bounded contexts (FPF A.1.1).
+
+Each context has its own holons, evidence, audit log and derived phase,
+so several independent decisions can be reasoned about in parallel.
+The active context is shared with the MCP server, which picks up a switch
+on its next call.`,
+}
+
+var contextListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List contexts and their holon counts",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runContextAction("list", "")
+	},
+}
+
+var contextCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a new context",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runContextAction("create", args[0])
+	},
+}
+
+var contextSwitchCmd = &cobra.Command{
+	Use:   "switch <id>",
+	Short: "Make a context active",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runContextAction("switch", args[0])
+	},
+}
+
+var contextArchiveCmd = &cobra.Command{
+	Use:   "archive <id>",
+	Short: "Archive an inactive context",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runContextAction("archive", args[0])
+	},
+}
+
+func init() {
+	contextCreateCmd.Flags().StringVar(&contextDescription, "description", "", "What this context is about")
+
+	contextCmd.AddCommand(contextListCmd, contextCreateCmd, contextSwitchCmd, contextArchiveCmd)
+	rootCmd.AddCommand(contextCmd)
+}
+
+func runContextAction(action, name string) error {
+	tools, database, err := openProject()
+	if err != nil {
+		return err
+	}
+	defer database.Close() //nolint:errcheck
+
+	output, err := tools.ManageContext(action, name, contextDescription)
+	if err != nil {
+		return err
+	}
+	fmt.Println(output)
+	return nil
+}
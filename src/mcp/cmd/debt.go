@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+
+	"github.com/spf13/cobra"
+)
+
+var debtOpts fpf.DebtOptions
+
+var debtCmd = &cobra.Command{
+	Use:   "debt",
+	Short: "Score the epistemic debt of the active context",
+	Long: `Score the epistemic debt of the active context and list what contributes to it:
+stale evidence, changed artifacts, L2 holons below the assurance threshold,
+waivers near expiry, unverified L0 hypotheses and alternatives a decision
+rejected without ever verifying them.
+
+Each run that changes the score is recorded in the audit log, so the report
+shows the trend since earlier measurements, per context and per holon.`,
+	Args: cobra.NoArgs,
+	RunE: runDebt,
+}
+
+func init() {
+	debtCmd.Flags().StringVar(&debtOpts.HolonID, "holon", "", "Only report this holon")
+	debtCmd.Flags().StringVar(&debtOpts.Format, "format", fpf.DebtFormatMarkdown, "Output format (markdown, json)")
+	rootCmd.AddCommand(debtCmd)
+}
+
+func runDebt(cmd *cobra.Command, args []string) error {
+	tools, database, err := openProject()
+	if err != nil {
+		return err
+	}
+	defer database.Close() //nolint:errcheck
+
+	output, err := tools.EpistemicDebt(debtOpts)
+	if err != nil {
+		return err
+	}
+	fmt.Println(output)
+	return nil
+}
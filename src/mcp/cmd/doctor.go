@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var doctorFix bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that .quint files and the database agree",
+	Long: `Cross-check the markdown projection in .quint/ against quint.db.
+
+Reports holons filed in the wrong layer, missing or orphaned files, tampered
+content hashes, evidence and DRR files without rows (and vice versa), and
+relations pointing at missing holons or evidence.
+
+With --fix, files are regenerated from the database, orphan files are imported
+as rows, and dangling relations are removed. Exits non-zero while issues remain.`,
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Repair the issues found")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	tools, database, err := openProject()
+	if err != nil {
+		return err
+	}
+	defer database.Close() //nolint:errcheck
+
+	report, err := tools.Doctor(doctorFix)
+	if err != nil {
+		return err
+	}
+	fmt.Println(report.String())
+
+	if n := report.Unresolved(); n > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d unresolved issue(s)", n)
+	}
+	return nil
+}
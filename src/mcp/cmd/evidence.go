@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	ingestHolon  string
+	ingestFormat string
+	ingestType   string
+)
+
+var evidenceCmd = &cobra.Command{
+	Use:   "evidence",
+	Short: "Record evidence from outside the MCP workflow",
+}
+
+var evidenceIngestCmd = &cobra.Command{
+	Use:   "ingest <file>",
+	Short: "Record a go test -json or JUnit report as validation evidence",
+	Long: `Parse a test report and record it as validation evidence for an L1 or L2 hypothesis.
+
+The verdict follows the pass ratio: PASS when every test passed, DEGRADE at
+80% or more, FAIL below. PASS promotes L1 to L2 and FAIL invalidates, as with
+quint_test. Each test is recorded as its own evidence item and the report is
+stored as the artifact of the suite evidence.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEvidenceIngest,
+}
+
+var evidenceRecipeCmd = &cobra.Command{
+	Use:   "recipe <evidence_id> <command>",
+	Short: "Store the command that reproduces an evidence item",
+	Long: `Store a single-line shell command that reproduces an evidence item.
+
+'quint-code revalidate' runs it once the evidence goes stale; exit status 0
+records fresh passing evidence, anything else failing evidence.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runEvidenceRecipe,
+}
+
+func init() {
+	evidenceIngestCmd.Flags().StringVar(&ingestHolon, "holon", "", "Hypothesis the tests validate")
+	evidenceIngestCmd.Flags().StringVar(&ingestFormat, "format", fpf.FormatGoTest, "Report format (gotest, junit)")
+	evidenceIngestCmd.Flags().StringVar(&ingestType, "type", "internal", "Evidence type")
+	_ = evidenceIngestCmd.MarkFlagRequired("holon")
+
+	evidenceCmd.AddCommand(evidenceIngestCmd, evidenceRecipeCmd)
+	rootCmd.AddCommand(evidenceCmd)
+}
+
+func runEvidenceIngest(cmd *cobra.Command, args []string) error {
+	path, err := filepath.Abs(args[0])
+	if err != nil {
+		return err
+	}
+
+	tools, database, err := openProject()
+	if err != nil {
+		return err
+	}
+	defer database.Close() //nolint:errcheck
+
+	if err := tools.CheckPreconditions("quint_ingest_tests", map[string]string{
+		"hypothesis_id": ingestHolon,
+		"format":        ingestFormat,
+		"path":          path,
+	}); err != nil {
+		return err
+	}
+
+	tools.FSM.State.Phase = fpf.PhaseInduction
+	if err := tools.FSM.SaveState(tools.ContextID()); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+
+	output, err := tools.IngestTestResults(ingestHolon, ingestFormat, path, ingestType)
+	if err != nil {
+		return err
+	}
+	fmt.Println(output)
+	return nil
+}
+
+func runEvidenceRecipe(cmd *cobra.Command, args []string) error {
+	tools, database, err := openProject()
+	if err != nil {
+		return err
+	}
+	defer database.Close() //nolint:errcheck
+
+	output, err := tools.SetEvidenceRecipe(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Println(output)
+	return nil
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat string
+	exportDir    string
+	importDir    string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the knowledge graph as line-based text files",
+	Long: `Write holons, evidence, relations, characteristics, waivers, loopbacks,
+work records and the audit log to .quint/export/<table>.jsonl.
+
+Each line is one record with its fields in name order, and lines are sorted by
+record ID, so exports diff cleanly and branches merge line by line. Cached R
+scores are left out; they are recomputed on import.`,
+	RunE: runExport,
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Merge exported .jsonl files into the database",
+	Long: `Read the files written by 'quint-code export' and insert every record the
+database does not have yet, together with its markdown projection.
+
+Records already present are skipped. An ID whose record differs from the
+database, or that appears with different content on several lines (as after
+merging two branches, conflict markers included), is reported and left out.
+Exits non-zero while conflicts remain.`,
+	RunE: runImport,
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", fpf.ExportFormatJSONL, "Export format (jsonl)")
+	exportCmd.Flags().StringVar(&exportDir, "dir", "", "Output directory (default .quint/export)")
+	importCmd.Flags().StringVar(&importDir, "dir", "", "Directory with the .jsonl files (default .quint/export)")
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	tools, database, err := openProject()
+	if err != nil {
+		return err
+	}
+	defer database.Close() //nolint:errcheck
+
+	dir := exportDir
+	if dir == "" {
+		dir = tools.ExportDir()
+	}
+	files, err := tools.Export(dir, exportFormat)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		fmt.Println(f)
+	}
+	return nil
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	tools, database, err := openProject()
+	if err != nil {
+		return err
+	}
+	defer database.Close() //nolint:errcheck
+
+	dir := importDir
+	if dir == "" {
+		dir = tools.ExportDir()
+	}
+	report, err := tools.Import(dir)
+	if err != nil {
+		return err
+	}
+	fmt.Println(report.String())
+
+	if n := len(report.Conflicts); n > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d conflicting record(s)", n)
+	}
+	return nil
+}
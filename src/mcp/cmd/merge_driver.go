@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+
+	"github.com/spf13/cobra"
+)
+
+var mergeDriverInstall bool
+
+var mergeDriverCmd = &cobra.Command{
+	Use:   "merge-driver <base> <ours> <theirs> [path]",
+	Short: "Git merge driver for .quint knowledge files",
+	Long: `Merge two versions of a hypothesis, DRR, evidence or waiver file against
+their common ancestor, as a git merge driver.
+
+Frontmatter is merged field by field: a field changed on one side takes that
+change, and depends_on, decision_context and rejected_ids combine both sides.
+The body is merged line by line and content_hash is recomputed, so the result
+is not flagged as tampered. The merge is recorded in the audit log.
+
+Fields or lines changed differently on both sides are left with conflict
+markers and without content_hash; the driver then exits non-zero.
+
+Register it once per clone with --install, which runs:
+
+  git config merge.quint.driver "quint-code merge-driver %O %A %B %P"
+
+and adds to .gitattributes:
+
+  .quint/knowledge/**/*.md merge=quint
+  .quint/decisions/*.md merge=quint
+  .quint/evidence/*.md merge=quint
+  .quint/waivers/*.md merge=quint`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if mergeDriverInstall {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.RangeArgs(3, 4)(cmd, args)
+	},
+	RunE: runMergeDriver,
+}
+
+func init() {
+	mergeDriverCmd.Flags().BoolVar(&mergeDriverInstall, "install", false, "Register the driver in git config and .gitattributes")
+	rootCmd.AddCommand(mergeDriverCmd)
+}
+
+func runMergeDriver(cmd *cobra.Command, args []string) error {
+	root, err := resolveProjectRoot()
+	if err != nil {
+		return err
+	}
+	if mergeDriverInstall {
+		if err := fpf.InstallMergeDriver(root); err != nil {
+			return err
+		}
+		fmt.Println("Registered the quint merge driver for .quint knowledge files.")
+		return nil
+	}
+
+	// The audit log is optional: a clone without quint.db can still merge
+	tools, database, err := openProject()
+	if err != nil {
+		tools = &fpf.Tools{RootDir: root}
+	} else {
+		defer database.Close() //nolint:errcheck
+	}
+
+	name := args[1]
+	if len(args) == 4 {
+		name = args[3]
+	}
+	result, err := tools.MergeFile(args[0], args[1], args[2], name)
+	if err != nil {
+		return err
+	}
+
+	if len(result.Conflicts) > 0 {
+		cmd.SilenceUsage = true
+		fmt.Fprintf(os.Stderr, "quint: conflicts in %s: %s\n", name, strings.Join(result.Conflicts, ", "))
+		return fmt.Errorf("%d conflict(s) left in %s", len(result.Conflicts), name)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/m0n0x41d/quint-code/db"
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+)
+
+// resolveProjectRoot returns QUINT_PROJECT_ROOT if set, otherwise the working directory
+func resolveProjectRoot() (string, error) {
+	cwd := os.Getenv("QUINT_PROJECT_ROOT")
+	if cwd != "" {
+		return cwd, nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return cwd, nil
+}
+
+// openProject opens an initialized project's database and loads the FSM
+// for its active context. Callers must Close the returned store.
+func openProject() (*fpf.Tools, *db.Store, error) {
+	root, err := resolveProjectRoot()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dbPath := filepath.Join(root, ".quint", "quint.db")
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, nil, fmt.Errorf("no quint database at %s (run 'quint-code init' first)", dbPath)
+	}
+
+	database, err := db.NewStore(dbPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	fsm, err := fpf.LoadState(fpf.ActiveContextID(database.GetRawDB()), database.GetRawDB())
+	if err != nil {
+		_ = database.Close()
+		return nil, nil, fmt.Errorf("failed to load state: %w", err)
+	}
+
+	return fpf.NewTools(fsm, root, database), database, nil
+}
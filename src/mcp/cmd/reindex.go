@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild quint.db from the files in .quint/",
+	Long: `Rebuild holons, evidence, relations and waivers from the markdown projection.
+
+quint.db is usually git-ignored while .quint/knowledge, .quint/decisions,
+.quint/evidence and .quint/waivers are committed. After a fresh clone, run
+reindex to restore the knowledge base and recompute R scores. Existing rows
+are replaced; files that cannot be imported are listed and skipped.`,
+	RunE: runReindex,
+}
+
+func init() {
+	rootCmd.AddCommand(reindexCmd)
+}
+
+func runReindex(cmd *cobra.Command, args []string) error {
+	tools, database, err := openProject()
+	if err != nil {
+		return err
+	}
+	defer database.Close() //nolint:errcheck
+
+	report, err := tools.Reindex()
+	if err != nil {
+		return err
+	}
+	fmt.Println(report.String())
+
+	if n := len(report.Failures); n > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d file(s) not imported", n)
+	}
+	return nil
+}
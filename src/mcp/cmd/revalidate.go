@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/m0n0x41d/quint-code/db"
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+
+	"github.com/spf13/cobra"
+)
+
+var revalidateOpts fpf.RevalidateOptions
+
+var revalidateCmd = &cobra.Command{
+	Use:   "revalidate",
+	Short: "Re-run the stored recipes of stale evidence",
+	Long: `Re-run the recipes of evidence that expired (and is not waived) or whose
+artifact changed, and record the outcome as fresh evidence.
+
+Each recipe is a shell command run in its own clean git worktree of HEAD under
+a timeout, so it sees committed code only and leaves the working tree alone.
+The worktree is not a sandbox: a recipe runs with your permissions. Only
+recipes set with "quint-code evidence recipe" or approved here before run
+unasked; any other recipe (stored by an agent through quint_test, or imported
+from committed evidence files by reindex or doctor --fix) is shown and needs
+confirmation, or --yes.
+
+Exit status 0 records passing evidence with a new valid_until, anything else
+failing evidence; the output is stored as a log artifact. The report lists
+which holons recovered and which degraded.`,
+	Args: cobra.NoArgs,
+	RunE: runRevalidate,
+}
+
+func init() {
+	revalidateCmd.Flags().StringVar(&revalidateOpts.HolonID, "holon", "", "Only re-validate this holon")
+	revalidateCmd.Flags().DurationVar(&revalidateOpts.Timeout, "timeout", fpf.DefaultRecipeTimeout, "Timeout per recipe")
+	revalidateCmd.Flags().BoolVar(&revalidateOpts.InPlace, "in-place", false, "Run recipes in the project root instead of a clean worktree")
+	revalidateCmd.Flags().BoolVar(&revalidateOpts.DryRun, "dry-run", false, "List the recipes that would run")
+	revalidateCmd.Flags().BoolVarP(&revalidateOpts.Yes, "yes", "y", false, "Run recipes nobody approved on this machine without asking")
+	rootCmd.AddCommand(revalidateCmd)
+}
+
+func runRevalidate(cmd *cobra.Command, args []string) error {
+	tools, database, err := openProject()
+	if err != nil {
+		return err
+	}
+	defer database.Close() //nolint:errcheck
+
+	stdin := bufio.NewReader(os.Stdin)
+	revalidateOpts.Confirm = func(e db.Evidence) bool {
+		fmt.Fprintf(os.Stderr, "Recipe of %s (%s) was not approved on this machine:\n  %s\nRun it? [y/N] ", e.ID, e.HolonID, e.Recipe.String)
+		answer, _ := stdin.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return answer == "y" || answer == "yes"
+	}
+
+	output, err := tools.Revalidate(revalidateOpts)
+	if err != nil {
+		return err
+	}
+	fmt.Println(output)
+	return nil
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+
+	"github.com/spf13/cobra"
+)
+
+var searchOpts fpf.SearchOptions
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Full-text search over the knowledge base",
+	Long: `Search hypotheses, decisions (DRRs) and evidence of the active context.
+
+All words must match; append * for prefix search. Results are ranked by
+relevance and can be narrowed by layer, kind, scope and minimum cached R_eff.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().StringVar(&searchOpts.Layer, "layer", "", "Only holons in this layer (L0, L1, L2, invalid, DRR)")
+	searchCmd.Flags().StringVar(&searchOpts.Kind, "kind", "", "Only holons of this kind (system, episteme)")
+	searchCmd.Flags().StringVar(&searchOpts.Scope, "scope", "", "Only holons whose scope contains this text")
+	searchCmd.Flags().Float64Var(&searchOpts.MinR, "min-r", 0, "Minimum cached R_eff")
+	searchCmd.Flags().IntVar(&searchOpts.Limit, "limit", 20, "Maximum number of results")
+	rootCmd.AddCommand(searchCmd)
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	tools, database, err := openProject()
+	if err != nil {
+		return err
+	}
+	defer database.Close() //nolint:errcheck
+
+	output, err := tools.Search(strings.Join(args, " "), searchOpts)
+	if err != nil {
+		return err
+	}
+	fmt.Println(output)
+	return nil
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"database/sql"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -18,7 +19,15 @@ var serveCmd = &cobra.Command{
 	Long: `Start the Model Context Protocol (MCP) server for AI tool integration.
 
 The server communicates via stdio and provides FPF tools to AI assistants
-like Claude Code, Cursor, Gemini CLI, and Codex CLI.
+like Claude Code, Cursor, Gemini CLI, and Codex CLI. The q0-q5 slash
+commands are also served as MCP prompts, so no per-platform install is needed.
+
+With --http the server instead listens for MCP Streamable HTTP at /mcp, so
+several agents and dashboards can share one long-lived server per repository.
+Each client gets its own session; tool calls are serialized. The endpoint has
+no authentication: a bare port such as --http :8080 binds to 127.0.0.1 only,
+and only loopback names or the bind host are accepted in Host and Origin.
+Sessions share the active context, so it cannot be switched over HTTP.
 
 The project root is determined by:
   1. QUINT_PROJECT_ROOT environment variable (if set)
@@ -26,18 +35,17 @@ The project root is determined by:
 	RunE: runServe,
 }
 
+var serveHTTPAddr string
+
 func init() {
+	serveCmd.Flags().StringVar(&serveHTTPAddr, "http", "", "Serve MCP Streamable HTTP on this address (e.g. :8080, bound to 127.0.0.1) instead of stdio")
 	rootCmd.AddCommand(serveCmd)
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
-	cwd := os.Getenv("QUINT_PROJECT_ROOT")
-	if cwd == "" {
-		var err error
-		cwd, err = os.Getwd()
-		if err != nil {
-			return fmt.Errorf("failed to get working directory: %w", err)
-		}
+	cwd, err := resolveProjectRoot()
+	if err != nil {
+		return err
 	}
 
 	quintDir := filepath.Join(cwd, ".quint")
@@ -56,14 +64,28 @@ func runServe(cmd *cobra.Command, args []string) error {
 		rawDB = database.GetRawDB()
 	}
 
-	fsm, err := fpf.LoadState("default", rawDB)
+	fsm, err := fpf.LoadState(fpf.ActiveContextID(rawDB), rawDB)
 	if err != nil {
 		return fmt.Errorf("failed to load state: %w", err)
 	}
 
 	tools := fpf.NewTools(fsm, cwd, database)
 	server := fpf.NewServer(tools)
-	server.Start()
 
-	return nil
+	commands, err := fs.Sub(embeddedCommands, "commands")
+	if err != nil {
+		return fmt.Errorf("failed to read embedded commands: %w", err)
+	}
+	prompts, err := fpf.LoadPrompts(commands)
+	if err != nil {
+		return err
+	}
+	server.SetPrompts(prompts)
+
+	if serveHTTPAddr != "" {
+		addr := fpf.HTTPListenAddr(serveHTTPAddr)
+		fmt.Fprintf(os.Stderr, "quint-code MCP server listening on http://%s%s\n", addr, fpf.MCPEndpoint)
+		return server.ListenHTTP(addr)
+	}
+	return server.Start()
 }
@@ -0,0 +1,186 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Export and import are hand-written rather than generated: they walk every
+// table of the knowledge graph generically, so a new column is carried along
+// without a new pair of queries.
+
+// ExportTable is a table serialized by export, with the columns that identify a row
+type ExportTable struct {
+	Name string
+	Key  []string
+}
+
+// ExportTables lists the exported tables in import order: rows referenced by
+// other tables come first.
+var ExportTables = []ExportTable{
+	{Name: "contexts", Key: []string{"id"}},
+	{Name: "holons", Key: []string{"id"}},
+	{Name: "evidence", Key: []string{"id"}},
+	{Name: "relations", Key: []string{"source_id", "target_id", "relation_type"}},
+	{Name: "characteristics", Key: []string{"id"}},
+	{Name: "waivers", Key: []string{"id"}},
+	{Name: "loopbacks", Key: []string{"id"}},
+	{Name: "work_records", Key: []string{"id"}},
+	{Name: "audit_log", Key: []string{"id"}},
+}
+
+// derivedColumns are left out of exports. Recomputing R rewrites both, so
+// keeping them would turn every recalculation into a diff.
+var derivedColumns = map[string]bool{
+	"holons.cached_r_score": true,
+	"holons.updated_at":     true,
+}
+
+// Row is one exported record, keyed by column name. Times are RFC3339 strings
+// in UTC, so that encoding a Row is deterministic.
+type Row map[string]any
+
+// KeyOf returns the identity of a row within its table
+func (t ExportTable) KeyOf(row Row) string {
+	parts := make([]string, len(t.Key))
+	for i, col := range t.Key {
+		parts[i] = fmt.Sprint(row[col])
+	}
+	return strings.Join(parts, " ")
+}
+
+// ExportRows returns every row of a table, ordered by its key
+func (s *Store) ExportRows(ctx context.Context, table ExportTable) ([]Row, error) {
+	query := fmt.Sprintf("SELECT * FROM %s ORDER BY %s", table.Name, strings.Join(table.Key, ", "))
+	return s.queryRows(ctx, table.Name, query)
+}
+
+// GetRow returns the row of a table with the same key as row, or nil when there is none
+func (s *Store) GetRow(ctx context.Context, table ExportTable, row Row) (Row, error) {
+	conds := make([]string, len(table.Key))
+	args := make([]any, len(table.Key))
+	for i, col := range table.Key {
+		conds[i] = col + " = ?"
+		args[i] = row[col]
+	}
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s LIMIT 1", table.Name, strings.Join(conds, " AND "))
+	rows, err := s.queryRows(ctx, table.Name, query, args...)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	return rows[0], nil
+}
+
+// InsertRow inserts an exported row. Columns the table does not have are an
+// error; DATETIME columns are parsed back from their RFC3339 form.
+func (s *Store) InsertRow(ctx context.Context, table ExportTable, row Row) error {
+	types, err := s.columnTypes(ctx, table.Name)
+	if err != nil {
+		return err
+	}
+
+	cols := make([]string, 0, len(row))
+	for col := range row {
+		if _, ok := types[col]; !ok {
+			return fmt.Errorf("%s has no column %q", table.Name, col)
+		}
+		cols = append(cols, col)
+	}
+
+	args := make([]any, len(cols))
+	for i, col := range cols {
+		v := row[col]
+		switch val := v.(type) {
+		case json.Number:
+			if n, err := val.Int64(); err == nil {
+				v = n
+			} else if f, err := val.Float64(); err == nil {
+				v = f
+			}
+		case string:
+			if types[col] == "DATETIME" {
+				if t, err := time.Parse(time.RFC3339Nano, val); err == nil {
+					v = t
+				}
+			}
+		}
+		args[i] = v
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table.Name,
+		strings.Join(cols, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", "))
+	_, err = s.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (s *Store) columnTypes(ctx context.Context, table string) (map[string]string, error) {
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf("SELECT name, type FROM pragma_table_info('%s')", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := make(map[string]string)
+	for rows.Next() {
+		var name, typ string
+		if err := rows.Scan(&name, &typ); err != nil {
+			return nil, err
+		}
+		types[name] = strings.ToUpper(typ)
+	}
+	return types, rows.Err()
+}
+
+func (s *Store) queryRows(ctx context.Context, table, query string, args ...any) ([]Row, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Row
+	for rows.Next() {
+		values := make([]any, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+
+		row := make(Row, len(cols))
+		for i, col := range cols {
+			if derivedColumns[table+"."+col] {
+				continue
+			}
+			row[col] = exportValue(values[i])
+		}
+		items = append(items, row)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func exportValue(v any) any {
+	switch val := v.(type) {
+	case time.Time:
+		return val.UTC().Format(time.RFC3339Nano)
+	case []byte:
+		return string(val)
+	}
+	return v
+}
@@ -37,6 +37,134 @@ var migrations = []struct {
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)`,
 	},
+	{
+		version:     4,
+		description: "Add contexts and settings tables for multi-context support",
+		sql: `CREATE TABLE IF NOT EXISTS contexts (
+			id TEXT PRIMARY KEY,
+			title TEXT NOT NULL,
+			description TEXT,
+			status TEXT NOT NULL DEFAULT 'open' CHECK(status IN ('open', 'archived')),
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			archived_at DATETIME
+		);
+		CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		INSERT OR IGNORE INTO contexts (id, title) VALUES ('default', 'Default');
+		INSERT OR IGNORE INTO contexts (id, title) SELECT DISTINCT context_id, context_id FROM holons;
+		INSERT OR IGNORE INTO settings (key, value) VALUES ('active_context', 'default')`,
+	},
+	{
+		version:     5,
+		description: "Add formality (F) to holons for the F-G-R assurance tuple",
+		sql:         `ALTER TABLE holons ADD COLUMN formality INTEGER DEFAULT 0 CHECK(formality BETWEEN 0 AND 9)`,
+	},
+	{
+		version:     6,
+		description: "Add claim_scope (G) to holons for the F-G-R assurance tuple",
+		sql:         `ALTER TABLE holons ADD COLUMN claim_scope TEXT`,
+	},
+	{
+		version:     7,
+		description: "Add formality (F) to evidence",
+		sql:         `ALTER TABLE evidence ADD COLUMN formality INTEGER CHECK(formality BETWEEN 0 AND 9)`,
+	},
+	{
+		version:     8,
+		description: "Add FTS5 search index over holons, DRRs and evidence",
+		sql: `CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts5(
+			doc_id UNINDEXED,
+			doc_type UNINDEXED,
+			holon_id UNINDEXED,
+			title,
+			content,
+			tokenize = 'porter unicode61'
+		);
+		DELETE FROM search_index;
+		INSERT INTO search_index (doc_id, doc_type, holon_id, title, content)
+			SELECT id, CASE WHEN type = 'DRR' THEN 'decision' ELSE 'holon' END, id, title, content FROM holons;
+		INSERT INTO search_index (doc_id, doc_type, holon_id, title, content)
+			SELECT id, 'evidence', holon_id, type, content FROM evidence`,
+	},
+	{
+		version:     9,
+		description: "Keep search_index in sync with holons and evidence",
+		sql: `CREATE TRIGGER IF NOT EXISTS search_holons_ai AFTER INSERT ON holons BEGIN
+			INSERT INTO search_index (doc_id, doc_type, holon_id, title, content)
+			VALUES (new.id, CASE WHEN new.type = 'DRR' THEN 'decision' ELSE 'holon' END, new.id, new.title, new.content);
+		END;
+		CREATE TRIGGER IF NOT EXISTS search_holons_au AFTER UPDATE OF type, title, content ON holons BEGIN
+			DELETE FROM search_index WHERE doc_id = old.id AND doc_type IN ('holon', 'decision');
+			INSERT INTO search_index (doc_id, doc_type, holon_id, title, content)
+			VALUES (new.id, CASE WHEN new.type = 'DRR' THEN 'decision' ELSE 'holon' END, new.id, new.title, new.content);
+		END;
+		CREATE TRIGGER IF NOT EXISTS search_holons_ad AFTER DELETE ON holons BEGIN
+			DELETE FROM search_index WHERE doc_id = old.id AND doc_type IN ('holon', 'decision');
+		END;
+		CREATE TRIGGER IF NOT EXISTS search_evidence_ai AFTER INSERT ON evidence BEGIN
+			INSERT INTO search_index (doc_id, doc_type, holon_id, title, content)
+			VALUES (new.id, 'evidence', new.holon_id, new.type, new.content);
+		END;
+		CREATE TRIGGER IF NOT EXISTS search_evidence_au AFTER UPDATE OF holon_id, type, content ON evidence BEGIN
+			DELETE FROM search_index WHERE doc_id = old.id AND doc_type = 'evidence';
+			INSERT INTO search_index (doc_id, doc_type, holon_id, title, content)
+			VALUES (new.id, 'evidence', new.holon_id, new.type, new.content);
+		END;
+		CREATE TRIGGER IF NOT EXISTS search_evidence_ad AFTER DELETE ON evidence BEGIN
+			DELETE FROM search_index WHERE doc_id = old.id AND doc_type = 'evidence';
+		END`,
+	},
+	{
+		version:     10,
+		description: "Add loopbacks table to record refinement lineage",
+		sql: `CREATE TABLE IF NOT EXISTS loopbacks (
+			id TEXT PRIMARY KEY,
+			parent_id TEXT NOT NULL,
+			child_id TEXT NOT NULL,
+			insight TEXT NOT NULL,
+			phase TEXT NOT NULL,
+			context_id TEXT NOT NULL DEFAULT 'default',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE INDEX IF NOT EXISTS idx_loopbacks_child ON loopbacks(child_id)`,
+	},
+	{
+		version:     11,
+		description: "Add revoked_at to waivers so a waiver can be withdrawn before it expires",
+		sql:         `ALTER TABLE waivers ADD COLUMN revoked_at DATETIME`,
+	},
+	{
+		version:     12,
+		description: "Add artifact reference and content hash to evidence",
+		sql: `ALTER TABLE evidence ADD COLUMN artifact_kind TEXT;
+		ALTER TABLE evidence ADD COLUMN artifact_ref TEXT;
+		ALTER TABLE evidence ADD COLUMN artifact_hash TEXT`,
+	},
+	{
+		version:     13,
+		description: "Add a reproducible command (recipe) to evidence for re-validation",
+		sql:         `ALTER TABLE evidence ADD COLUMN recipe TEXT`,
+	},
+	{
+		version:     14,
+		description: "Add context_snapshots for context drift detection in actualize",
+		sql: `CREATE TABLE IF NOT EXISTS context_snapshots (
+			context_id TEXT NOT NULL,
+			source TEXT NOT NULL,
+			content TEXT NOT NULL,
+			content_hash TEXT NOT NULL,
+			taken_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (context_id, source)
+		)`,
+	},
+	{
+		version:     15,
+		description: "Add the original source path of a file artifact so staleness re-hashes the source, not the copy",
+		sql:         `ALTER TABLE evidence ADD COLUMN artifact_source TEXT`,
+	},
 }
 
 // RunMigrations applies all pending migrations to the database.
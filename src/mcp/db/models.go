@@ -32,6 +32,23 @@ type Characteristic struct {
 	CreatedAt sql.NullTime
 }
 
+type Context struct {
+	ID          string
+	Title       string
+	Description sql.NullString
+	Status      string
+	CreatedAt   sql.NullTime
+	ArchivedAt  sql.NullTime
+}
+
+type ContextSnapshot struct {
+	ContextID   string
+	Source      string
+	Content     string
+	ContentHash string
+	TakenAt     sql.NullTime
+}
+
 type Evidence struct {
 	ID             string
 	HolonID        string
@@ -42,6 +59,12 @@ type Evidence struct {
 	CarrierRef     sql.NullString
 	ValidUntil     sql.NullTime
 	CreatedAt      sql.NullTime
+	Formality      sql.NullInt64
+	ArtifactKind   sql.NullString
+	ArtifactRef    sql.NullString
+	ArtifactHash   sql.NullString
+	ArtifactSource sql.NullString
+	Recipe         sql.NullString
 }
 
 type Holon struct {
@@ -57,6 +80,18 @@ type Holon struct {
 	CachedRScore sql.NullFloat64
 	CreatedAt    sql.NullTime
 	UpdatedAt    sql.NullTime
+	Formality    sql.NullInt64
+	ClaimScope   sql.NullString
+}
+
+type Loopback struct {
+	ID        string
+	ParentID  string
+	ChildID   string
+	Insight   string
+	Phase     string
+	ContextID string
+	CreatedAt sql.NullTime
 }
 
 type Relation struct {
@@ -67,6 +102,12 @@ type Relation struct {
 	CreatedAt       sql.NullTime
 }
 
+type Setting struct {
+	Key       string
+	Value     string
+	UpdatedAt sql.NullTime
+}
+
 type Waiver struct {
 	ID          string
 	EvidenceID  string
@@ -74,6 +115,7 @@ type Waiver struct {
 	WaivedUntil time.Time
 	Rationale   string
 	CreatedAt   sql.NullTime
+	RevokedAt   sql.NullTime
 }
 
 type WorkRecord struct {
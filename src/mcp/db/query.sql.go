@@ -45,6 +45,11 @@ const addEvidence = `-- name: AddEvidence :exec
 
 INSERT INTO evidence (id, holon_id, type, content, verdict, assurance_level, carrier_ref, valid_until, created_at)
 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+    holon_id = excluded.holon_id, type = excluded.type, content = excluded.content, verdict = excluded.verdict,
+    assurance_level = excluded.assurance_level, carrier_ref = excluded.carrier_ref, valid_until = excluded.valid_until,
+    created_at = excluded.created_at, formality = excluded.formality,
+    artifact_kind = NULL, artifact_ref = NULL, artifact_hash = NULL, artifact_source = NULL, recipe = NULL
 `
 
 type AddEvidenceParams struct {
@@ -79,6 +84,7 @@ const addRelation = `-- name: AddRelation :exec
 
 INSERT INTO relations (source_id, target_id, relation_type, created_at)
 VALUES (?, ?, ?, ?)
+ON CONFLICT(source_id, target_id, relation_type) DO NOTHING
 `
 
 type AddRelationParams struct {
@@ -99,6 +105,20 @@ func (q *Queries) AddRelation(ctx context.Context, db DBTX, arg AddRelationParam
 	return err
 }
 
+const archiveContext = `-- name: ArchiveContext :exec
+UPDATE contexts SET status = 'archived', archived_at = ? WHERE id = ?
+`
+
+type ArchiveContextParams struct {
+	ArchivedAt sql.NullTime
+	ID         string
+}
+
+func (q *Queries) ArchiveContext(ctx context.Context, db DBTX, arg ArchiveContextParams) error {
+	_, err := db.ExecContext(ctx, archiveContext, arg.ArchivedAt, arg.ID)
+	return err
+}
+
 const countHolonsByLayer = `-- name: CountHolonsByLayer :many
 SELECT layer, COUNT(*) as count FROM holons WHERE context_id = ? GROUP BY layer
 `
@@ -131,6 +151,30 @@ func (q *Queries) CountHolonsByLayer(ctx context.Context, db DBTX, contextID str
 	return items, nil
 }
 
+const createContext = `-- name: CreateContext :exec
+
+INSERT INTO contexts (id, title, description, status, created_at)
+VALUES (?, ?, ?, 'open', ?)
+`
+
+type CreateContextParams struct {
+	ID          string
+	Title       string
+	Description sql.NullString
+	CreatedAt   sql.NullTime
+}
+
+// Context queries
+func (q *Queries) CreateContext(ctx context.Context, db DBTX, arg CreateContextParams) error {
+	_, err := db.ExecContext(ctx, createContext,
+		arg.ID,
+		arg.Title,
+		arg.Description,
+		arg.CreatedAt,
+	)
+	return err
+}
+
 const createHolon = `-- name: CreateHolon :exec
 
 
@@ -172,6 +216,36 @@ func (q *Queries) CreateHolon(ctx context.Context, db DBTX, arg CreateHolonParam
 	return err
 }
 
+const createLoopback = `-- name: CreateLoopback :exec
+
+INSERT INTO loopbacks (id, parent_id, child_id, insight, phase, context_id, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateLoopbackParams struct {
+	ID        string
+	ParentID  string
+	ChildID   string
+	Insight   string
+	Phase     string
+	ContextID string
+	CreatedAt sql.NullTime
+}
+
+// Loopback queries
+func (q *Queries) CreateLoopback(ctx context.Context, db DBTX, arg CreateLoopbackParams) error {
+	_, err := db.ExecContext(ctx, createLoopback,
+		arg.ID,
+		arg.ParentID,
+		arg.ChildID,
+		arg.Insight,
+		arg.Phase,
+		arg.ContextID,
+		arg.CreatedAt,
+	)
+	return err
+}
+
 const createRelation = `-- name: CreateRelation :exec
 INSERT INTO relations (source_id, relation_type, target_id, congruence_level)
 VALUES (?, ?, ?, ?)
@@ -224,9 +298,74 @@ func (q *Queries) CreateWaiver(ctx context.Context, db DBTX, arg CreateWaiverPar
 	return err
 }
 
+const deleteAllEvidence = `-- name: DeleteAllEvidence :exec
+DELETE FROM evidence
+`
+
+func (q *Queries) DeleteAllEvidence(ctx context.Context, db DBTX) error {
+	_, err := db.ExecContext(ctx, deleteAllEvidence)
+	return err
+}
+
+const deleteAllHolons = `-- name: DeleteAllHolons :exec
+DELETE FROM holons
+`
+
+func (q *Queries) DeleteAllHolons(ctx context.Context, db DBTX) error {
+	_, err := db.ExecContext(ctx, deleteAllHolons)
+	return err
+}
+
+const deleteAllRelations = `-- name: DeleteAllRelations :exec
+DELETE FROM relations
+`
+
+func (q *Queries) DeleteAllRelations(ctx context.Context, db DBTX) error {
+	_, err := db.ExecContext(ctx, deleteAllRelations)
+	return err
+}
+
+const deleteAllWaivers = `-- name: DeleteAllWaivers :exec
+DELETE FROM waivers
+`
+
+func (q *Queries) DeleteAllWaivers(ctx context.Context, db DBTX) error {
+	_, err := db.ExecContext(ctx, deleteAllWaivers)
+	return err
+}
+
+const deleteContextSnapshot = `-- name: DeleteContextSnapshot :exec
+DELETE FROM context_snapshots WHERE context_id = ? AND source = ?
+`
+
+type DeleteContextSnapshotParams struct {
+	ContextID string
+	Source    string
+}
+
+func (q *Queries) DeleteContextSnapshot(ctx context.Context, db DBTX, arg DeleteContextSnapshotParams) error {
+	_, err := db.ExecContext(ctx, deleteContextSnapshot, arg.ContextID, arg.Source)
+	return err
+}
+
+const deleteRelation = `-- name: DeleteRelation :exec
+DELETE FROM relations WHERE source_id = ? AND target_id = ? AND relation_type = ?
+`
+
+type DeleteRelationParams struct {
+	SourceID     string
+	TargetID     string
+	RelationType string
+}
+
+func (q *Queries) DeleteRelation(ctx context.Context, db DBTX, arg DeleteRelationParams) error {
+	_, err := db.ExecContext(ctx, deleteRelation, arg.SourceID, arg.TargetID, arg.RelationType)
+	return err
+}
+
 const getActiveWaiverForEvidence = `-- name: GetActiveWaiverForEvidence :one
-SELECT id, evidence_id, waived_by, waived_until, rationale, created_at FROM waivers
-WHERE evidence_id = ? AND waived_until > datetime('now')
+SELECT id, evidence_id, waived_by, waived_until, rationale, created_at, revoked_at FROM waivers
+WHERE evidence_id = ? AND waived_until > datetime('now') AND revoked_at IS NULL
 ORDER BY waived_until DESC LIMIT 1
 `
 
@@ -240,12 +379,13 @@ func (q *Queries) GetActiveWaiverForEvidence(ctx context.Context, db DBTX, evide
 		&i.WaivedUntil,
 		&i.Rationale,
 		&i.CreatedAt,
+		&i.RevokedAt,
 	)
 	return i, err
 }
 
 const getAllActiveWaivers = `-- name: GetAllActiveWaivers :many
-SELECT id, evidence_id, waived_by, waived_until, rationale, created_at FROM waivers WHERE waived_until > datetime('now') ORDER BY waived_until ASC
+SELECT id, evidence_id, waived_by, waived_until, rationale, created_at, revoked_at FROM waivers WHERE waived_until > datetime('now') AND revoked_at IS NULL ORDER BY waived_until ASC
 `
 
 func (q *Queries) GetAllActiveWaivers(ctx context.Context, db DBTX) ([]Waiver, error) {
@@ -264,6 +404,7 @@ func (q *Queries) GetAllActiveWaivers(ctx context.Context, db DBTX) ([]Waiver, e
 			&i.WaivedUntil,
 			&i.Rationale,
 			&i.CreatedAt,
+			&i.RevokedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -355,7 +496,7 @@ func (q *Queries) GetAuditLogByTarget(ctx context.Context, db DBTX, targetID sql
 }
 
 const getCharacteristics = `-- name: GetCharacteristics :many
-SELECT id, holon_id, name, scale, value, unit, created_at FROM characteristics WHERE holon_id = ?
+SELECT id, holon_id, name, scale, value, unit, created_at FROM characteristics WHERE holon_id = ? ORDER BY created_at ASC, id ASC
 `
 
 func (q *Queries) GetCharacteristics(ctx context.Context, db DBTX, holonID string) ([]Characteristic, error) {
@@ -456,6 +597,24 @@ func (q *Queries) GetComponentsOf(ctx context.Context, db DBTX, targetID string)
 	return items, nil
 }
 
+const getContext = `-- name: GetContext :one
+SELECT id, title, description, status, created_at, archived_at FROM contexts WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetContext(ctx context.Context, db DBTX, id string) (Context, error) {
+	row := db.QueryRowContext(ctx, getContext, id)
+	var i Context
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.CreatedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
 const getDependencies = `-- name: GetDependencies :many
 SELECT target_id, relation_type, congruence_level
 FROM relations
@@ -527,7 +686,7 @@ func (q *Queries) GetDependents(ctx context.Context, db DBTX, targetID string) (
 }
 
 const getEvidenceByHolon = `-- name: GetEvidenceByHolon :many
-SELECT id, holon_id, type, content, verdict, assurance_level, carrier_ref, valid_until, created_at FROM evidence WHERE holon_id = ? ORDER BY created_at DESC
+SELECT id, holon_id, type, content, verdict, assurance_level, carrier_ref, valid_until, created_at, formality, artifact_kind, artifact_ref, artifact_hash, artifact_source, recipe FROM evidence WHERE holon_id = ? ORDER BY created_at DESC
 `
 
 func (q *Queries) GetEvidenceByHolon(ctx context.Context, db DBTX, holonID string) ([]Evidence, error) {
@@ -549,6 +708,12 @@ func (q *Queries) GetEvidenceByHolon(ctx context.Context, db DBTX, holonID strin
 			&i.CarrierRef,
 			&i.ValidUntil,
 			&i.CreatedAt,
+			&i.Formality,
+			&i.ArtifactKind,
+			&i.ArtifactRef,
+			&i.ArtifactHash,
+			&i.ArtifactSource,
+			&i.Recipe,
 		); err != nil {
 			return nil, err
 		}
@@ -564,7 +729,7 @@ func (q *Queries) GetEvidenceByHolon(ctx context.Context, db DBTX, holonID strin
 }
 
 const getEvidenceByID = `-- name: GetEvidenceByID :one
-SELECT id, holon_id, type, content, verdict, assurance_level, carrier_ref, valid_until, created_at FROM evidence WHERE id = ? LIMIT 1
+SELECT id, holon_id, type, content, verdict, assurance_level, carrier_ref, valid_until, created_at, formality, artifact_kind, artifact_ref, artifact_hash, artifact_source, recipe FROM evidence WHERE id = ? LIMIT 1
 `
 
 func (q *Queries) GetEvidenceByID(ctx context.Context, db DBTX, id string) (Evidence, error) {
@@ -580,12 +745,18 @@ func (q *Queries) GetEvidenceByID(ctx context.Context, db DBTX, id string) (Evid
 		&i.CarrierRef,
 		&i.ValidUntil,
 		&i.CreatedAt,
+		&i.Formality,
+		&i.ArtifactKind,
+		&i.ArtifactRef,
+		&i.ArtifactHash,
+		&i.ArtifactSource,
+		&i.Recipe,
 	)
 	return i, err
 }
 
 const getEvidenceWithCarrier = `-- name: GetEvidenceWithCarrier :many
-SELECT id, holon_id, type, content, verdict, assurance_level, carrier_ref, valid_until, created_at FROM evidence WHERE carrier_ref IS NOT NULL AND carrier_ref != ''
+SELECT id, holon_id, type, content, verdict, assurance_level, carrier_ref, valid_until, created_at, formality, artifact_kind, artifact_ref, artifact_hash, artifact_source, recipe FROM evidence WHERE carrier_ref IS NOT NULL AND carrier_ref != ''
 `
 
 func (q *Queries) GetEvidenceWithCarrier(ctx context.Context, db DBTX) ([]Evidence, error) {
@@ -607,6 +778,12 @@ func (q *Queries) GetEvidenceWithCarrier(ctx context.Context, db DBTX) ([]Eviden
 			&i.CarrierRef,
 			&i.ValidUntil,
 			&i.CreatedAt,
+			&i.Formality,
+			&i.ArtifactKind,
+			&i.ArtifactRef,
+			&i.ArtifactHash,
+			&i.ArtifactSource,
+			&i.Recipe,
 		); err != nil {
 			return nil, err
 		}
@@ -622,7 +799,7 @@ func (q *Queries) GetEvidenceWithCarrier(ctx context.Context, db DBTX) ([]Eviden
 }
 
 const getHolon = `-- name: GetHolon :one
-SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at FROM holons WHERE id = ? LIMIT 1
+SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at, formality, claim_scope FROM holons WHERE id = ? LIMIT 1
 `
 
 func (q *Queries) GetHolon(ctx context.Context, db DBTX, id string) (Holon, error) {
@@ -641,6 +818,8 @@ func (q *Queries) GetHolon(ctx context.Context, db DBTX, id string) (Holon, erro
 		&i.CachedRScore,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Formality,
+		&i.ClaimScope,
 	)
 	return i, err
 }
@@ -710,6 +889,44 @@ func (q *Queries) GetHolonLineage(ctx context.Context, db DBTX, id string) ([]Ge
 	return items, nil
 }
 
+const getHolonRelations = `-- name: GetHolonRelations :many
+SELECT source_id, target_id, relation_type, congruence_level, created_at FROM relations WHERE source_id = ? OR target_id = ?
+`
+
+type GetHolonRelationsParams struct {
+	SourceID string
+	TargetID string
+}
+
+func (q *Queries) GetHolonRelations(ctx context.Context, db DBTX, arg GetHolonRelationsParams) ([]Relation, error) {
+	rows, err := db.QueryContext(ctx, getHolonRelations, arg.SourceID, arg.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Relation
+	for rows.Next() {
+		var i Relation
+		if err := rows.Scan(
+			&i.SourceID,
+			&i.TargetID,
+			&i.RelationType,
+			&i.CongruenceLevel,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getHolonTitle = `-- name: GetHolonTitle :one
 SELECT title FROM holons WHERE id = ? LIMIT 1
 `
@@ -722,7 +939,7 @@ func (q *Queries) GetHolonTitle(ctx context.Context, db DBTX, id string) (string
 }
 
 const getHolonsByParent = `-- name: GetHolonsByParent :many
-SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at FROM holons WHERE parent_id = ? ORDER BY created_at DESC
+SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at, formality, claim_scope FROM holons WHERE parent_id = ? ORDER BY created_at DESC
 `
 
 func (q *Queries) GetHolonsByParent(ctx context.Context, db DBTX, parentID sql.NullString) ([]Holon, error) {
@@ -747,6 +964,8 @@ func (q *Queries) GetHolonsByParent(ctx context.Context, db DBTX, parentID sql.N
 			&i.CachedRScore,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Formality,
+			&i.ClaimScope,
 		); err != nil {
 			return nil, err
 		}
@@ -762,7 +981,7 @@ func (q *Queries) GetHolonsByParent(ctx context.Context, db DBTX, parentID sql.N
 }
 
 const getLatestHolonByContext = `-- name: GetLatestHolonByContext :one
-SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at FROM holons WHERE context_id = ? ORDER BY updated_at DESC LIMIT 1
+SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at, formality, claim_scope FROM holons WHERE context_id = ? ORDER BY updated_at DESC LIMIT 1
 `
 
 func (q *Queries) GetLatestHolonByContext(ctx context.Context, db DBTX, contextID string) (Holon, error) {
@@ -781,6 +1000,27 @@ func (q *Queries) GetLatestHolonByContext(ctx context.Context, db DBTX, contextI
 		&i.CachedRScore,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Formality,
+		&i.ClaimScope,
+	)
+	return i, err
+}
+
+const getLoopbackByChild = `-- name: GetLoopbackByChild :one
+SELECT id, parent_id, child_id, insight, phase, context_id, created_at FROM loopbacks WHERE child_id = ? ORDER BY created_at DESC LIMIT 1
+`
+
+func (q *Queries) GetLoopbackByChild(ctx context.Context, db DBTX, childID string) (Loopback, error) {
+	row := db.QueryRowContext(ctx, getLoopbackByChild, childID)
+	var i Loopback
+	err := row.Scan(
+		&i.ID,
+		&i.ParentID,
+		&i.ChildID,
+		&i.Insight,
+		&i.Phase,
+		&i.ContextID,
+		&i.CreatedAt,
 	)
 	return i, err
 }
@@ -861,8 +1101,21 @@ func (q *Queries) GetRelationsByTarget(ctx context.Context, db DBTX, arg GetRela
 	return items, nil
 }
 
+const getSetting = `-- name: GetSetting :one
+
+SELECT value FROM settings WHERE key = ? LIMIT 1
+`
+
+// Settings queries
+func (q *Queries) GetSetting(ctx context.Context, db DBTX, key string) (string, error) {
+	row := db.QueryRowContext(ctx, getSetting, key)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
 const getWaiversByEvidence = `-- name: GetWaiversByEvidence :many
-SELECT id, evidence_id, waived_by, waived_until, rationale, created_at FROM waivers WHERE evidence_id = ? ORDER BY created_at DESC
+SELECT id, evidence_id, waived_by, waived_until, rationale, created_at, revoked_at FROM waivers WHERE evidence_id = ? ORDER BY created_at DESC
 `
 
 func (q *Queries) GetWaiversByEvidence(ctx context.Context, db DBTX, evidenceID string) ([]Waiver, error) {
@@ -881,6 +1134,7 @@ func (q *Queries) GetWaiversByEvidence(ctx context.Context, db DBTX, evidenceID
 			&i.WaivedUntil,
 			&i.Rationale,
 			&i.CreatedAt,
+			&i.RevokedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -929,6 +1183,49 @@ func (q *Queries) InsertAuditLog(ctx context.Context, db DBTX, arg InsertAuditLo
 	return err
 }
 
+const listAllEvidence = `-- name: ListAllEvidence :many
+SELECT id, holon_id, type, content, verdict, assurance_level, carrier_ref, valid_until, created_at, formality, artifact_kind, artifact_ref, artifact_hash, artifact_source, recipe FROM evidence ORDER BY id ASC
+`
+
+func (q *Queries) ListAllEvidence(ctx context.Context, db DBTX) ([]Evidence, error) {
+	rows, err := db.QueryContext(ctx, listAllEvidence)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Evidence
+	for rows.Next() {
+		var i Evidence
+		if err := rows.Scan(
+			&i.ID,
+			&i.HolonID,
+			&i.Type,
+			&i.Content,
+			&i.Verdict,
+			&i.AssuranceLevel,
+			&i.CarrierRef,
+			&i.ValidUntil,
+			&i.CreatedAt,
+			&i.Formality,
+			&i.ArtifactKind,
+			&i.ArtifactRef,
+			&i.ArtifactHash,
+			&i.ArtifactSource,
+			&i.Recipe,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAllHolonIDs = `-- name: ListAllHolonIDs :many
 SELECT id FROM holons
 `
@@ -956,8 +1253,238 @@ func (q *Queries) ListAllHolonIDs(ctx context.Context, db DBTX) ([]string, error
 	return items, nil
 }
 
+const listAllHolons = `-- name: ListAllHolons :many
+SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at, formality, claim_scope FROM holons ORDER BY id ASC
+`
+
+func (q *Queries) ListAllHolons(ctx context.Context, db DBTX) ([]Holon, error) {
+	rows, err := db.QueryContext(ctx, listAllHolons)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Holon
+	for rows.Next() {
+		var i Holon
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Kind,
+			&i.Layer,
+			&i.Title,
+			&i.Content,
+			&i.ContextID,
+			&i.Scope,
+			&i.ParentID,
+			&i.CachedRScore,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Formality,
+			&i.ClaimScope,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllRelations = `-- name: ListAllRelations :many
+SELECT source_id, target_id, relation_type, congruence_level, created_at FROM relations ORDER BY source_id ASC, target_id ASC, relation_type ASC
+`
+
+func (q *Queries) ListAllRelations(ctx context.Context, db DBTX) ([]Relation, error) {
+	rows, err := db.QueryContext(ctx, listAllRelations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Relation
+	for rows.Next() {
+		var i Relation
+		if err := rows.Scan(
+			&i.SourceID,
+			&i.TargetID,
+			&i.RelationType,
+			&i.CongruenceLevel,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listContexts = `-- name: ListContexts :many
+SELECT id, title, description, status, created_at, archived_at FROM contexts ORDER BY created_at ASC, id ASC
+`
+
+func (q *Queries) ListContexts(ctx context.Context, db DBTX) ([]Context, error) {
+	rows, err := db.QueryContext(ctx, listContexts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Context
+	for rows.Next() {
+		var i Context
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.CreatedAt,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listContextSnapshots = `-- name: ListContextSnapshots :many
+SELECT context_id, source, content, content_hash, taken_at FROM context_snapshots WHERE context_id = ? ORDER BY source
+`
+
+func (q *Queries) ListContextSnapshots(ctx context.Context, db DBTX, contextID string) ([]ContextSnapshot, error) {
+	rows, err := db.QueryContext(ctx, listContextSnapshots, contextID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ContextSnapshot
+	for rows.Next() {
+		var i ContextSnapshot
+		if err := rows.Scan(
+			&i.ContextID,
+			&i.Source,
+			&i.Content,
+			&i.ContentHash,
+			&i.TakenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEvidenceByContext = `-- name: ListEvidenceByContext :many
+SELECT e.id, e.holon_id, e.type, e.content, e.verdict, e.assurance_level, e.carrier_ref, e.valid_until, e.created_at, e.formality, e.artifact_kind, e.artifact_ref, e.artifact_hash, e.artifact_source, e.recipe FROM evidence e
+JOIN holons h ON h.id = e.holon_id
+WHERE h.context_id = ?
+ORDER BY e.created_at ASC, e.id ASC
+`
+
+func (q *Queries) ListEvidenceByContext(ctx context.Context, db DBTX, contextID string) ([]Evidence, error) {
+	rows, err := db.QueryContext(ctx, listEvidenceByContext, contextID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Evidence
+	for rows.Next() {
+		var i Evidence
+		if err := rows.Scan(
+			&i.ID,
+			&i.HolonID,
+			&i.Type,
+			&i.Content,
+			&i.Verdict,
+			&i.AssuranceLevel,
+			&i.CarrierRef,
+			&i.ValidUntil,
+			&i.CreatedAt,
+			&i.Formality,
+			&i.ArtifactKind,
+			&i.ArtifactRef,
+			&i.ArtifactHash,
+			&i.ArtifactSource,
+			&i.Recipe,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listHolonsByContext = `-- name: ListHolonsByContext :many
+SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at, formality, claim_scope FROM holons WHERE context_id = ? ORDER BY created_at ASC, id ASC
+`
+
+func (q *Queries) ListHolonsByContext(ctx context.Context, db DBTX, contextID string) ([]Holon, error) {
+	rows, err := db.QueryContext(ctx, listHolonsByContext, contextID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Holon
+	for rows.Next() {
+		var i Holon
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Kind,
+			&i.Layer,
+			&i.Title,
+			&i.Content,
+			&i.ContextID,
+			&i.Scope,
+			&i.ParentID,
+			&i.CachedRScore,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Formality,
+			&i.ClaimScope,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listHolonsByLayer = `-- name: ListHolonsByLayer :many
-SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at FROM holons WHERE layer = ? ORDER BY created_at DESC
+SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at, formality, claim_scope FROM holons WHERE layer = ? ORDER BY created_at DESC
 `
 
 func (q *Queries) ListHolonsByLayer(ctx context.Context, db DBTX, layer string) ([]Holon, error) {
@@ -982,6 +1509,8 @@ func (q *Queries) ListHolonsByLayer(ctx context.Context, db DBTX, layer string)
 			&i.CachedRScore,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Formality,
+			&i.ClaimScope,
 		); err != nil {
 			return nil, err
 		}
@@ -1026,6 +1555,163 @@ func (q *Queries) RecordWork(ctx context.Context, db DBTX, arg RecordWorkParams)
 	return err
 }
 
+const revokeWaiver = `-- name: RevokeWaiver :exec
+UPDATE waivers SET revoked_at = ? WHERE id = ?
+`
+
+type RevokeWaiverParams struct {
+	RevokedAt sql.NullTime
+	ID        string
+}
+
+func (q *Queries) RevokeWaiver(ctx context.Context, db DBTX, arg RevokeWaiverParams) error {
+	_, err := db.ExecContext(ctx, revokeWaiver, arg.RevokedAt, arg.ID)
+	return err
+}
+
+const setHolonParent = `-- name: SetHolonParent :exec
+UPDATE holons SET parent_id = ?, updated_at = ? WHERE id = ?
+`
+
+type SetHolonParentParams struct {
+	ParentID  sql.NullString
+	UpdatedAt sql.NullTime
+	ID        string
+}
+
+func (q *Queries) SetHolonParent(ctx context.Context, db DBTX, arg SetHolonParentParams) error {
+	_, err := db.ExecContext(ctx, setHolonParent, arg.ParentID, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const setSetting = `-- name: SetSetting :exec
+INSERT INTO settings (key, value, updated_at)
+VALUES (?, ?, ?)
+ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+`
+
+type SetSettingParams struct {
+	Key       string
+	Value     string
+	UpdatedAt sql.NullTime
+}
+
+func (q *Queries) SetSetting(ctx context.Context, db DBTX, arg SetSettingParams) error {
+	_, err := db.ExecContext(ctx, setSetting, arg.Key, arg.Value, arg.UpdatedAt)
+	return err
+}
+
+const upsertContextSnapshot = `-- name: UpsertContextSnapshot :exec
+INSERT INTO context_snapshots (context_id, source, content, content_hash, taken_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(context_id, source) DO UPDATE SET content = excluded.content, content_hash = excluded.content_hash, taken_at = excluded.taken_at
+`
+
+type UpsertContextSnapshotParams struct {
+	ContextID   string
+	Source      string
+	Content     string
+	ContentHash string
+	TakenAt     sql.NullTime
+}
+
+func (q *Queries) UpsertContextSnapshot(ctx context.Context, db DBTX, arg UpsertContextSnapshotParams) error {
+	_, err := db.ExecContext(ctx, upsertContextSnapshot,
+		arg.ContextID,
+		arg.Source,
+		arg.Content,
+		arg.ContentHash,
+		arg.TakenAt,
+	)
+	return err
+}
+
+const updateEvidenceArtifact = `-- name: UpdateEvidenceArtifact :exec
+UPDATE evidence SET artifact_kind = ?, artifact_ref = ?, artifact_hash = ?, artifact_source = ? WHERE id = ?
+`
+
+type UpdateEvidenceArtifactParams struct {
+	ArtifactKind   sql.NullString
+	ArtifactRef    sql.NullString
+	ArtifactHash   sql.NullString
+	ArtifactSource sql.NullString
+	ID             string
+}
+
+func (q *Queries) UpdateEvidenceArtifact(ctx context.Context, db DBTX, arg UpdateEvidenceArtifactParams) error {
+	_, err := db.ExecContext(ctx, updateEvidenceArtifact,
+		arg.ArtifactKind,
+		arg.ArtifactRef,
+		arg.ArtifactHash,
+		arg.ArtifactSource,
+		arg.ID,
+	)
+	return err
+}
+
+const updateEvidenceFormality = `-- name: UpdateEvidenceFormality :exec
+UPDATE evidence SET formality = ? WHERE id = ?
+`
+
+type UpdateEvidenceFormalityParams struct {
+	Formality sql.NullInt64
+	ID        string
+}
+
+func (q *Queries) UpdateEvidenceFormality(ctx context.Context, db DBTX, arg UpdateEvidenceFormalityParams) error {
+	_, err := db.ExecContext(ctx, updateEvidenceFormality, arg.Formality, arg.ID)
+	return err
+}
+
+const updateEvidenceRecipe = `-- name: UpdateEvidenceRecipe :exec
+UPDATE evidence SET recipe = ? WHERE id = ?
+`
+
+type UpdateEvidenceRecipeParams struct {
+	Recipe sql.NullString
+	ID     string
+}
+
+func (q *Queries) UpdateEvidenceRecipe(ctx context.Context, db DBTX, arg UpdateEvidenceRecipeParams) error {
+	_, err := db.ExecContext(ctx, updateEvidenceRecipe, arg.Recipe, arg.ID)
+	return err
+}
+
+const updateEvidenceValidUntil = `-- name: UpdateEvidenceValidUntil :exec
+UPDATE evidence SET valid_until = ? WHERE id = ?
+`
+
+type UpdateEvidenceValidUntilParams struct {
+	ValidUntil sql.NullTime
+	ID         string
+}
+
+func (q *Queries) UpdateEvidenceValidUntil(ctx context.Context, db DBTX, arg UpdateEvidenceValidUntilParams) error {
+	_, err := db.ExecContext(ctx, updateEvidenceValidUntil, arg.ValidUntil, arg.ID)
+	return err
+}
+
+const updateHolonClaim = `-- name: UpdateHolonClaim :exec
+UPDATE holons SET formality = ?, claim_scope = ?, updated_at = ? WHERE id = ?
+`
+
+type UpdateHolonClaimParams struct {
+	Formality  sql.NullInt64
+	ClaimScope sql.NullString
+	UpdatedAt  sql.NullTime
+	ID         string
+}
+
+func (q *Queries) UpdateHolonClaim(ctx context.Context, db DBTX, arg UpdateHolonClaimParams) error {
+	_, err := db.ExecContext(ctx, updateHolonClaim,
+		arg.Formality,
+		arg.ClaimScope,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
+
 const updateHolonLayer = `-- name: UpdateHolonLayer :exec
 UPDATE holons SET layer = ?, updated_at = ? WHERE id = ?
 `
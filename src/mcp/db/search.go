@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// Search is hand-written rather than generated: sqlc cannot express FTS5 MATCH
+// against the search_index virtual table, nor the optional filters below.
+
+type SearchParams struct {
+	Query     string
+	ContextID string
+	Layer     string  // optional exact match on holons.layer
+	Kind      string  // optional exact match on holons.kind
+	Scope     string  // optional substring match on holons.scope
+	MinR      float64 // minimum holons.cached_r_score
+	Limit     int
+}
+
+type SearchResult struct {
+	DocID        string
+	DocType      string // holon, decision or evidence
+	HolonID      string
+	Title        string
+	Layer        string
+	Kind         sql.NullString
+	Scope        sql.NullString
+	CachedRScore sql.NullFloat64
+	Snippet      string
+	Score        float64 // bm25, lower is better
+}
+
+const search = `
+SELECT search_index.doc_id, search_index.doc_type, search_index.holon_id, h.title, h.layer, h.kind, h.scope, h.cached_r_score,
+	snippet(search_index, 4, '**', '**', '…', 16), bm25(search_index, 0, 0, 0, 5.0, 1.0) AS score
+FROM search_index
+JOIN holons h ON h.id = search_index.holon_id
+WHERE search_index MATCH ?
+	AND h.context_id = ?
+	AND (? = '' OR h.layer = ?)
+	AND (? = '' OR h.kind = ?)
+	AND (? = '' OR h.scope LIKE '%' || ? || '%')
+	AND COALESCE(h.cached_r_score, 0) >= ?
+ORDER BY score
+LIMIT ?
+`
+
+// Search runs a full-text query over holons, DRRs and evidence of one context.
+// Evidence is filtered by the attributes of the holon it supports. Best match first.
+func (s *Store) Search(ctx context.Context, p SearchParams) ([]SearchResult, error) {
+	match := FTSQuery(p.Query)
+	if match == "" {
+		return nil, nil
+	}
+	limit := p.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	rows, err := s.db.QueryContext(ctx, search, match, p.ContextID,
+		p.Layer, p.Layer, p.Kind, p.Kind, p.Scope, p.Scope, p.MinR, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []SearchResult
+	for rows.Next() {
+		var i SearchResult
+		if err := rows.Scan(
+			&i.DocID,
+			&i.DocType,
+			&i.HolonID,
+			&i.Title,
+			&i.Layer,
+			&i.Kind,
+			&i.Scope,
+			&i.CachedRScore,
+			&i.Snippet,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// FTSQuery turns free text into a safe FTS5 query: every word becomes a quoted
+// term (all must match), a trailing * keeps prefix search, and uppercase OR/NOT
+// pass through as operators. An operator with no term before it is dropped,
+// since FTS5 rejects "a OR NOT b".
+func FTSQuery(input string) string {
+	isOperator := func(term string) bool { return term == "OR" || term == "NOT" }
+
+	var terms []string
+	for _, word := range strings.Fields(input) {
+		if isOperator(word) {
+			if len(terms) > 0 && !isOperator(terms[len(terms)-1]) {
+				terms = append(terms, word)
+			}
+			continue
+		}
+
+		prefix := strings.HasSuffix(word, "*")
+		word = strings.Trim(word, `*"`)
+		if word == "" {
+			continue
+		}
+		term := `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+		if prefix {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+
+	for len(terms) > 0 && isOperator(terms[len(terms)-1]) {
+		terms = terms[:len(terms)-1]
+	}
+	return strings.Join(terms, " ")
+}
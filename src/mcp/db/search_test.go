@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestFTSQuery(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"redis cache", `"redis" "cache"`},
+		{"cach*", `"cach"*`},
+		{"redis OR memcached", `"redis" OR "memcached"`},
+		{`say "hi"`, `"say" "hi"`},
+		{"rate-limit", `"rate-limit"`},
+		{"OR redis NOT", `"redis"`},
+		{"cache OR NOT redis", `"cache" OR "redis"`},
+		{"cache NOT OR NOT redis", `"cache" NOT "redis"`},
+		{"   ", ""},
+	}
+
+	for _, tt := range tests {
+		if got := FTSQuery(tt.input); got != tt.expected {
+			t.Errorf("FTSQuery(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestStore_Search(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	_ = store.CreateHolon(ctx, "redis", "hypothesis", "system", "L1", "Redis caching", "Cache sessions in Redis", "default", "api endpoints", "")
+	_ = store.CreateHolon(ctx, "cdn", "hypothesis", "system", "L0", "CDN edge", "Serve static assets from the edge cache", "default", "static assets", "")
+	_ = store.CreateHolon(ctx, "review", "hypothesis", "episteme", "L1", "Review process", "Review every caching change", "default", "team", "")
+	_ = store.CreateHolon(ctx, "other", "hypothesis", "system", "L1", "Redis elsewhere", "Redis in another context", "other", "api", "")
+	_ = store.AddEvidence(ctx, "ev1", "cdn", "internal", "Benchmark shows a memcached-like latency", "pass", "L2", "bench", "")
+	_, _ = store.conn.Exec("UPDATE holons SET cached_r_score = 0.9 WHERE id = 'redis'")
+
+	ids := func(results []SearchResult) map[string]bool {
+		m := make(map[string]bool)
+		for _, r := range results {
+			m[r.DocID] = true
+		}
+		return m
+	}
+
+	results, err := store.Search(ctx, SearchParams{Query: "cach*", ContextID: "default"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	got := ids(results)
+	if !got["redis"] || !got["cdn"] || !got["review"] || got["other"] {
+		t.Errorf("unexpected results for cach*: %v", got)
+	}
+
+	results, _ = store.Search(ctx, SearchParams{Query: "cach*", ContextID: "default", Layer: "L1", Kind: "system"})
+	if got := ids(results); len(got) != 1 || !got["redis"] {
+		t.Errorf("expected only redis for L1 system, got %v", got)
+	}
+
+	results, _ = store.Search(ctx, SearchParams{Query: "cach*", ContextID: "default", MinR: 0.5})
+	if got := ids(results); len(got) != 1 || !got["redis"] {
+		t.Errorf("expected only redis for min R 0.5, got %v", got)
+	}
+
+	results, _ = store.Search(ctx, SearchParams{Query: "benchmark", ContextID: "default", Scope: "static"})
+	if len(results) != 1 || results[0].DocType != "evidence" || results[0].HolonID != "cdn" {
+		t.Errorf("expected evidence for cdn, got %+v", results)
+	}
+
+	if _, err := store.Search(ctx, SearchParams{Query: "cache OR NOT redis", ContextID: "default"}); err != nil {
+		t.Errorf("expected stacked operators to be accepted, got %v", err)
+	}
+
+	// Triggers keep the index in sync with updates and deletes
+	_, _ = store.conn.Exec("UPDATE holons SET content = 'Use a message queue' WHERE id = 'review'")
+	_, _ = store.conn.Exec("DELETE FROM evidence WHERE id = 'ev1'")
+
+	results, _ = store.Search(ctx, SearchParams{Query: "queue", ContextID: "default"})
+	if got := ids(results); !got["review"] {
+		t.Errorf("expected updated holon to be indexed, got %v", got)
+	}
+	results, _ = store.Search(ctx, SearchParams{Query: "benchmark", ContextID: "default"})
+	if len(results) != 0 {
+		t.Errorf("expected deleted evidence to leave the index, got %+v", results)
+	}
+}
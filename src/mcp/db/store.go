@@ -22,7 +22,9 @@ CREATE TABLE IF NOT EXISTS holons (
 	parent_id TEXT REFERENCES holons(id),
 	cached_r_score REAL DEFAULT 0.0 CHECK(cached_r_score BETWEEN 0.0 AND 1.0),
 	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	formality INTEGER DEFAULT 0 CHECK(formality BETWEEN 0 AND 9),
+	claim_scope TEXT
 );
 CREATE TABLE IF NOT EXISTS evidence (
 	id TEXT PRIMARY KEY,
@@ -33,7 +35,13 @@ CREATE TABLE IF NOT EXISTS evidence (
 	assurance_level TEXT,
 	carrier_ref TEXT,
 	valid_until DATETIME,
-	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	formality INTEGER CHECK(formality BETWEEN 0 AND 9),
+	artifact_kind TEXT,
+	artifact_ref TEXT,
+	artifact_hash TEXT,
+	artifact_source TEXT,
+	recipe TEXT
 );
 CREATE TABLE IF NOT EXISTS relations (
 	source_id TEXT NOT NULL,
@@ -81,6 +89,7 @@ CREATE TABLE IF NOT EXISTS waivers (
 	waived_until DATETIME NOT NULL,
 	rationale TEXT NOT NULL,
 	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	revoked_at DATETIME,
 	FOREIGN KEY(evidence_id) REFERENCES evidence(id)
 );
 CREATE INDEX IF NOT EXISTS idx_relations_target ON relations(target_id, relation_type);
@@ -90,6 +99,7 @@ CREATE INDEX IF NOT EXISTS idx_waivers_evidence ON waivers(evidence_id);
 
 type Store struct {
 	conn *sql.DB
+	db   DBTX // conn, or the transaction of a Store returned by WithTx
 	q    *Queries
 }
 
@@ -109,10 +119,35 @@ func NewStore(dbPath string) (*Store, error) {
 
 	return &Store{
 		conn: conn,
+		db:   conn,
 		q:    New(),
 	}, nil
 }
 
+// WithTx runs fn with a Store whose queries all go through one transaction.
+// The transaction commits when fn returns nil and rolls back otherwise.
+// Calling WithTx on a transactional Store joins the running transaction.
+func (s *Store) WithTx(ctx context.Context, fn func(tx *Store) error) error {
+	if _, ok := s.db.(*sql.Tx); ok {
+		return fn(s)
+	}
+
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(&Store{conn: s.conn, db: tx, q: s.q}); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 func (s *Store) GetRawDB() *sql.DB {
 	return s.conn
 }
@@ -123,7 +158,7 @@ func (s *Store) Close() error {
 
 func (s *Store) CreateHolon(ctx context.Context, id, typ, kind, layer, title, content, contextID, scope, parentID string) error {
 	now := sql.NullTime{Time: time.Now(), Valid: true}
-	return s.q.CreateHolon(ctx, s.conn, CreateHolonParams{
+	return s.q.CreateHolon(ctx, s.db, CreateHolonParams{
 		ID:        id,
 		Type:      typ,
 		Kind:      toNullString(kind),
@@ -139,19 +174,32 @@ func (s *Store) CreateHolon(ctx context.Context, id, typ, kind, layer, title, co
 }
 
 func (s *Store) GetHolon(ctx context.Context, id string) (Holon, error) {
-	return s.q.GetHolon(ctx, s.conn, id)
+	return s.q.GetHolon(ctx, s.db, id)
 }
 
 func (s *Store) GetHolonTitle(ctx context.Context, id string) (string, error) {
-	return s.q.GetHolonTitle(ctx, s.conn, id)
+	return s.q.GetHolonTitle(ctx, s.db, id)
 }
 
 func (s *Store) ListAllHolonIDs(ctx context.Context) ([]string, error) {
-	return s.q.ListAllHolonIDs(ctx, s.conn)
+	return s.q.ListAllHolonIDs(ctx, s.db)
+}
+
+func (s *Store) ListAllHolons(ctx context.Context) ([]Holon, error) {
+	return s.q.ListAllHolons(ctx, s.db)
+}
+
+func (s *Store) UpdateHolonClaim(ctx context.Context, id string, formality int, claimScope string) error {
+	return s.q.UpdateHolonClaim(ctx, s.db, UpdateHolonClaimParams{
+		Formality:  sql.NullInt64{Int64: int64(formality), Valid: true},
+		ClaimScope: toNullString(claimScope),
+		UpdatedAt:  sql.NullTime{Time: time.Now(), Valid: true},
+		ID:         id,
+	})
 }
 
 func (s *Store) UpdateHolonLayer(ctx context.Context, id, layer string) error {
-	return s.q.UpdateHolonLayer(ctx, s.conn, UpdateHolonLayerParams{
+	return s.q.UpdateHolonLayer(ctx, s.db, UpdateHolonLayerParams{
 		ID:        id,
 		Layer:     layer,
 		UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
@@ -159,7 +207,7 @@ func (s *Store) UpdateHolonLayer(ctx context.Context, id, layer string) error {
 }
 
 func (s *Store) RecordWork(ctx context.Context, id, methodRef, performerRef string, startedAt, endedAt time.Time, ledger string) error {
-	return s.q.RecordWork(ctx, s.conn, RecordWorkParams{
+	return s.q.RecordWork(ctx, s.db, RecordWorkParams{
 		ID:             id,
 		MethodRef:      methodRef,
 		PerformerRef:   performerRef,
@@ -182,7 +230,7 @@ func (s *Store) AddEvidence(ctx context.Context, id, holonID, typ, content, verd
 		}
 	}
 
-	return s.q.AddEvidence(ctx, s.conn, AddEvidenceParams{
+	return s.q.AddEvidence(ctx, s.db, AddEvidenceParams{
 		ID:             id,
 		HolonID:        holonID,
 		Type:           typ,
@@ -195,16 +243,67 @@ func (s *Store) AddEvidence(ctx context.Context, id, holonID, typ, content, verd
 	})
 }
 
+func (s *Store) UpdateEvidenceFormality(ctx context.Context, id string, formality int) error {
+	return s.q.UpdateEvidenceFormality(ctx, s.db, UpdateEvidenceFormalityParams{
+		Formality: sql.NullInt64{Int64: int64(formality), Valid: true},
+		ID:        id,
+	})
+}
+
+func (s *Store) UpdateEvidenceArtifact(ctx context.Context, id, kind, ref, hash, source string) error {
+	return s.q.UpdateEvidenceArtifact(ctx, s.db, UpdateEvidenceArtifactParams{
+		ArtifactKind:   toNullString(kind),
+		ArtifactRef:    toNullString(ref),
+		ArtifactHash:   toNullString(hash),
+		ArtifactSource: toNullString(source),
+		ID:             id,
+	})
+}
+
+func (s *Store) UpdateEvidenceRecipe(ctx context.Context, id, recipe string) error {
+	return s.q.UpdateEvidenceRecipe(ctx, s.db, UpdateEvidenceRecipeParams{
+		Recipe: toNullString(recipe),
+		ID:     id,
+	})
+}
+
+func (s *Store) UpdateEvidenceValidUntil(ctx context.Context, id string, validUntil time.Time) error {
+	return s.q.UpdateEvidenceValidUntil(ctx, s.db, UpdateEvidenceValidUntilParams{
+		ValidUntil: sql.NullTime{Time: validUntil, Valid: true},
+		ID:         id,
+	})
+}
+
 func (s *Store) GetEvidence(ctx context.Context, holonID string) ([]Evidence, error) {
-	return s.q.GetEvidenceByHolon(ctx, s.conn, holonID)
+	return s.q.GetEvidenceByHolon(ctx, s.db, holonID)
 }
 
 func (s *Store) GetEvidenceWithCarrier(ctx context.Context) ([]Evidence, error) {
-	return s.q.GetEvidenceWithCarrier(ctx, s.conn)
+	return s.q.GetEvidenceWithCarrier(ctx, s.db)
+}
+
+func (s *Store) ListAllEvidence(ctx context.Context) ([]Evidence, error) {
+	return s.q.ListAllEvidence(ctx, s.db)
+}
+
+func (s *Store) AddCharacteristic(ctx context.Context, id, holonID, name, scale, value, unit string) error {
+	return s.q.AddCharacteristic(ctx, s.db, AddCharacteristicParams{
+		ID:        id,
+		HolonID:   holonID,
+		Name:      name,
+		Scale:     scale,
+		Value:     value,
+		Unit:      toNullString(unit),
+		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+}
+
+func (s *Store) GetCharacteristics(ctx context.Context, holonID string) ([]Characteristic, error) {
+	return s.q.GetCharacteristics(ctx, s.db, holonID)
 }
 
 func (s *Store) Link(ctx context.Context, source, target, relType string) error {
-	return s.q.AddRelation(ctx, s.conn, AddRelationParams{
+	return s.q.AddRelation(ctx, s.db, AddRelationParams{
 		SourceID:     source,
 		TargetID:     target,
 		RelationType: relType,
@@ -213,7 +312,7 @@ func (s *Store) Link(ctx context.Context, source, target, relType string) error
 }
 
 func (s *Store) CreateRelation(ctx context.Context, sourceID, relationType, targetID string, cl int) error {
-	return s.q.CreateRelation(ctx, s.conn, CreateRelationParams{
+	return s.q.CreateRelation(ctx, s.db, CreateRelationParams{
 		SourceID:        sourceID,
 		RelationType:    relationType,
 		TargetID:        targetID,
@@ -222,35 +321,95 @@ func (s *Store) CreateRelation(ctx context.Context, sourceID, relationType, targ
 }
 
 func (s *Store) GetComponentsOf(ctx context.Context, targetID string) ([]GetComponentsOfRow, error) {
-	return s.q.GetComponentsOf(ctx, s.conn, targetID)
+	return s.q.GetComponentsOf(ctx, s.db, targetID)
+}
+
+func (s *Store) GetHolonRelations(ctx context.Context, id string) ([]Relation, error) {
+	return s.q.GetHolonRelations(ctx, s.db, GetHolonRelationsParams{SourceID: id, TargetID: id})
+}
+
+func (s *Store) ListAllRelations(ctx context.Context) ([]Relation, error) {
+	return s.q.ListAllRelations(ctx, s.db)
+}
+
+func (s *Store) DeleteRelation(ctx context.Context, sourceID, targetID, relationType string) error {
+	return s.q.DeleteRelation(ctx, s.db, DeleteRelationParams{
+		SourceID:     sourceID,
+		TargetID:     targetID,
+		RelationType: relationType,
+	})
+}
+
+// ClearKnowledge deletes every holon, evidence record, relation and waiver,
+// so that they can be rebuilt from the markdown projection
+func (s *Store) ClearKnowledge(ctx context.Context) error {
+	for _, deleteAll := range []func(context.Context, DBTX) error{
+		s.q.DeleteAllWaivers,
+		s.q.DeleteAllRelations,
+		s.q.DeleteAllEvidence,
+		s.q.DeleteAllHolons,
+	} {
+		if err := deleteAll(ctx, s.db); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *Store) GetCollectionMembers(ctx context.Context, targetID string) ([]GetCollectionMembersRow, error) {
-	return s.q.GetCollectionMembers(ctx, s.conn, targetID)
+	return s.q.GetCollectionMembers(ctx, s.db, targetID)
 }
 
 func (s *Store) GetDependencies(ctx context.Context, sourceID string) ([]GetDependenciesRow, error) {
-	return s.q.GetDependencies(ctx, s.conn, sourceID)
+	return s.q.GetDependencies(ctx, s.db, sourceID)
 }
 
 func (s *Store) GetHolonsByParent(ctx context.Context, parentID string) ([]Holon, error) {
-	return s.q.GetHolonsByParent(ctx, s.conn, toNullString(parentID))
+	return s.q.GetHolonsByParent(ctx, s.db, toNullString(parentID))
+}
+
+func (s *Store) ListHolonsByContext(ctx context.Context, contextID string) ([]Holon, error) {
+	return s.q.ListHolonsByContext(ctx, s.db, contextID)
 }
 
 func (s *Store) GetHolonLineage(ctx context.Context, id string) ([]GetHolonLineageRow, error) {
-	return s.q.GetHolonLineage(ctx, s.conn, id)
+	return s.q.GetHolonLineage(ctx, s.db, id)
+}
+
+func (s *Store) SetHolonParent(ctx context.Context, id, parentID string) error {
+	return s.q.SetHolonParent(ctx, s.db, SetHolonParentParams{
+		ParentID:  toNullString(parentID),
+		UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		ID:        id,
+	})
+}
+
+func (s *Store) CreateLoopback(ctx context.Context, id, parentID, childID, insight, phase, contextID string) error {
+	return s.q.CreateLoopback(ctx, s.db, CreateLoopbackParams{
+		ID:        id,
+		ParentID:  parentID,
+		ChildID:   childID,
+		Insight:   insight,
+		Phase:     phase,
+		ContextID: contextID,
+		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+}
+
+func (s *Store) GetLoopbackByChild(ctx context.Context, childID string) (Loopback, error) {
+	return s.q.GetLoopbackByChild(ctx, s.db, childID)
 }
 
 func (s *Store) CountHolonsByLayer(ctx context.Context, contextID string) ([]CountHolonsByLayerRow, error) {
-	return s.q.CountHolonsByLayer(ctx, s.conn, contextID)
+	return s.q.CountHolonsByLayer(ctx, s.db, contextID)
 }
 
 func (s *Store) GetLatestHolonByContext(ctx context.Context, contextID string) (Holon, error) {
-	return s.q.GetLatestHolonByContext(ctx, s.conn, contextID)
+	return s.q.GetLatestHolonByContext(ctx, s.db, contextID)
 }
 
 func (s *Store) InsertAuditLog(ctx context.Context, id, toolName, operation, actor, targetID, inputHash, result, details, contextID string) error {
-	return s.q.InsertAuditLog(ctx, s.conn, InsertAuditLogParams{
+	return s.q.InsertAuditLog(ctx, s.db, InsertAuditLogParams{
 		ID:        id,
 		ToolName:  toolName,
 		Operation: operation,
@@ -264,19 +423,19 @@ func (s *Store) InsertAuditLog(ctx context.Context, id, toolName, operation, act
 }
 
 func (s *Store) GetAuditLogByContext(ctx context.Context, contextID string) ([]AuditLog, error) {
-	return s.q.GetAuditLogByContext(ctx, s.conn, contextID)
+	return s.q.GetAuditLogByContext(ctx, s.db, contextID)
 }
 
 func (s *Store) GetAuditLogByTarget(ctx context.Context, targetID string) ([]AuditLog, error) {
-	return s.q.GetAuditLogByTarget(ctx, s.conn, toNullString(targetID))
+	return s.q.GetAuditLogByTarget(ctx, s.db, toNullString(targetID))
 }
 
 func (s *Store) GetRecentAuditLog(ctx context.Context, limit int64) ([]AuditLog, error) {
-	return s.q.GetRecentAuditLog(ctx, s.conn, limit)
+	return s.q.GetRecentAuditLog(ctx, s.db, limit)
 }
 
 func (s *Store) CreateWaiver(ctx context.Context, id, evidenceID, waivedBy string, waivedUntil time.Time, rationale string) error {
-	return s.q.CreateWaiver(ctx, s.conn, CreateWaiverParams{
+	return s.q.CreateWaiver(ctx, s.db, CreateWaiverParams{
 		ID:          id,
 		EvidenceID:  evidenceID,
 		WaivedBy:    waivedBy,
@@ -286,16 +445,85 @@ func (s *Store) CreateWaiver(ctx context.Context, id, evidenceID, waivedBy strin
 	})
 }
 
+func (s *Store) RevokeWaiver(ctx context.Context, id string, revokedAt time.Time) error {
+	return s.q.RevokeWaiver(ctx, s.db, RevokeWaiverParams{
+		RevokedAt: sql.NullTime{Time: revokedAt, Valid: true},
+		ID:        id,
+	})
+}
+
 func (s *Store) GetActiveWaiverForEvidence(ctx context.Context, evidenceID string) (Waiver, error) {
-	return s.q.GetActiveWaiverForEvidence(ctx, s.conn, evidenceID)
+	return s.q.GetActiveWaiverForEvidence(ctx, s.db, evidenceID)
+}
+
+func (s *Store) GetWaiversByEvidence(ctx context.Context, evidenceID string) ([]Waiver, error) {
+	return s.q.GetWaiversByEvidence(ctx, s.db, evidenceID)
 }
 
 func (s *Store) GetAllActiveWaivers(ctx context.Context) ([]Waiver, error) {
-	return s.q.GetAllActiveWaivers(ctx, s.conn)
+	return s.q.GetAllActiveWaivers(ctx, s.db)
 }
 
 func (s *Store) GetEvidenceByID(ctx context.Context, id string) (Evidence, error) {
-	return s.q.GetEvidenceByID(ctx, s.conn, id)
+	return s.q.GetEvidenceByID(ctx, s.db, id)
+}
+
+func (s *Store) ListEvidenceByContext(ctx context.Context, contextID string) ([]Evidence, error) {
+	return s.q.ListEvidenceByContext(ctx, s.db, contextID)
+}
+
+func (s *Store) CreateContext(ctx context.Context, id, title, description string) error {
+	return s.q.CreateContext(ctx, s.db, CreateContextParams{
+		ID:          id,
+		Title:       title,
+		Description: toNullString(description),
+		CreatedAt:   sql.NullTime{Time: time.Now(), Valid: true},
+	})
+}
+
+func (s *Store) GetContext(ctx context.Context, id string) (Context, error) {
+	return s.q.GetContext(ctx, s.db, id)
+}
+
+func (s *Store) ListContexts(ctx context.Context) ([]Context, error) {
+	return s.q.ListContexts(ctx, s.db)
+}
+
+func (s *Store) ArchiveContext(ctx context.Context, id string) error {
+	return s.q.ArchiveContext(ctx, s.db, ArchiveContextParams{
+		ArchivedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		ID:         id,
+	})
+}
+
+func (s *Store) ListContextSnapshots(ctx context.Context, contextID string) ([]ContextSnapshot, error) {
+	return s.q.ListContextSnapshots(ctx, s.db, contextID)
+}
+
+func (s *Store) UpsertContextSnapshot(ctx context.Context, contextID, source, content, contentHash string) error {
+	return s.q.UpsertContextSnapshot(ctx, s.db, UpsertContextSnapshotParams{
+		ContextID:   contextID,
+		Source:      source,
+		Content:     content,
+		ContentHash: contentHash,
+		TakenAt:     sql.NullTime{Time: time.Now(), Valid: true},
+	})
+}
+
+func (s *Store) DeleteContextSnapshot(ctx context.Context, contextID, source string) error {
+	return s.q.DeleteContextSnapshot(ctx, s.db, DeleteContextSnapshotParams{ContextID: contextID, Source: source})
+}
+
+func (s *Store) GetSetting(ctx context.Context, key string) (string, error) {
+	return s.q.GetSetting(ctx, s.db, key)
+}
+
+func (s *Store) SetSetting(ctx context.Context, key, value string) error {
+	return s.q.SetSetting(ctx, s.db, SetSettingParams{
+		Key:       key,
+		Value:     value,
+		UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
 }
 
 func toNullString(s string) sql.NullString {
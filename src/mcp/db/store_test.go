@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -97,6 +98,17 @@ func TestStore_EvidenceCRUD(t *testing.T) {
 		t.Errorf("Expected verdict 'pass', got '%s'", evidence[0].Verdict)
 	}
 
+	// Recording the same ID again drops the previous run's artifact and recipe
+	_ = store.UpdateEvidenceArtifact(ctx, "e1", "file", "artifacts/e1/report.txt", "abc123", "report.txt")
+	_ = store.UpdateEvidenceRecipe(ctx, "e1", "go test ./...")
+	if err := store.AddEvidence(ctx, "e1", "h1", "test_result", "Rerun", "pass", "L1", "internal-logic", ""); err != nil {
+		t.Fatalf("AddEvidence rerun failed: %v", err)
+	}
+	evidence, _ = store.GetEvidence(ctx, "h1")
+	if len(evidence) != 1 || evidence[0].ArtifactKind.Valid || evidence[0].ArtifactRef.Valid || evidence[0].ArtifactHash.Valid || evidence[0].ArtifactSource.Valid || evidence[0].Recipe.Valid {
+		t.Errorf("Expected the rerun to clear artifact and recipe, got %+v", evidence)
+	}
+
 	withCarrier, err := store.GetEvidenceWithCarrier(ctx)
 	if err != nil {
 		t.Fatalf("GetEvidenceWithCarrier failed: %v", err)
@@ -195,6 +207,45 @@ func TestStore_ParentChild(t *testing.T) {
 	}
 }
 
+func TestStore_Loopbacks(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+
+	_ = store.CreateHolon(ctx, "parent", "hypothesis", "system", "invalid", "Parent", "Content", "default", "", "")
+	_ = store.CreateHolon(ctx, "child", "hypothesis", "system", "L0", "Child", "Content", "default", "", "")
+	_ = store.CreateRelation(ctx, "dep", "componentOf", "parent", 2)
+
+	if err := store.SetHolonParent(ctx, "child", "parent"); err != nil {
+		t.Fatalf("SetHolonParent failed: %v", err)
+	}
+	if err := store.CreateLoopback(ctx, "lb-1", "parent", "child", "Latency too high", "DEDUCTION", "default"); err != nil {
+		t.Fatalf("CreateLoopback failed: %v", err)
+	}
+
+	lineage, err := store.GetHolonLineage(ctx, "child")
+	if err != nil || len(lineage) != 2 || lineage[0].ID != "parent" {
+		t.Errorf("Expected lineage [parent, child], got %v (err %v)", lineage, err)
+	}
+
+	loopback, err := store.GetLoopbackByChild(ctx, "child")
+	if err != nil {
+		t.Fatalf("GetLoopbackByChild failed: %v", err)
+	}
+	if loopback.ParentID != "parent" || loopback.Insight != "Latency too high" {
+		t.Errorf("Unexpected loopback: %+v", loopback)
+	}
+
+	relations, err := store.GetHolonRelations(ctx, "parent")
+	if err != nil || len(relations) != 1 || relations[0].SourceID != "dep" {
+		t.Errorf("Expected one relation from dep, got %v (err %v)", relations, err)
+	}
+}
+
 func TestStore_AuditLog(t *testing.T) {
 	tempDir := t.TempDir()
 	dbPath := filepath.Join(tempDir, "test.db")
@@ -242,6 +293,63 @@ func TestStore_AuditLog(t *testing.T) {
 	}
 }
 
+func TestStore_Contexts(t *testing.T) {
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "test.db")
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+
+	// Migration seeds the default context and selects it
+	def, err := store.GetContext(ctx, "default")
+	if err != nil {
+		t.Fatalf("Default context should exist: %v", err)
+	}
+	if def.Status != "open" {
+		t.Errorf("Expected default context to be open, got %s", def.Status)
+	}
+	active, err := store.GetSetting(ctx, "active_context")
+	if err != nil || active != "default" {
+		t.Errorf("Expected active_context 'default', got %q (err: %v)", active, err)
+	}
+
+	if err := store.CreateContext(ctx, "caching", "Caching", "Cache layer choices"); err != nil {
+		t.Fatalf("CreateContext failed: %v", err)
+	}
+	if err := store.CreateContext(ctx, "caching", "Caching", ""); err == nil {
+		t.Error("Expected duplicate context ID to fail")
+	}
+
+	contexts, err := store.ListContexts(ctx)
+	if err != nil {
+		t.Fatalf("ListContexts failed: %v", err)
+	}
+	if len(contexts) != 2 {
+		t.Fatalf("Expected 2 contexts, got %d", len(contexts))
+	}
+
+	if err := store.ArchiveContext(ctx, "caching"); err != nil {
+		t.Fatalf("ArchiveContext failed: %v", err)
+	}
+	caching, _ := store.GetContext(ctx, "caching")
+	if caching.Status != "archived" || !caching.ArchivedAt.Valid {
+		t.Errorf("Expected archived context with archived_at, got status=%s archived_at=%v", caching.Status, caching.ArchivedAt)
+	}
+
+	if err := store.SetSetting(ctx, "active_context", "caching"); err != nil {
+		t.Fatalf("SetSetting failed: %v", err)
+	}
+	active, _ = store.GetSetting(ctx, "active_context")
+	if active != "caching" {
+		t.Errorf("Expected active_context 'caching', got %q", active)
+	}
+}
+
 func TestStore_FileCleanup(t *testing.T) {
 	tempDir := t.TempDir()
 	dbPath := filepath.Join(tempDir, "test.db")
@@ -256,3 +364,45 @@ func TestStore_FileCleanup(t *testing.T) {
 		t.Error("Database file should exist after close")
 	}
 }
+
+func TestStore_WithTx(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+
+	err = store.WithTx(ctx, func(tx *Store) error {
+		if err := tx.CreateHolon(ctx, "kept", "hypothesis", "system", "L0", "Kept", "Content", "default", "", ""); err != nil {
+			return err
+		}
+		// Nested calls join the running transaction
+		return tx.WithTx(ctx, func(inner *Store) error {
+			return inner.CreateRelation(ctx, "dep", "componentOf", "kept", 3)
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+	if _, err := store.GetHolon(ctx, "kept"); err != nil {
+		t.Errorf("Expected committed holon, got %v", err)
+	}
+
+	failure := errors.New("boom")
+	err = store.WithTx(ctx, func(tx *Store) error {
+		_ = tx.CreateHolon(ctx, "dropped", "hypothesis", "system", "L0", "Dropped", "Content", "default", "", "")
+		_ = tx.UpdateHolonLayer(ctx, "kept", "L1")
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Expected fn error to be returned, got %v", err)
+	}
+	if _, err := store.GetHolon(ctx, "dropped"); err == nil {
+		t.Error("Expected rolled back holon to be absent")
+	}
+	if h, _ := store.GetHolon(ctx, "kept"); h.Layer != "L0" {
+		t.Errorf("Expected rolled back layer L0, got %s", h.Layer)
+	}
+}
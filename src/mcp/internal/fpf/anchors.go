@@ -0,0 +1,606 @@
+package fpf
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// RelationAnchoredTo links a holon (source) to a code anchor (target). The
+// target is not a holon but an anchor string such as "path:src/cache/**".
+const RelationAnchoredTo = "anchoredTo"
+
+// Anchor kinds: what part of the code a holon covers
+const (
+	AnchorPath   = "path"   // path glob relative to the repository root; ** spans directories
+	AnchorPkg    = "pkg"    // Go package, by import path or directory
+	AnchorSymbol = "symbol" // Go declaration: Name or Type.Method
+)
+
+var symbolPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Anchor is a parsed code anchor
+type Anchor struct {
+	Kind  string
+	Value string
+}
+
+func (a Anchor) String() string {
+	return a.Kind + ":" + a.Value
+}
+
+// ParseAnchor reads "kind:value"; anchors without a kind are path globs
+func ParseAnchor(s string) (Anchor, error) {
+	s = strings.TrimSpace(s)
+	a := Anchor{Kind: AnchorPath, Value: s}
+	if kind, value, ok := strings.Cut(s, ":"); ok {
+		switch kind {
+		case AnchorPath, AnchorPkg, AnchorSymbol:
+			a = Anchor{Kind: kind, Value: strings.TrimSpace(value)}
+		}
+	}
+
+	if a.Value == "" {
+		return Anchor{}, fmt.Errorf("anchor %q is empty", s)
+	}
+	if strings.ContainsAny(a.Value, ", \t\r\n") {
+		return Anchor{}, fmt.Errorf("anchor %q must not contain commas or whitespace", s)
+	}
+
+	switch a.Kind {
+	case AnchorPath:
+		a.Value = strings.TrimPrefix(filepath.ToSlash(a.Value), "./")
+		if path.IsAbs(a.Value) || a.Value == ".." || strings.HasPrefix(a.Value, "../") {
+			return Anchor{}, fmt.Errorf("path anchor %q must be relative to the repository root", s)
+		}
+		for _, segment := range strings.Split(a.Value, "/") {
+			if _, err := path.Match(segment, ""); err != nil {
+				return Anchor{}, fmt.Errorf("path anchor %q: %v", s, err)
+			}
+		}
+	case AnchorPkg:
+		a.Value = strings.Trim(filepath.ToSlash(a.Value), "/")
+		if strings.ContainsAny(a.Value, "*?[") {
+			return Anchor{}, fmt.Errorf("package anchor %q must not be a glob (use a path anchor)", s)
+		}
+	case AnchorSymbol:
+		if !symbolPattern.MatchString(a.Value) {
+			return Anchor{}, fmt.Errorf("symbol anchor %q must be Name or Type.Method", s)
+		}
+	}
+	return a, nil
+}
+
+// parseAnchors parses and deduplicates anchors, sorted
+func parseAnchors(anchors []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var parsed []string
+	for _, s := range anchors {
+		a, err := ParseAnchor(s)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[a.String()] {
+			seen[a.String()] = true
+			parsed = append(parsed, a.String())
+		}
+	}
+	sort.Strings(parsed)
+	return parsed, nil
+}
+
+// anchorsOf lists the anchors a holon declares among its relations
+func anchorsOf(holonID string, relations []db.Relation) []string {
+	var anchors []string
+	for _, r := range relations {
+		if r.SourceID == holonID && r.RelationType == RelationAnchoredTo {
+			anchors = append(anchors, r.TargetID)
+		}
+	}
+	sort.Strings(anchors)
+	return anchors
+}
+
+// anchorRelations reads the anchors field of a projection
+func anchorRelations(holonID, field string) []projectedRelation {
+	var relations []projectedRelation
+	for _, anchor := range splitList(field) {
+		relations = append(relations, projectedRelation{SourceID: holonID, RelationType: RelationAnchoredTo, TargetID: anchor, CL: 3})
+	}
+	return relations
+}
+
+// SetAnchors declares the code a holon or DRR covers. Anchors are added to
+// the existing ones, or replace them with replace.
+func (t *Tools) SetAnchors(holonID string, anchors []string, replace bool) (string, error) {
+	defer t.RecordWork("SetAnchors", time.Now())
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+	if err := t.checkHolonContext(holonID); err != nil {
+		return "", err
+	}
+
+	parsed, err := parseAnchors(anchors)
+	if err != nil {
+		return "", err
+	}
+	if len(parsed) == 0 && !replace {
+		return "", fmt.Errorf("at least one anchor is required")
+	}
+
+	ctx := context.Background()
+	holon, err := t.DB.GetHolon(ctx, holonID)
+	if err != nil {
+		return "", fmt.Errorf("holon %s not found", holonID)
+	}
+
+	var current []string
+	err = t.transact(func() error {
+		relations, err := t.DB.GetHolonRelations(ctx, holonID)
+		if err != nil {
+			return err
+		}
+		if replace {
+			for _, anchor := range anchorsOf(holonID, relations) {
+				if err := t.DB.DeleteRelation(ctx, holonID, anchor, RelationAnchoredTo); err != nil {
+					return err
+				}
+			}
+		}
+		for _, anchor := range parsed {
+			if err := t.DB.CreateRelation(ctx, holonID, RelationAnchoredTo, anchor, 3); err != nil {
+				return err
+			}
+		}
+
+		if relations, err = t.DB.GetHolonRelations(ctx, holonID); err != nil {
+			return err
+		}
+		current = anchorsOf(holonID, relations)
+		if holon.Type == "DRR" {
+			return t.writeDecisionProjection(ctx, holonID)
+		}
+		return t.writeHolonProjection(ctx, holonID)
+	})
+	if err != nil {
+		t.AuditLog("quint_anchor", "set_anchors", "agent", holonID, "ERROR", map[string]string{"anchors": strings.Join(anchors, ", ")}, err.Error())
+		return "", err
+	}
+
+	t.AuditLog("quint_anchor", "set_anchors", "agent", holonID, "SUCCESS",
+		map[string]string{"anchors": strings.Join(current, ", "), "replace": strconv.FormatBool(replace)}, "")
+	if len(current) == 0 {
+		return fmt.Sprintf("%s has no code anchors", holonID), nil
+	}
+	return fmt.Sprintf("Code anchors of %s: %s", holonID, strings.Join(current, ", ")), nil
+}
+
+// fileChange is one line of git diff --name-status
+type fileChange struct {
+	Status  string // A, M, D, R, C, T
+	Path    string
+	OldPath string // renames and copies
+}
+
+func parseNameStatus(output string) []fileChange {
+	var changes []fileChange
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		parts := strings.Split(scanner.Text(), "\t")
+		if len(parts) < 2 || parts[0] == "" {
+			continue
+		}
+		c := fileChange{Status: parts[0][:1], Path: parts[len(parts)-1]}
+		if len(parts) == 3 {
+			c.OldPath = parts[1]
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// anchorHit is an anchor matched by changed files
+type anchorHit struct {
+	Anchor string
+	Files  []string
+}
+
+// affectedHolon is a holon of the active context anchored to changed code
+type affectedHolon struct {
+	Holon db.Holon
+	Hits  []anchorHit
+}
+
+// anchorMatcher matches anchors against the changes between two commits
+type anchorMatcher struct {
+	root     string
+	from, to string
+	modules  map[string]string // module path → directory, loaded on first pkg anchor
+	symbols  map[string]map[string]bool
+}
+
+// anchorImpact finds the holons of the active context whose anchors match the
+// files changed between two commits
+func (t *Tools) anchorImpact(ctx context.Context, from, to string, changes []fileChange) ([]affectedHolon, error) {
+	relations, err := t.DB.ListAllRelations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &anchorMatcher{root: t.RootDir, from: from, to: to, symbols: make(map[string]map[string]bool)}
+	byHolon := make(map[string]*affectedHolon)
+	var order []string
+	for _, r := range relations {
+		if r.RelationType != RelationAnchoredTo {
+			continue
+		}
+		a, err := ParseAnchor(r.TargetID)
+		if err != nil {
+			continue
+		}
+
+		var files []string
+		for _, c := range changes {
+			if m.matches(a, c) {
+				files = append(files, c.Path)
+			}
+		}
+		if len(files) == 0 {
+			continue
+		}
+
+		affected, ok := byHolon[r.SourceID]
+		if !ok {
+			holon, err := t.DB.GetHolon(ctx, r.SourceID)
+			if err != nil || holon.ContextID != t.ContextID() {
+				continue
+			}
+			affected = &affectedHolon{Holon: holon}
+			byHolon[r.SourceID] = affected
+			order = append(order, r.SourceID)
+		}
+		affected.Hits = append(affected.Hits, anchorHit{Anchor: a.String(), Files: files})
+	}
+
+	sort.Strings(order)
+	result := make([]affectedHolon, len(order))
+	for i, id := range order {
+		result[i] = *byHolon[id]
+	}
+	return result, nil
+}
+
+func (m *anchorMatcher) matches(a Anchor, c fileChange) bool {
+	paths := []string{c.Path}
+	if c.OldPath != "" {
+		paths = append(paths, c.OldPath)
+	}
+
+	switch a.Kind {
+	case AnchorPath:
+		for _, p := range paths {
+			if matchPathGlob(a.Value, p) {
+				return true
+			}
+		}
+	case AnchorPkg:
+		dir := m.packageDir(a.Value)
+		for _, p := range paths {
+			if path.Dir(p) == dir {
+				return true
+			}
+		}
+	case AnchorSymbol:
+		if strings.HasSuffix(c.Path, ".go") || strings.HasSuffix(c.OldPath, ".go") {
+			return m.touchedSymbols(c)[a.Value]
+		}
+	}
+	return false
+}
+
+// matchPathGlob matches a slash path against a glob where ** spans any number
+// of directories. A pattern without wildcards also matches everything below it.
+func matchPathGlob(pattern, name string) bool {
+	if !strings.ContainsAny(pattern, "*?[") {
+		return name == pattern || strings.HasPrefix(name, strings.TrimSuffix(pattern, "/")+"/")
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// packageDir resolves a Go import path to its directory through the go.mod
+// files of the repository; other values are taken as directories
+func (m *anchorMatcher) packageDir(pkg string) string {
+	if m.modules == nil {
+		m.modules = make(map[string]string)
+		files, _ := m.git("ls-tree", "-r", "--name-only", m.to)
+		for _, file := range strings.Split(strings.TrimSpace(files), "\n") {
+			if path.Base(file) != "go.mod" {
+				continue
+			}
+			content, err := m.git("show", m.to+":"+file)
+			if err != nil {
+				continue
+			}
+			for _, line := range strings.Split(content, "\n") {
+				if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+					m.modules[strings.Trim(strings.TrimSpace(rest), `"`)] = path.Dir(file)
+					break
+				}
+			}
+		}
+	}
+
+	best := ""
+	for module := range m.modules {
+		if (pkg == module || strings.HasPrefix(pkg, module+"/")) && len(module) > len(best) {
+			best = module
+		}
+	}
+	if best == "" {
+		return pkg
+	}
+	return path.Clean(path.Join(m.modules[best], strings.TrimPrefix(pkg, best)))
+}
+
+// touchedSymbols returns the Go declarations a change touches: functions,
+// Type.Method (and Type) for methods, types, vars and consts whose lines
+// overlap the diff. Added and deleted files touch every declaration.
+func (m *anchorMatcher) touchedSymbols(c fileChange) map[string]bool {
+	if symbols, ok := m.symbols[c.Path]; ok {
+		return symbols
+	}
+	symbols := make(map[string]bool)
+	m.symbols[c.Path] = symbols
+
+	rev, file := m.to, c.Path
+	if c.Status == "D" {
+		rev = m.from
+	}
+	src, err := m.git("show", rev+":"+file)
+	if err != nil {
+		return symbols
+	}
+
+	var ranges [][2]int
+	if c.Status != "A" && c.Status != "D" {
+		args := []string{"diff", "-U0", m.from, m.to, "--", c.Path}
+		if c.OldPath != "" {
+			args = append(args, c.OldPath)
+		}
+		diff, err := m.git(args...)
+		if err != nil {
+			return symbols
+		}
+		ranges = changedLines(diff)
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, src, parser.SkipObjectResolution)
+	if err != nil {
+		return symbols
+	}
+	touched := func(node ast.Node) bool {
+		if c.Status == "A" || c.Status == "D" {
+			return true
+		}
+		start, end := fset.Position(node.Pos()).Line, fset.Position(node.End()).Line
+		for _, r := range ranges {
+			if r[0] <= end && r[1] >= start {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if !touched(d) {
+				continue
+			}
+			if recv := receiverType(d); recv != "" {
+				symbols[recv+"."+d.Name.Name] = true
+				symbols[recv] = true
+			} else {
+				symbols[d.Name.Name] = true
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if !touched(spec) {
+					continue
+				}
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					symbols[s.Name.Name] = true
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						symbols[name.Name] = true
+					}
+				}
+			}
+		}
+	}
+	return symbols
+}
+
+func receiverType(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+var hunkPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// changedLines returns the line ranges a unified diff touches on the new side.
+// A pure deletion touches the lines around it.
+func changedLines(diff string) [][2]int {
+	var ranges [][2]int
+	for _, line := range strings.Split(diff, "\n") {
+		match := hunkPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		start, _ := strconv.Atoi(match[1])
+		count := 1
+		if match[2] != "" {
+			count, _ = strconv.Atoi(match[2])
+		}
+		if count == 0 {
+			ranges = append(ranges, [2]int{start, start + 1})
+		} else {
+			ranges = append(ranges, [2]int{start, start + count - 1})
+		}
+	}
+	return ranges
+}
+
+func (m *anchorMatcher) git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = m.root
+	output, err := cmd.Output()
+	return string(output), err
+}
+
+// reportAnchorImpact writes the holons and evidence affected by changed files
+// and, with ShortenValidity, caps the valid_until of that evidence. An error
+// means the changes were not fully checked.
+func (t *Tools) reportAnchorImpact(report *strings.Builder, from, to string, changes []fileChange, opts ActualizeOptions) error {
+	ctx := context.Background()
+	affected, err := t.anchorImpact(ctx, from, to, changes)
+	if err != nil {
+		return fmt.Errorf("failed to check code anchors: %v", err)
+	}
+	if len(affected) == 0 {
+		report.WriteString("IMPACT: No anchored holons affected.\n")
+		return nil
+	}
+
+	report.WriteString(fmt.Sprintf("IMPACT: %d holon(s) anchored to changed code:\n", len(affected)))
+	var evidence []db.Evidence
+	seen := make(map[string]bool)
+	for _, a := range affected {
+		report.WriteString(fmt.Sprintf("- [%s] %s (%s)\n", a.Holon.Layer, a.Holon.ID, a.Holon.Title))
+		for _, hit := range a.Hits {
+			report.WriteString(fmt.Sprintf("    %s → %s\n", hit.Anchor, strings.Join(hit.Files, ", ")))
+		}
+
+		// A decision rests on the evidence of the hypothesis it selected
+		holonIDs := []string{a.Holon.ID}
+		if a.Holon.Type == "DRR" && a.Holon.ParentID.String != "" {
+			holonIDs = append(holonIDs, a.Holon.ParentID.String)
+		}
+		for _, id := range holonIDs {
+			items, _ := t.DB.GetEvidence(ctx, id)
+			for _, e := range items {
+				if seen[e.ID] {
+					continue
+				}
+				seen[e.ID] = true
+				evidence = append(evidence, e)
+				until := "no expiry"
+				if e.ValidUntil.Valid {
+					until = "valid until " + e.ValidUntil.Time.Format("2006-01-02")
+				}
+				report.WriteString(fmt.Sprintf("    evidence %s (%s)\n", e.ID, until))
+			}
+		}
+	}
+
+	if !opts.ShortenValidity || len(evidence) == 0 {
+		return nil
+	}
+	shortened, err := t.shortenValidity(ctx, evidence, opts.ValidityDays)
+	if err != nil {
+		return fmt.Errorf("failed to shorten evidence validity: %v", err)
+	}
+	report.WriteString(fmt.Sprintf("IMPACT: valid_until of %d evidence item(s) shortened to %s.\n",
+		len(shortened), time.Now().AddDate(0, 0, opts.ValidityDays).Format("2006-01-02")))
+	return nil
+}
+
+// shortenValidity caps valid_until at days from now and refreshes the R of
+// the holons concerned. Evidence that already expires earlier is left alone.
+func (t *Tools) shortenValidity(ctx context.Context, evidence []db.Evidence, days int) ([]string, error) {
+	until := time.Now().AddDate(0, 0, days)
+	var shortened []string
+	err := t.transact(func() error {
+		for _, e := range evidence {
+			if e.ValidUntil.Valid && !e.ValidUntil.Time.After(until) {
+				continue
+			}
+			if err := t.DB.UpdateEvidenceValidUntil(ctx, e.ID, until); err != nil {
+				return err
+			}
+			updated, err := t.DB.GetEvidenceByID(ctx, e.ID)
+			if err != nil {
+				return err
+			}
+			fields, body := evidenceProjection(updated)
+			if err := t.writeProjection(filepath.Join(t.GetFPFDir(), "evidence", e.ID), fields, body); err != nil {
+				return err
+			}
+			shortened = append(shortened, e.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range shortened {
+		t.AuditLog("quint_actualize", "shorten_validity", "agent", id, "SUCCESS",
+			map[string]string{"valid_until": until.Format(time.RFC3339)}, "code anchored by its holon changed")
+		t.recalculateForEvidence(id)
+	}
+	return shortened, nil
+}
@@ -0,0 +1,199 @@
+package fpf
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAnchor(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"src/cache/**", "path:src/cache/**", false},
+		{"./src/cache.go", "path:src/cache.go", false},
+		{"path:internal/*.go", "path:internal/*.go", false},
+		{"pkg:github.com/org/repo/cache/", "pkg:github.com/org/repo/cache", false},
+		{"symbol:Cache.Get", "symbol:Cache.Get", false},
+		{"symbol:NewCache", "symbol:NewCache", false},
+		{"symbol:a.b.c", "", true},
+		{"pkg:internal/*", "", true},
+		{"/etc/passwd", "", true},
+		{"../outside", "", true},
+		{"a, b", "", true},
+		{"path:[", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAnchor(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAnchor(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.String() != tt.want {
+			t.Errorf("ParseAnchor(%q) = %q, want %q", tt.input, got.String(), tt.want)
+		}
+	}
+}
+
+func TestMatchPathGlob(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"src/cache", "src/cache/lru.go", true},
+		{"src/cache", "src/cachex/lru.go", false},
+		{"src/*.go", "src/main.go", true},
+		{"src/*.go", "src/cache/lru.go", false},
+		{"src/**/*.go", "src/main.go", true},
+		{"src/**/*.go", "src/cache/lru/lru.go", true},
+		{"**/README.md", "docs/api/README.md", true},
+		{"src/**", "docs/README.md", false},
+	}
+	for _, tt := range tests {
+		if got := matchPathGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchPathGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestActualize_AnchorImpact(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.email=test@example.com", "-c", "user.name=Test"}, args...)...)
+		cmd.Dir = tempDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+	write := func(name, content string) {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cache := `package cache
+
+type Cache struct{ items map[string]string }
+
+func (c *Cache) Get(key string) string {
+	return c.items[key]
+}
+
+func (c *Cache) Put(key, value string) {
+	c.items[key] = value
+}
+`
+	git("init")
+	write("go.mod", "module example.com/app\n")
+	write("cache/cache.go", cache)
+	write("util/util.go", "package util\n\nconst Version = 1\n")
+	write("docs/README.md", "docs\n")
+	git("add", "go.mod", "cache", "util", "docs")
+	git("commit", "-m", "Initial commit")
+
+	anchors := map[string][]string{
+		"get-path":  {"symbol:Cache.Get"},
+		"put-path":  {"symbol:Cache.Put"},
+		"util-pkg":  {"pkg:example.com/app/util"},
+		"docs-only": {"docs/**"},
+	}
+	for id, a := range anchors {
+		if err := tools.DB.CreateHolon(ctx, id, "hypothesis", "system", "L2", id, "Content", tools.ContextID(), "global", ""); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tools.SetAnchors(id, a, false); err != nil {
+			t.Fatalf("SetAnchors(%s) failed: %v", id, err)
+		}
+	}
+	if err := tools.DB.AddEvidence(ctx, "get-test", "get-path", "internal", "Get works", "pass", "L2", "test-runner", "2099-01-01"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.DB.AddEvidence(ctx, "put-test", "put-path", "internal", "Put works", "pass", "L2", "test-runner", "2099-01-01"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tools.SetAnchors("get-path", []string{"a, b"}, false); err == nil {
+		t.Error("Expected an invalid anchor to be rejected")
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tools.GetFPFDir(), "knowledge", "L2", "get-path.md"))
+	if !strings.Contains(string(content), "anchors: symbol:Cache.Get") {
+		t.Errorf("Expected anchors in the hypothesis file, got:\n%s", content)
+	}
+
+	// The decision inherits the anchors of its winner
+	if _, err := tools.FinalizeDecision("Cache reads", "get-path", []string{"put-path"}, "ctx", "decision", "rationale", "consequences", ""); err != nil {
+		t.Fatalf("FinalizeDecision failed: %v", err)
+	}
+
+	if _, err := tools.Actualize(ActualizeOptions{}); err != nil {
+		t.Fatalf("Baseline Actualize failed: %v", err)
+	}
+
+	write("cache/cache.go", strings.Replace(cache, "return c.items[key]", "return c.items[key] + \"\"", 1))
+	write("util/util.go", "package util\n\nconst Version = 2\n")
+	git("commit", "-am", "Change Get and util")
+
+	report, err := tools.Actualize(ActualizeOptions{ShortenValidity: true, ValidityDays: 3})
+	if err != nil {
+		t.Fatalf("Actualize failed: %v", err)
+	}
+	for _, want := range []string{
+		"IMPACT: 3 holon(s) anchored to changed code:",
+		"get-path (get-path)",
+		"symbol:Cache.Get → cache/cache.go",
+		"util-pkg (util-pkg)",
+		"pkg:example.com/app/util → util/util.go",
+		"cache-reads (Cache reads)",
+		"evidence get-test (valid until 2099-01-01)",
+		"valid_until of 1 evidence item(s) shortened",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected %q in report:\n%s", want, report)
+		}
+	}
+	for _, unwanted := range []string{"put-path (", "docs-only"} {
+		if strings.Contains(report, unwanted) {
+			t.Errorf("Expected no %q in report:\n%s", unwanted, report)
+		}
+	}
+
+	limit := time.Now().AddDate(0, 0, 3)
+	if e, _ := tools.DB.GetEvidenceByID(ctx, "get-test"); !e.ValidUntil.Valid || e.ValidUntil.Time.After(limit) {
+		t.Errorf("Expected get-test to expire within 3 days, got %v", e.ValidUntil)
+	}
+	if e, _ := tools.DB.GetEvidenceByID(ctx, "put-test"); e.ValidUntil.Time.Year() != 2099 {
+		t.Errorf("Expected put-test to keep its validity, got %v", e.ValidUntil)
+	}
+
+	// A failed impact check keeps the baseline, so the changes are checked again
+	baseline := tools.FSM.State.LastCommit
+	write("util/util.go", "package util\n\nconst Version = 3\n")
+	git("commit", "-am", "Change util again")
+	raw := tools.DB.GetRawDB()
+	if _, err := raw.Exec("ALTER TABLE relations RENAME TO relations_hidden"); err != nil {
+		t.Fatal(err)
+	}
+	report, _ = tools.Actualize(ActualizeOptions{})
+	if !strings.Contains(report, "Warning: failed to check code anchors") || tools.FSM.State.LastCommit != baseline {
+		t.Errorf("Expected the baseline to stay at %s, got %s:\n%s", baseline, tools.FSM.State.LastCommit, report)
+	}
+	if _, err := raw.Exec("ALTER TABLE relations_hidden RENAME TO relations"); err != nil {
+		t.Fatal(err)
+	}
+	report, _ = tools.Actualize(ActualizeOptions{})
+	if !strings.Contains(report, "util-pkg (util-pkg)") || tools.FSM.State.LastCommit == baseline {
+		t.Errorf("Expected the retry to report the change and advance the baseline:\n%s", report)
+	}
+}
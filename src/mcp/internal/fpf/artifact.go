@@ -0,0 +1,208 @@
+package fpf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/assurance"
+)
+
+// ArtifactsDir holds copies of the files evidence points at, named by content hash
+func (t *Tools) ArtifactsDir() string {
+	return filepath.Join(t.GetFPFDir(), "evidence", "artifacts")
+}
+
+// Artifact is a file or commit stored as the carrier of an evidence item
+type Artifact struct {
+	Kind   string
+	Ref    string // path of the stored copy relative to the project root, or the commit SHA
+	Hash   string // SHA-256 of the file, or the commit SHA
+	Source string // original file relative to the project root; empty outside the project
+}
+
+// StoreArtifact copies a report, benchmark output or log file into the
+// artifacts directory under its content hash, or resolves a commit to its
+// full SHA. source is a path (relative to the project root or absolute) or,
+// for commits, any revision git understands. A source inside the project is
+// remembered so later edits to it, not to the copy, make the evidence stale.
+func (t *Tools) StoreArtifact(kind, source string) (Artifact, error) {
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return Artifact{}, fmt.Errorf("artifact source is required")
+	}
+
+	switch kind {
+	case assurance.ArtifactCommit:
+		cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", source+"^{commit}")
+		cmd.Dir = t.RootDir
+		output, err := cmd.Output()
+		if err != nil {
+			return Artifact{}, fmt.Errorf("commit %s not found in %s", source, t.RootDir)
+		}
+		sha := strings.TrimSpace(string(output))
+		return Artifact{Kind: kind, Ref: sha, Hash: sha}, nil
+	case assurance.ArtifactJUnit, assurance.ArtifactGoTest, assurance.ArtifactBenchmark, assurance.ArtifactLog, assurance.ArtifactFile:
+	default:
+		return Artifact{}, fmt.Errorf("artifact kind must be one of %s, got %q", strings.Join(assurance.ArtifactKinds, ", "), kind)
+	}
+
+	path := source
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(t.RootDir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("failed to read artifact: %v", err)
+	}
+	if err := validateArtifact(kind, data); err != nil {
+		return Artifact{}, fmt.Errorf("%s is not a valid %s artifact: %v", source, kind, err)
+	}
+
+	if err := os.MkdirAll(t.ArtifactsDir(), 0755); err != nil {
+		return Artifact{}, err
+	}
+	hash, err := assurance.HashFile(path)
+	if err != nil {
+		return Artifact{}, err
+	}
+	dest := filepath.Join(t.ArtifactsDir(), hash[:12]+"-"+filepath.Base(path))
+	if _, err := os.Stat(dest); os.IsNotExist(err) {
+		if err := t.writeFile(dest, data); err != nil {
+			return Artifact{}, err
+		}
+	}
+
+	ref, err := filepath.Rel(t.RootDir, dest)
+	if err != nil {
+		return Artifact{}, err
+	}
+	artifact := Artifact{Kind: kind, Ref: filepath.ToSlash(ref), Hash: hash}
+	if rel, err := filepath.Rel(t.RootDir, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		artifact.Source = filepath.ToSlash(rel)
+	}
+	return artifact, nil
+}
+
+// validateArtifact checks that test reports parse: JUnit must be XML, go test
+// output one JSON event per line
+func validateArtifact(kind string, data []byte) error {
+	switch kind {
+	case assurance.ArtifactJUnit:
+		dec := xml.NewDecoder(bytes.NewReader(data))
+		sawElement := false
+		for {
+			tok, err := dec.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if _, ok := tok.(xml.StartElement); ok {
+				sawElement = true
+			}
+		}
+		if !sawElement {
+			return fmt.Errorf("no XML elements")
+		}
+	case assurance.ArtifactGoTest:
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+		for n := 1; scanner.Scan(); n++ {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) > 0 && !json.Valid(line) {
+				return fmt.Errorf("line %d is not JSON (use go test -json)", n)
+			}
+		}
+		return scanner.Err()
+	}
+	return nil
+}
+
+// AttachArtifact stores an artifact and records it as the carrier of an
+// existing evidence item. R is re-checked against the artifact's hash from then on.
+func (t *Tools) AttachArtifact(evidenceID, kind, source string) (string, error) {
+	defer t.RecordWork("AttachArtifact", time.Now())
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+
+	ctx := context.Background()
+	var artifact Artifact
+	err := t.transact(func() error {
+		if _, err := t.DB.GetEvidenceByID(ctx, evidenceID); err != nil {
+			return fmt.Errorf("evidence %s not found", evidenceID)
+		}
+		var err error
+		if artifact, err = t.StoreArtifact(kind, source); err != nil {
+			return err
+		}
+		if err := t.DB.UpdateEvidenceArtifact(ctx, evidenceID, artifact.Kind, artifact.Ref, artifact.Hash, artifact.Source); err != nil {
+			return err
+		}
+		e, err := t.DB.GetEvidenceByID(ctx, evidenceID)
+		if err != nil {
+			return err
+		}
+		fields, body := evidenceProjection(e)
+		return t.writeProjection(filepath.Join(t.GetFPFDir(), "evidence", evidenceID), fields, body)
+	})
+	if err != nil {
+		t.AuditLog("quint_test", "attach_artifact", "agent", evidenceID, "ERROR", map[string]string{"kind": kind, "source": source}, err.Error())
+		return "", err
+	}
+
+	t.AuditLog("quint_test", "attach_artifact", "agent", evidenceID, "SUCCESS",
+		map[string]string{"kind": artifact.Kind, "ref": artifact.Ref, "hash": artifact.Hash}, "")
+	return fmt.Sprintf("Artifact attached to %s: %s (%s)", evidenceID, artifact.Ref, artifact.Kind), nil
+}
+
+// staleArtifact is an evidence item whose artifact no longer matches its hash
+type staleArtifact struct {
+	Evidence  string
+	HolonID   string
+	Type      string
+	Ref       string
+	Status    string // CHANGED or MISSING
+	HasRecipe bool
+}
+
+// staleArtifacts re-checks the source of every file artifact in the active
+// context against its recorded hash
+func (t *Tools) staleArtifacts(ctx context.Context) ([]staleArtifact, error) {
+	evidence, err := t.DB.ListEvidenceByContext(ctx, t.ContextID())
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []staleArtifact
+	for _, e := range evidence {
+		if !e.ArtifactHash.Valid || e.ArtifactKind.String == assurance.ArtifactCommit {
+			continue
+		}
+		status := ""
+		tracked := assurance.ArtifactTrackedPath(e.ArtifactRef.String, e.ArtifactSource.String)
+		hash, err := assurance.HashFile(filepath.Join(t.RootDir, filepath.FromSlash(tracked)))
+		switch {
+		case os.IsNotExist(err):
+			status = "MISSING"
+		case err != nil || hash != e.ArtifactHash.String:
+			status = "CHANGED"
+		}
+		if status != "" {
+			stale = append(stale, staleArtifact{Evidence: e.ID, HolonID: e.HolonID, Type: e.Type, Ref: tracked, Status: status, HasRecipe: e.Recipe.String != ""})
+		}
+	}
+	return stale, nil
+}
@@ -0,0 +1,93 @@
+package fpf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAttachArtifact(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+
+	if err := tools.DB.CreateHolon(ctx, "cache", "hypothesis", "system", "L2", "Cache", "Content", tools.ContextID(), "global", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.DB.AddEvidence(ctx, "cache-test", "cache", "internal", "Tests pass", "pass", "L2", "test-runner", "2099-12-31"); err != nil {
+		t.Fatal(err)
+	}
+
+	source := filepath.Join(tempDir, "test.json")
+	if err := os.WriteFile(source, []byte("not json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tools.AttachArtifact("cache-test", "gotest", "test.json"); err == nil || !strings.Contains(err.Error(), "not a valid gotest artifact") {
+		t.Errorf("Expected plain text to be rejected as gotest output, got %v", err)
+	}
+	if _, err := tools.AttachArtifact("cache-test", "tarball", "test.json"); err == nil || !strings.Contains(err.Error(), "artifact kind must be one of") {
+		t.Errorf("Expected an unknown kind to be rejected, got %v", err)
+	}
+
+	if err := os.WriteFile(source, []byte(`{"Action":"pass","Package":"cache","Test":"TestHit"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tools.AttachArtifact("cache-test", "gotest", "test.json"); err != nil {
+		t.Fatalf("AttachArtifact failed: %v", err)
+	}
+
+	e, _ := tools.DB.GetEvidenceByID(ctx, "cache-test")
+	if !strings.HasPrefix(e.ArtifactRef.String, ".quint/evidence/artifacts/") || len(e.ArtifactHash.String) != 64 {
+		t.Fatalf("Expected the artifact to be stored under its hash, got %+v", e)
+	}
+	content, err := os.ReadFile(filepath.Join(tools.GetFPFDir(), "evidence", "cache-test"))
+	if err != nil || !strings.Contains(string(content), "artifact_hash: "+e.ArtifactHash.String) {
+		t.Errorf("Expected artifact_hash in the evidence file, got %s (%v)", content, err)
+	}
+
+	if e.ArtifactSource.String != "test.json" || !strings.Contains(string(content), "artifact_source: test.json") {
+		t.Errorf("Expected the original source to be recorded, got %q", e.ArtifactSource.String)
+	}
+
+	// The stored copy is a record of what was attached; R follows the source
+	stored := filepath.Join(tempDir, filepath.FromSlash(e.ArtifactRef.String))
+	if err := os.WriteFile(stored, []byte(`{"Action":"fail"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := tools.CalculateR("cache"); !strings.Contains(result, "R_eff: 1.00") {
+		t.Errorf("Expected R to be unaffected by the stored copy, got:\n%s", result)
+	}
+
+	if err := os.WriteFile(source, []byte(`{"Action":"fail"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := tools.CalculateR("cache")
+	if err != nil {
+		t.Fatalf("CalculateR failed: %v", err)
+	}
+	if !strings.Contains(result, "R_eff: 0.10") || !strings.Contains(result, "changed since it was recorded") {
+		t.Errorf("Expected a changed artifact to drop R, got:\n%s", result)
+	}
+
+	report, _ := tools.CheckDecay("", "", "", "")
+	if !strings.Contains(report, "| cache-test | internal | ARTIFACT CHANGED |") {
+		t.Errorf("Expected the changed artifact in the freshness report, got:\n%s", report)
+	}
+}
+
+func TestAttachArtifact_Commit(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+
+	if err := tools.DB.CreateHolon(ctx, "cache", "hypothesis", "system", "L2", "Cache", "Content", tools.ContextID(), "global", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.DB.AddEvidence(ctx, "cache-test", "cache", "internal", "Tests pass", "pass", "L2", "test-runner", "2099-12-31"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tools.AttachArtifact("cache-test", "commit", "deadbeef"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected an unknown commit to be rejected, got %v", err)
+	}
+}
@@ -51,7 +51,7 @@ func TestAssuranceGuard_BlocksLowR(t *testing.T) {
 	os.WriteFile(l2File, []byte("Test hypothesis"), 0644)
 
 	// Create holon with failing evidence (R = 0.0)
-	_, err := rawDB.Exec("INSERT INTO holons (id, type, layer, title, content, context_id) VALUES ('test-holon', 'hypothesis', 'L2', 'Test', 'Content', 'ctx')")
+	_, err := rawDB.Exec("INSERT INTO holons (id, type, layer, title, content, context_id) VALUES ('test-holon', 'hypothesis', 'L2', 'Test', 'Content', 'default')")
 	if err != nil {
 		t.Fatalf("Failed to insert holon: %v", err)
 	}
@@ -87,7 +87,7 @@ func TestAssuranceGuard_AllowsHighR(t *testing.T) {
 	os.WriteFile(l2File, []byte("Good hypothesis"), 0644)
 
 	// Create holon with passing evidence (R = 1.0)
-	_, err := rawDB.Exec("INSERT INTO holons (id, type, layer, title, content, context_id) VALUES ('good-holon', 'hypothesis', 'L2', 'Good', 'Content', 'ctx')")
+	_, err := rawDB.Exec("INSERT INTO holons (id, type, layer, title, content, context_id) VALUES ('good-holon', 'hypothesis', 'L2', 'Good', 'Content', 'default')")
 	if err != nil {
 		t.Fatalf("Failed to insert holon: %v", err)
 	}
@@ -119,7 +119,7 @@ func TestAssuranceGuard_RespectsConfigurableThreshold(t *testing.T) {
 	os.WriteFile(l2File, []byte("Medium hypothesis"), 0644)
 
 	// Create holon with degraded evidence (R = 0.5)
-	_, err := rawDB.Exec("INSERT INTO holons (id, type, layer, title, content, context_id) VALUES ('medium-holon', 'hypothesis', 'L2', 'Medium', 'Content', 'ctx')")
+	_, err := rawDB.Exec("INSERT INTO holons (id, type, layer, title, content, context_id) VALUES ('medium-holon', 'hypothesis', 'L2', 'Medium', 'Content', 'default')")
 	if err != nil {
 		t.Fatalf("Failed to insert holon: %v", err)
 	}
@@ -152,7 +152,7 @@ func TestEvidenceDecay_PenalizesExpired(t *testing.T) {
 	rawDB := database.GetRawDB()
 
 	// Create holon with expired evidence
-	_, err := rawDB.Exec("INSERT INTO holons (id, type, layer, title, content, context_id) VALUES ('decay-holon', 'hypothesis', 'L2', 'Decay', 'Content', 'ctx')")
+	_, err := rawDB.Exec("INSERT INTO holons (id, type, layer, title, content, context_id) VALUES ('decay-holon', 'hypothesis', 'L2', 'Decay', 'Content', 'default')")
 	if err != nil {
 		t.Fatalf("Failed to insert holon: %v", err)
 	}
@@ -188,13 +188,53 @@ func TestEvidenceDecay_PenalizesExpired(t *testing.T) {
 	}
 }
 
+func TestAssuranceGuard_HonorsWaivers(t *testing.T) {
+	fsm, database, tempDir := setupAssuranceTestEnv(t)
+	rawDB := database.GetRawDB()
+	ctx := context.Background()
+
+	l2Dir := filepath.Join(tempDir, ".quint", "knowledge", "L2")
+	os.MkdirAll(l2Dir, 0755)
+	l2File := filepath.Join(l2Dir, "waived-holon.md")
+	os.WriteFile(l2File, []byte("Waived hypothesis"), 0644)
+
+	_, _ = rawDB.Exec("INSERT INTO holons (id, type, layer, title, content, context_id) VALUES ('waived-holon', 'hypothesis', 'L2', 'Waived', 'Content', 'default')")
+	_, _ = rawDB.Exec("INSERT INTO evidence (id, holon_id, type, content, verdict, valid_until) VALUES ('e1', 'waived-holon', 'test', 'Old test', 'pass', ?)", time.Now().Add(-24*time.Hour))
+
+	ra := fpf.RoleAssignment{Role: fpf.RoleDecider, SessionID: "test", Context: "test"}
+	ev := &fpf.EvidenceStub{URI: l2File, Type: "hypothesis", HolonID: "waived-holon"}
+
+	if ok, _ := fsm.CanTransition(fpf.PhaseOperation, ra, ev); ok {
+		t.Fatal("Expected expired evidence to block the transition")
+	}
+
+	if err := database.CreateWaiver(ctx, "w1", "e1", "alice", time.Now().AddDate(0, 0, 14), "Launch week"); err != nil {
+		t.Fatalf("CreateWaiver failed: %v", err)
+	}
+	if ok, msg := fsm.CanTransition(fpf.PhaseOperation, ra, ev); !ok {
+		t.Errorf("Expected the waiver to allow the transition, got: %s", msg)
+	}
+
+	report, _ := assurance.New(fsm.DB).CalculateReliability(ctx, "waived-holon")
+	if !strings.Contains(strings.Join(report.Factors, "\n"), "expired, waived until "+time.Now().AddDate(0, 0, 14).Format("2006-01-02")+" by alice") {
+		t.Errorf("Expected a waiver factor, got: %v", report.Factors)
+	}
+
+	if err := database.RevokeWaiver(ctx, "w1", time.Now()); err != nil {
+		t.Fatalf("RevokeWaiver failed: %v", err)
+	}
+	if ok, _ := fsm.CanTransition(fpf.PhaseOperation, ra, ev); ok {
+		t.Error("Expected a revoked waiver to block the transition again")
+	}
+}
+
 func TestAuditVisualization_ReturnsTree(t *testing.T) {
 	_, database, tempDir := setupAssuranceTestEnv(t)
 	rawDB := database.GetRawDB()
 
 	// Create holon hierarchy: Parent -> Child
-	_, _ = rawDB.Exec("INSERT INTO holons (id, type, layer, title, content, context_id) VALUES ('parent', 'hypothesis', 'L2', 'Parent', 'Content', 'ctx')")
-	_, _ = rawDB.Exec("INSERT INTO holons (id, type, layer, title, content, context_id) VALUES ('child', 'hypothesis', 'L2', 'Child', 'Content', 'ctx')")
+	_, _ = rawDB.Exec("INSERT INTO holons (id, type, layer, title, content, context_id) VALUES ('parent', 'hypothesis', 'L2', 'Parent', 'Content', 'default')")
+	_, _ = rawDB.Exec("INSERT INTO holons (id, type, layer, title, content, context_id) VALUES ('child', 'hypothesis', 'L2', 'Child', 'Content', 'default')")
 
 	// Add passing evidence
 	future := time.Now().Add(24 * time.Hour)
@@ -0,0 +1,142 @@
+package fpf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Measurement scales of the C.16 characteristic space
+const (
+	ScaleNominal  = "nominal"
+	ScaleOrdinal  = "ordinal"
+	ScaleInterval = "interval"
+	ScaleRatio    = "ratio"
+)
+
+// CharacteristicScales lists the accepted scales, weakest first
+var CharacteristicScales = []string{ScaleNominal, ScaleOrdinal, ScaleInterval, ScaleRatio}
+
+// Characterize records one characteristic of a holon. Interval and ratio values
+// must be numeric, and ratio values cannot be negative (the scale has a true zero).
+// Recording the same name again supersedes the previous value.
+func (t *Tools) Characterize(holonID, name, scale, value, unit string) (string, error) {
+	defer t.RecordWork("Characterize", time.Now())
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+	if err := t.checkHolonContext(holonID); err != nil {
+		return "", err
+	}
+
+	name = strings.TrimSpace(name)
+	value = strings.TrimSpace(value)
+	if name == "" || value == "" {
+		return "", fmt.Errorf("characteristic name and value are required")
+	}
+
+	scale = strings.ToLower(scale)
+	switch scale {
+	case ScaleNominal, ScaleOrdinal:
+	case ScaleInterval, ScaleRatio:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("%s scale requires a numeric value, got %q", scale, value)
+		}
+		if scale == ScaleRatio && n < 0 {
+			return "", fmt.Errorf("ratio scale requires a non-negative value, got %q", value)
+		}
+	default:
+		return "", fmt.Errorf("scale must be one of %s, got %q", strings.Join(CharacteristicScales, ", "), scale)
+	}
+
+	ctx := context.Background()
+	if _, err := t.DB.GetHolon(ctx, holonID); err != nil {
+		return "", fmt.Errorf("holon %s not found", holonID)
+	}
+
+	if err := t.DB.AddCharacteristic(ctx, uuid.New().String(), holonID, name, scale, value, unit); err != nil {
+		t.AuditLog("quint_characterize", "add_characteristic", "agent", holonID, "ERROR", map[string]string{"name": name, "scale": scale}, err.Error())
+		return "", fmt.Errorf("failed to record characteristic: %v", err)
+	}
+
+	t.AuditLog("quint_characterize", "add_characteristic", "agent", holonID, "SUCCESS",
+		map[string]string{"name": name, "scale": scale, "value": value, "unit": unit}, "")
+
+	return fmt.Sprintf("Recorded %s = %s for %s (%s scale)", name, formatMeasure(value, unit), holonID, scale), nil
+}
+
+// CharacteristicMatrix compares the winner against the rejected alternatives on
+// every recorded characteristic. Returns an empty string when none was recorded.
+func (t *Tools) CharacteristicMatrix(winnerID string, rejectedIDs []string) string {
+	if t.DB == nil {
+		return ""
+	}
+
+	ctx := context.Background()
+	holonIDs := []string{winnerID}
+	for _, id := range rejectedIDs {
+		if id != "" && id != winnerID {
+			holonIDs = append(holonIDs, id)
+		}
+	}
+
+	var names []string
+	scales := make(map[string]string)
+	cells := make(map[string]map[string]string)
+	for _, id := range holonIDs {
+		rows, err := t.DB.GetCharacteristics(ctx, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load characteristics of %s: %v\n", id, err)
+			continue
+		}
+		for _, c := range rows {
+			if _, seen := cells[c.Name]; !seen {
+				names = append(names, c.Name)
+				cells[c.Name] = make(map[string]string)
+			}
+			// Rows are oldest first, so the latest measurement wins
+			scales[c.Name] = c.Scale
+			cells[c.Name][id] = formatMeasure(c.Value, c.Unit.String)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("| Characteristic | Scale |")
+	for i, id := range holonIDs {
+		if i == 0 {
+			sb.WriteString(fmt.Sprintf(" %s (selected) |", id))
+		} else {
+			sb.WriteString(fmt.Sprintf(" %s (rejected) |", id))
+		}
+	}
+	sb.WriteString("\n|---|---|" + strings.Repeat("---|", len(holonIDs)) + "\n")
+
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("| %s | %s |", tableCell(name), scales[name]))
+		for _, id := range holonIDs {
+			cell, ok := cells[name][id]
+			if !ok {
+				cell = "-"
+			}
+			sb.WriteString(fmt.Sprintf(" %s |", tableCell(cell)))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func formatMeasure(value, unit string) string {
+	if unit == "" {
+		return value
+	}
+	return value + " " + unit
+}
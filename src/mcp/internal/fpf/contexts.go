@@ -0,0 +1,220 @@
+package fpf
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultContextID is the bounded context used until another one is created and selected
+const DefaultContextID = "default"
+
+const activeContextKey = "active_context"
+
+// ActiveContextID reads the active bounded context from the settings table.
+// Falls back to DefaultContextID when no DB is available or nothing was selected yet.
+func ActiveContextID(conn *sql.DB) string {
+	if conn == nil {
+		return DefaultContextID
+	}
+
+	var id string
+	err := conn.QueryRow("SELECT value FROM settings WHERE key = ?", activeContextKey).Scan(&id)
+	if err != nil || id == "" {
+		return DefaultContextID
+	}
+	return id
+}
+
+// ContextID returns the bounded context all reads and writes are scoped to
+func (t *Tools) ContextID() string {
+	if t.FSM == nil {
+		return DefaultContextID
+	}
+	return t.FSM.CurrentContext()
+}
+
+// checkHolonContext rejects holons of another bounded context. IDs are shared
+// by all contexts, so without it a tool could read or change another context's
+// holons. IDs the DB does not know pass; callers report those themselves.
+func (t *Tools) checkHolonContext(holonIDs ...string) error {
+	if t.DB == nil {
+		return nil
+	}
+	for _, id := range holonIDs {
+		holon, err := t.DB.GetHolon(context.Background(), id)
+		if err == nil && holon.ContextID != t.ContextID() {
+			return fmt.Errorf("%s belongs to context %s, not the active context %s", id, holon.ContextID, t.ContextID())
+		}
+	}
+	return nil
+}
+
+// ReloadActiveContext follows the active context stored in the settings table,
+// so a switch made by the CLI reaches a running server. The FSM is reloaded
+// only when the context changed.
+func (t *Tools) ReloadActiveContext() error {
+	if t.DB == nil || t.FSM == nil {
+		return nil
+	}
+	id := ActiveContextID(t.DB.GetRawDB())
+	if id == t.ContextID() {
+		return nil
+	}
+	fsm, err := LoadState(id, t.DB.GetRawDB())
+	if err != nil {
+		return err
+	}
+	t.FSM.ContextID = fsm.ContextID
+	t.FSM.State = fsm.State
+	return nil
+}
+
+// ContextFilePath returns where RecordContext stores the vocabulary and invariants
+// of the active context. The default context keeps the legacy .quint/context.md.
+func (t *Tools) ContextFilePath() string {
+	id := t.ContextID()
+	if id == DefaultContextID {
+		return filepath.Join(t.GetFPFDir(), "context.md")
+	}
+	return filepath.Join(t.GetFPFDir(), "contexts", id+".md")
+}
+
+func (t *Tools) ManageContext(action, name, description string) (string, error) {
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+
+	switch action {
+	case "create":
+		return t.CreateContext(name, description)
+	case "list":
+		return t.ListContexts()
+	case "switch":
+		return t.SwitchContext(name)
+	case "archive":
+		return t.ArchiveContext(name)
+	default:
+		return "", fmt.Errorf("unknown context action: %s (use create, list, switch or archive)", action)
+	}
+}
+
+func (t *Tools) CreateContext(name, description string) (string, error) {
+	id := t.Slugify(name)
+	if id == "" {
+		return "", fmt.Errorf("context name must contain at least one letter or digit")
+	}
+
+	ctx := context.Background()
+	if _, err := t.DB.GetContext(ctx, id); err == nil {
+		return "", fmt.Errorf("context %s already exists", id)
+	}
+
+	if err := t.DB.CreateContext(ctx, id, name, description); err != nil {
+		t.AuditLog("quint_context", "create_context", "agent", id, "ERROR", map[string]string{"name": name}, err.Error())
+		return "", fmt.Errorf("failed to create context: %v", err)
+	}
+
+	t.AuditLog("quint_context", "create_context", "agent", id, "SUCCESS", map[string]string{"name": name}, "")
+	return fmt.Sprintf("Context created: %s\nSwitch to it with quint_context action=switch name=%s", id, id), nil
+}
+
+func (t *Tools) ListContexts() (string, error) {
+	ctx := context.Background()
+	contexts, err := t.DB.ListContexts(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	active := t.ContextID()
+
+	var result strings.Builder
+	result.WriteString("## Bounded Contexts\n\n")
+	result.WriteString("| | ID | Title | Status | L0 | L1 | L2 | DRR |\n")
+	result.WriteString("|---|----|-------|--------|----|----|----|-----|\n")
+	for _, c := range contexts {
+		marker := ""
+		if c.ID == active {
+			marker = "*"
+		}
+
+		counts := make(map[string]int64)
+		rows, err := t.DB.CountHolonsByLayer(ctx, c.ID)
+		if err == nil {
+			for _, r := range rows {
+				counts[r.Layer] = r.Count
+			}
+		}
+
+		result.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %d | %d | %d | %d |\n",
+			marker, c.ID, c.Title, c.Status, counts["L0"], counts["L1"], counts["L2"], counts["DRR"]))
+	}
+	result.WriteString(fmt.Sprintf("\nActive context: %s\n", active))
+
+	return result.String(), nil
+}
+
+func (t *Tools) SwitchContext(id string) (string, error) {
+	ctx := context.Background()
+	c, err := t.DB.GetContext(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("context not found: %s", id)
+	}
+	if c.Status == "archived" {
+		return "", fmt.Errorf("context %s is archived", id)
+	}
+
+	fsm, err := LoadState(id, t.DB.GetRawDB())
+	if err != nil {
+		return "", err
+	}
+
+	if err := t.DB.SetSetting(ctx, activeContextKey, id); err != nil {
+		return "", fmt.Errorf("failed to switch context: %v", err)
+	}
+
+	previous := t.ContextID()
+	t.FSM.ContextID = fsm.ContextID
+	t.FSM.State = fsm.State
+
+	t.AuditLog("quint_context", "switch_context", "agent", id, "SUCCESS", map[string]string{"from": previous}, "")
+	return fmt.Sprintf("Switched context: %s → %s\nPhase: %s", previous, id, t.FSM.GetPhase()), nil
+}
+
+func (t *Tools) ArchiveContext(id string) (string, error) {
+	if id == DefaultContextID {
+		return "", fmt.Errorf("the default context cannot be archived")
+	}
+	if id == t.ContextID() {
+		return "", fmt.Errorf("context %s is active: switch to another context before archiving it", id)
+	}
+
+	ctx := context.Background()
+	c, err := t.DB.GetContext(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("context not found: %s", id)
+	}
+	if c.Status == "archived" {
+		return "", fmt.Errorf("context %s is already archived", id)
+	}
+
+	if err := t.DB.ArchiveContext(ctx, id); err != nil {
+		return "", fmt.Errorf("failed to archive context: %v", err)
+	}
+
+	t.AuditLog("quint_context", "archive_context", "agent", id, "SUCCESS", nil, "")
+	return fmt.Sprintf("Context archived: %s", id), nil
+}
+
+func (t *Tools) ensureContextDir() error {
+	if t.ContextID() == DefaultContextID {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Join(t.GetFPFDir(), "contexts"), 0755); err != nil {
+		return fmt.Errorf("failed to create contexts directory: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package fpf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestActiveContextID_Default(t *testing.T) {
+	if got := ActiveContextID(nil); got != DefaultContextID {
+		t.Errorf("Expected %s without DB, got %s", DefaultContextID, got)
+	}
+
+	tools, _, _ := setupTools(t)
+	if got := ActiveContextID(tools.DB.GetRawDB()); got != DefaultContextID {
+		t.Errorf("Expected %s on fresh DB, got %s", DefaultContextID, got)
+	}
+	if got := tools.ContextID(); got != DefaultContextID {
+		t.Errorf("Expected tools to start in %s, got %s", DefaultContextID, got)
+	}
+}
+
+func TestManageContext_CreateSwitchArchive(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	if _, err := tools.ManageContext("create", "Auth Service", "Token handling"); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if _, err := tools.ManageContext("create", "Auth Service", ""); err == nil {
+		t.Error("Expected duplicate create to fail")
+	}
+
+	if _, err := tools.ManageContext("switch", "auth-service", ""); err != nil {
+		t.Fatalf("switch failed: %v", err)
+	}
+	if tools.ContextID() != "auth-service" {
+		t.Errorf("Expected active context auth-service, got %s", tools.ContextID())
+	}
+	if got := ActiveContextID(tools.DB.GetRawDB()); got != "auth-service" {
+		t.Errorf("Expected persisted active context auth-service, got %s", got)
+	}
+
+	if _, err := tools.ManageContext("archive", "auth-service", ""); err == nil {
+		t.Error("Expected archiving the active context to fail")
+	}
+	if _, err := tools.ManageContext("archive", DefaultContextID, ""); err == nil {
+		t.Error("Expected archiving the default context to fail")
+	}
+
+	if _, err := tools.ManageContext("switch", DefaultContextID, ""); err != nil {
+		t.Fatalf("switch back failed: %v", err)
+	}
+	if _, err := tools.ManageContext("archive", "auth-service", ""); err != nil {
+		t.Fatalf("archive failed: %v", err)
+	}
+	if _, err := tools.ManageContext("switch", "auth-service", ""); err == nil {
+		t.Error("Expected switching to an archived context to fail")
+	}
+
+	list, err := tools.ManageContext("list", "", "")
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if !strings.Contains(list, "auth-service") || !strings.Contains(list, "archived") {
+		t.Errorf("Expected archived auth-service in list, got:\n%s", list)
+	}
+}
+
+func TestContextScoping(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+
+	if _, err := tools.ProposeHypothesis("Default Hypo", "content", "global", "system", "{}", "", nil, 3); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+
+	if _, err := tools.CreateContext("Caching", ""); err != nil {
+		t.Fatalf("CreateContext failed: %v", err)
+	}
+	if _, err := tools.SwitchContext("caching"); err != nil {
+		t.Fatalf("SwitchContext failed: %v", err)
+	}
+
+	if phase := tools.FSM.GetPhase(); phase != PhaseIdle {
+		t.Errorf("Expected fresh context to be IDLE, got %s", phase)
+	}
+
+	if _, err := tools.ProposeHypothesis("Redis Cache", "content", "global", "system", "{}", "", nil, 3); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+
+	holon, err := tools.DB.GetHolon(ctx, "redis-cache")
+	if err != nil {
+		t.Fatalf("GetHolon failed: %v", err)
+	}
+	if holon.ContextID != "caching" {
+		t.Errorf("Expected holon in context caching, got %s", holon.ContextID)
+	}
+
+	logs, err := tools.DB.GetAuditLogByContext(ctx, "caching")
+	if err != nil {
+		t.Fatalf("GetAuditLogByContext failed: %v", err)
+	}
+	if len(logs) == 0 {
+		t.Error("Expected audit log entries in context caching")
+	}
+
+	if phase := tools.FSM.DerivePhase(DefaultContextID); phase != PhaseAbduction {
+		t.Errorf("Expected default context to stay in ABDUCTION, got %s", phase)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tempDir, ".quint", "knowledge", "L0", "redis-cache.md"))
+	if !strings.Contains(string(content), "context: caching") {
+		t.Errorf("Expected context in frontmatter, got:\n%s", content)
+	}
+
+	// Holon IDs are shared across contexts
+	if _, err := tools.ProposeHypothesis("Default Hypo", "content", "global", "system", "{}", "", nil, 3); err == nil || !strings.Contains(err.Error(), "already used in context default") {
+		t.Errorf("Expected a cross-context ID collision error, got %v", err)
+	}
+	for name, call := range map[string]func() error{
+		"check evidence": func() error {
+			_, _, err := tools.ManageEvidence(PhaseInduction, "check", "default-hypo", "", "", "", "", "", "")
+			return err
+		},
+		"add evidence": func() error {
+			_, _, err := tools.ManageEvidence(PhaseInduction, "add", "default-hypo", "test", "ok", "PASS", "L2", "test-runner", "")
+			return err
+		},
+		"calculate R": func() error { _, err := tools.CalculateR("default-hypo"); return err },
+		"audit tree":  func() error { _, err := tools.VisualizeAudit("default-hypo"); return err },
+		"characterize": func() error {
+			_, err := tools.Characterize("default-hypo", "latency", "ratio", "5", "ms")
+			return err
+		},
+		"move":    func() error { _, err := tools.MoveHypothesis("default-hypo", "L0", "L1"); return err },
+		"anchors": func() error { _, err := tools.SetAnchors("default-hypo", []string{"src/**"}, false); return err },
+		"depends on": func() error {
+			_, err := tools.ProposeHypothesis("Layered Cache", "content", "global", "system", "{}", "", []string{"default-hypo"}, 3)
+			return err
+		},
+	} {
+		if err := call(); err == nil || !strings.Contains(err.Error(), "belongs to context default") {
+			t.Errorf("Expected %s on a holon from another context to fail, got %v", name, err)
+		}
+	}
+	if holon, _ := tools.DB.GetHolon(ctx, "default-hypo"); holon.Layer != "L0" {
+		t.Errorf("Expected the other context's holon to stay in L0, got %s", holon.Layer)
+	}
+	if err := tools.DB.AddEvidence(ctx, "default-test", "default-hypo", "internal", "Old run", "pass", "L1", "test-runner", "2020-01-01"); err != nil {
+		t.Fatal(err)
+	}
+	if report, err := tools.generateFreshnessReport(); err != nil || strings.Contains(report, "default-hypo") {
+		t.Errorf("Expected the freshness report to skip other contexts, got %v:\n%s", err, report)
+	}
+
+	path, err := tools.RecordContext("Cache: fast storage.", "1. Reads are idempotent.")
+	if err != nil {
+		t.Fatalf("RecordContext failed: %v", err)
+	}
+	if path != filepath.Join(tempDir, ".quint", "contexts", "caching.md") {
+		t.Errorf("Expected per-context file, got %s", path)
+	}
+}
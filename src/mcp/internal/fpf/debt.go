@@ -0,0 +1,438 @@
+package fpf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// Debt item kinds
+const (
+	DebtStaleEvidence   = "stale_evidence"       // expired and not waived
+	DebtChangedArtifact = "changed_artifact"     // artifact changed or missing since it was recorded
+	DebtLowR            = "low_r"                // L2 holon below the assurance threshold
+	DebtExpiringWaiver  = "expiring_waiver"      // waiver that runs out within DebtWaiverHorizonDays
+	DebtUnverifiedL0    = "unverified_l0"        // hypothesis never verified
+	DebtUnexamined      = "unexamined_rejection" // DRR rejected an alternative that stayed at L0
+)
+
+// DebtWeights is the base score of one item of each kind. Stale evidence and
+// unverified hypotheses grow with age, low R with the shortfall and waivers as
+// their expiry nears.
+var DebtWeights = map[string]float64{
+	DebtStaleEvidence:   2,
+	DebtChangedArtifact: 2,
+	DebtLowR:            3,
+	DebtExpiringWaiver:  1,
+	DebtUnverifiedL0:    0.5,
+	DebtUnexamined:      1,
+}
+
+var debtKinds = []struct{ kind, title string }{
+	{DebtStaleEvidence, "Stale evidence"},
+	{DebtChangedArtifact, "Changed artifacts"},
+	{DebtLowR, "Low R L2 holons"},
+	{DebtExpiringWaiver, "Waivers near expiry"},
+	{DebtUnverifiedL0, "Unverified L0 backlog"},
+	{DebtUnexamined, "Rejected alternatives left at L0"},
+}
+
+// DebtWaiverHorizonDays is how close to expiry a waiver starts counting as debt
+const DebtWaiverHorizonDays = 14
+
+// DebtTrendPoints is how many earlier measurements the trend shows
+const DebtTrendPoints = 10
+
+// Debt output formats
+const (
+	DebtFormatMarkdown = "markdown"
+	DebtFormatJSON     = "json"
+)
+
+// DebtOptions select what EpistemicDebt reports
+type DebtOptions struct {
+	HolonID string // only this holon's items and trend
+	Format  string // markdown (default) or json
+}
+
+// DebtItem is one contribution to epistemic debt
+type DebtItem struct {
+	Kind    string  `json:"kind"`
+	HolonID string  `json:"holon_id"`
+	Subject string  `json:"subject"` // evidence or holon the item is about
+	Detail  string  `json:"detail"`
+	Score   float64 `json:"score"`
+}
+
+// HolonDebt is the debt attributed to one holon
+type HolonDebt struct {
+	HolonID  string   `json:"holon_id"`
+	Title    string   `json:"title"`
+	Layer    string   `json:"layer"`
+	Score    float64  `json:"score"`
+	Items    int      `json:"items"`
+	Previous *float64 `json:"previous,omitempty"` // score at the last measurement
+}
+
+// DebtPoint is a recorded measurement
+type DebtPoint struct {
+	At     time.Time          `json:"at"`
+	Score  float64            `json:"score"`
+	Items  int                `json:"items,omitempty"`
+	Holons map[string]float64 `json:"holons,omitempty"`
+}
+
+// DebtReport is the epistemic debt of a context
+type DebtReport struct {
+	ContextID string      `json:"context_id"`
+	HolonID   string      `json:"holon_id,omitempty"`
+	Score     float64     `json:"score"`
+	Measured  time.Time   `json:"measured_at"`
+	Items     []DebtItem  `json:"items"`
+	Holons    []HolonDebt `json:"holons"`
+	Trend     []DebtPoint `json:"trend"` // earlier measurements, oldest first
+}
+
+// EpistemicDebt scores the debt of the active context, records the
+// measurement in the audit log when it changed, and renders the report
+func (t *Tools) EpistemicDebt(opts DebtOptions) (string, error) {
+	defer t.RecordWork("EpistemicDebt", time.Now())
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+	if opts.Format == "" {
+		opts.Format = DebtFormatMarkdown
+	}
+	if opts.Format != DebtFormatMarkdown && opts.Format != DebtFormatJSON {
+		return "", fmt.Errorf("unknown format %q (use %s or %s)", opts.Format, DebtFormatMarkdown, DebtFormatJSON)
+	}
+	if opts.HolonID != "" {
+		if err := t.checkHolonContext(opts.HolonID); err != nil {
+			return "", err
+		}
+	}
+
+	report, err := t.ComputeDebt(opts.HolonID)
+	if err != nil {
+		return "", err
+	}
+	if opts.Format == DebtFormatJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+	return formatDebtReport(report), nil
+}
+
+// ComputeDebt collects the debt items of the active context, or of one holon
+func (t *Tools) ComputeDebt(holonID string) (*DebtReport, error) {
+	ctx := context.Background()
+	contextID := t.ContextID()
+
+	holons, err := t.DB.ListHolonsByContext(ctx, contextID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holons: %w", err)
+	}
+	byID := make(map[string]db.Holon, len(holons))
+	for _, h := range holons {
+		byID[h.ID] = h
+	}
+	if holonID != "" {
+		if _, ok := byID[holonID]; !ok {
+			return nil, fmt.Errorf("holon %s not found in context %s", holonID, contextID)
+		}
+	}
+
+	now := time.Now()
+	var items []DebtItem
+	add := func(kind, holon, subject, detail string, factor float64) {
+		items = append(items, DebtItem{Kind: kind, HolonID: holon, Subject: subject, Detail: detail, Score: round2(DebtWeights[kind] * factor)})
+	}
+
+	// Evidence: changed artifacts first, then expired without a waiver
+	evidence, err := t.DB.ListEvidenceByContext(ctx, contextID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list evidence: %w", err)
+	}
+	artifacts, err := t.staleArtifacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	changed := make(map[string]staleArtifact, len(artifacts))
+	for _, a := range artifacts {
+		changed[a.Evidence] = a
+	}
+	inContext := make(map[string]db.Evidence, len(evidence))
+	for _, e := range evidence {
+		inContext[e.ID] = e
+		if a, ok := changed[e.ID]; ok {
+			add(DebtChangedArtifact, e.HolonID, e.ID, fmt.Sprintf("artifact %s %s", a.Ref, strings.ToLower(a.Status)), 1)
+			continue
+		}
+		if !e.ValidUntil.Valid || !e.ValidUntil.Time.Before(now) {
+			continue
+		}
+		if _, err := t.DB.GetActiveWaiverForEvidence(ctx, e.ID); err == nil {
+			continue
+		}
+		days := int(now.Sub(e.ValidUntil.Time).Hours() / 24)
+		add(DebtStaleEvidence, e.HolonID, e.ID, fmt.Sprintf("expired %d days ago", days), 1+math.Min(float64(days)/30, 2))
+	}
+
+	waivers, err := t.DB.GetAllActiveWaivers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list waivers: %w", err)
+	}
+	horizon := float64(DebtWaiverHorizonDays)
+	for _, w := range waivers {
+		e, ok := inContext[w.EvidenceID]
+		daysLeft := w.WaivedUntil.Sub(now).Hours() / 24
+		if !ok || daysLeft > horizon {
+			continue
+		}
+		add(DebtExpiringWaiver, e.HolonID, w.EvidenceID,
+			fmt.Sprintf("waiver by %s expires %s", w.WaivedBy, w.WaivedUntil.Format("2006-01-02")), 1-daysLeft/(2*horizon))
+	}
+
+	threshold := 0.8
+	if t.FSM != nil {
+		threshold = t.FSM.GetAssuranceThreshold()
+	}
+	calc := t.newCalculator()
+	relations, err := t.DB.ListAllRelations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list relations: %w", err)
+	}
+	for _, h := range holons {
+		switch {
+		case h.Type == "DRR":
+			for _, r := range relations {
+				if r.SourceID != h.ID || r.RelationType != "rejects" {
+					continue
+				}
+				if alt, ok := byID[r.TargetID]; ok && alt.Layer == "L0" {
+					add(DebtUnexamined, h.ID, alt.ID, fmt.Sprintf("rejected %s was never verified", alt.ID), 1)
+				}
+			}
+		case h.Layer == "L0":
+			days := 0
+			if h.CreatedAt.Valid {
+				days = int(now.Sub(h.CreatedAt.Time).Hours() / 24)
+			}
+			add(DebtUnverifiedL0, h.ID, h.ID, fmt.Sprintf("unverified for %d days", days), 1+math.Min(float64(days)/30, 3))
+		case h.Layer == "L2":
+			assurance, err := calc.CalculateReliability(ctx, h.ID)
+			if err != nil || assurance.FinalScore >= threshold {
+				continue
+			}
+			add(DebtLowR, h.ID, h.ID, fmt.Sprintf("R %.2f below threshold %.2f", assurance.FinalScore, threshold), (threshold-assurance.FinalScore)/threshold)
+		}
+	}
+
+	report := &DebtReport{ContextID: contextID, Measured: now}
+	perHolon := make(map[string]*HolonDebt)
+	for _, item := range items {
+		report.Score += item.Score
+		hd, ok := perHolon[item.HolonID]
+		if !ok {
+			h := byID[item.HolonID]
+			hd = &HolonDebt{HolonID: h.ID, Title: h.Title, Layer: h.Layer}
+			perHolon[item.HolonID] = hd
+		}
+		hd.Score += item.Score
+		hd.Items++
+	}
+	report.Score = round2(report.Score)
+
+	current := DebtPoint{At: now, Score: report.Score, Items: len(items), Holons: make(map[string]float64)}
+	for id, hd := range perHolon {
+		hd.Score = round2(hd.Score)
+		current.Holons[id] = hd.Score
+	}
+
+	history := t.debtHistory(ctx)
+	if len(history) == 0 || !sameDebt(history[len(history)-1], current) {
+		if data, err := json.Marshal(current); err == nil {
+			t.AuditLog("quint_debt", "debt_score", "agent", contextID, "SUCCESS", nil, string(data))
+		}
+	}
+	if len(history) > DebtTrendPoints {
+		history = history[len(history)-DebtTrendPoints:]
+	}
+
+	var last *DebtPoint
+	if len(history) > 0 {
+		last = &history[len(history)-1]
+	}
+	for _, hd := range perHolon {
+		if last != nil {
+			previous := last.Holons[hd.HolonID]
+			hd.Previous = &previous
+		}
+		report.Holons = append(report.Holons, *hd)
+	}
+	sort.Slice(report.Holons, func(i, j int) bool {
+		if report.Holons[i].Score != report.Holons[j].Score {
+			return report.Holons[i].Score > report.Holons[j].Score
+		}
+		return report.Holons[i].HolonID < report.Holons[j].HolonID
+	})
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Score > items[j].Score })
+	report.Items = items
+	report.Trend = history
+
+	if holonID != "" {
+		return report.forHolon(holonID), nil
+	}
+	return report, nil
+}
+
+// forHolon narrows a context report to one holon, with that holon's trend
+func (r *DebtReport) forHolon(holonID string) *DebtReport {
+	narrowed := &DebtReport{ContextID: r.ContextID, HolonID: holonID, Measured: r.Measured, Items: []DebtItem{}, Holons: []HolonDebt{}}
+	for _, item := range r.Items {
+		if item.HolonID == holonID {
+			narrowed.Items = append(narrowed.Items, item)
+			narrowed.Score += item.Score
+		}
+	}
+	narrowed.Score = round2(narrowed.Score)
+	for _, hd := range r.Holons {
+		if hd.HolonID == holonID {
+			narrowed.Holons = append(narrowed.Holons, hd)
+		}
+	}
+	for _, p := range r.Trend {
+		// Per-holon item counts are not recorded, only scores
+		narrowed.Trend = append(narrowed.Trend, DebtPoint{At: p.At, Score: p.Holons[holonID]})
+	}
+	return narrowed
+}
+
+// debtHistory reads the recorded measurements of the active context, oldest first
+func (t *Tools) debtHistory(ctx context.Context) []DebtPoint {
+	logs, err := t.DB.GetAuditLogByContext(ctx, t.ContextID())
+	if err != nil {
+		return nil
+	}
+	var points []DebtPoint
+	for i := len(logs) - 1; i >= 0; i-- {
+		entry := logs[i]
+		if entry.ToolName != "quint_debt" || entry.Operation != "debt_score" || !entry.Details.Valid {
+			continue
+		}
+		var p DebtPoint
+		if json.Unmarshal([]byte(entry.Details.String), &p) != nil {
+			continue
+		}
+		if p.At.IsZero() && entry.Timestamp.Valid {
+			p.At = entry.Timestamp.Time
+		}
+		points = append(points, p)
+	}
+	// The recorded measurement time is finer than the audit timestamp
+	sort.SliceStable(points, func(i, j int) bool { return points[i].At.Before(points[j].At) })
+	return points
+}
+
+func sameDebt(a, b DebtPoint) bool {
+	if a.Score != b.Score || a.Items != b.Items || len(a.Holons) != len(b.Holons) {
+		return false
+	}
+	for id, score := range a.Holons {
+		if b.Holons[id] != score {
+			return false
+		}
+	}
+	return true
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func formatDebtReport(r *DebtReport) string {
+	var b strings.Builder
+	if r.HolonID != "" {
+		b.WriteString(fmt.Sprintf("## Epistemic Debt: %s (context %s)\n\n", r.HolonID, r.ContextID))
+	} else {
+		b.WriteString(fmt.Sprintf("## Epistemic Debt: %s\n\n", r.ContextID))
+	}
+
+	b.WriteString(fmt.Sprintf("**Score: %.2f**", r.Score))
+	if len(r.Trend) > 0 {
+		last := r.Trend[len(r.Trend)-1]
+		b.WriteString(fmt.Sprintf(" (%s since %s)", formatDelta(r.Score-last.Score), last.At.Format("2006-01-02 15:04")))
+	} else {
+		b.WriteString(" (first measurement)")
+	}
+	b.WriteString("\n\n")
+
+	if len(r.Items) == 0 {
+		b.WriteString("No epistemic debt ✓\n")
+	} else {
+		b.WriteString("| Kind | Items | Score |\n|------|-------|-------|\n")
+		for _, k := range debtKinds {
+			count, score := 0, 0.0
+			for _, item := range r.Items {
+				if item.Kind == k.kind {
+					count++
+					score += item.Score
+				}
+			}
+			if count > 0 {
+				b.WriteString(fmt.Sprintf("| %s | %d | %.2f |\n", k.title, count, score))
+			}
+		}
+
+		b.WriteString("\n### Holons\n\n| Holon | Layer | Score | Trend | Items |\n|-------|-------|-------|-------|-------|\n")
+		for _, hd := range r.Holons {
+			trend := "new"
+			if hd.Previous != nil {
+				trend = formatDelta(hd.Score - *hd.Previous)
+			}
+			b.WriteString(fmt.Sprintf("| %s | %s | %.2f | %s | %d |\n", hd.HolonID, hd.Layer, hd.Score, trend, hd.Items))
+		}
+
+		b.WriteString("\n### Contributing Items\n\n")
+		titles := make(map[string]string, len(debtKinds))
+		for _, k := range debtKinds {
+			titles[k.kind] = k.title
+		}
+		for _, item := range r.Items {
+			subject := item.Subject
+			if subject != item.HolonID {
+				subject = item.HolonID + " / " + subject
+			}
+			b.WriteString(fmt.Sprintf("- %.2f **%s** %s: %s\n", item.Score, titles[item.Kind], subject, item.Detail))
+		}
+	}
+
+	if len(r.Trend) > 0 {
+		b.WriteString("\n### Trend\n\n| Measured | Score |\n|----------|-------|\n")
+		for _, p := range r.Trend {
+			b.WriteString(fmt.Sprintf("| %s | %.2f |\n", p.At.Format("2006-01-02 15:04"), p.Score))
+		}
+		b.WriteString(fmt.Sprintf("| now | %.2f |\n", r.Score))
+	}
+	return b.String()
+}
+
+func formatDelta(d float64) string {
+	switch {
+	case d > 0.005:
+		return fmt.Sprintf("▲ +%.2f", d)
+	case d < -0.005:
+		return fmt.Sprintf("▼ %.2f", d)
+	default:
+		return "= 0.00"
+	}
+}
@@ -0,0 +1,103 @@
+package fpf
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEpistemicDebt(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+	contextID := tools.ContextID()
+
+	holons := []struct{ id, typ, layer string }{
+		{"stale", "hypothesis", "L1"},
+		{"waived", "hypothesis", "L1"},
+		{"weak", "hypothesis", "L2"},
+		{"idea", "hypothesis", "L0"},
+		{"choice", "DRR", "DRR"},
+	}
+	for _, h := range holons {
+		if err := tools.DB.CreateHolon(ctx, h.id, h.typ, "system", h.layer, h.id, "Content", contextID, "global", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tools.DB.AddEvidence(ctx, "stale-test", "stale", "internal", "Old run", "pass", "L2", "test-runner", "2020-01-01"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.DB.AddEvidence(ctx, "waived-test", "waived", "internal", "Old run", "pass", "L2", "test-runner", "2020-01-01"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.DB.CreateWaiver(ctx, "w1", "waived-test", "lead", time.Now().AddDate(0, 0, 7), "Re-test after launch"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.DB.CreateRelation(ctx, "choice", "rejects", "idea", 3); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := tools.EpistemicDebt(DebtOptions{})
+	if err != nil {
+		t.Fatalf("EpistemicDebt failed: %v", err)
+	}
+	for _, want := range []string{
+		"## Epistemic Debt: " + contextID,
+		"(first measurement)",
+		"| Stale evidence | 1 | 6.00 |",
+		"| Waivers near expiry | 1 | 0.75 |",
+		"| Low R L2 holons | 1 |",
+		"| Unverified L0 backlog | 1 | 0.50 |",
+		"| Rejected alternatives left at L0 | 1 | 1.00 |",
+		"**Stale evidence** stale / stale-test: expired",
+		"**Rejected alternatives left at L0** choice / idea: rejected idea was never verified",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected %q in report:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "waived-test: expired") {
+		t.Errorf("Expected waived evidence not to count as stale:\n%s", report)
+	}
+
+	// An unchanged score is not recorded again
+	if _, err := tools.EpistemicDebt(DebtOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if history := tools.debtHistory(ctx); len(history) != 1 {
+		t.Fatalf("Expected one recorded measurement, got %d", len(history))
+	}
+
+	if err := tools.DB.CreateHolon(ctx, "another-idea", "hypothesis", "system", "L0", "another-idea", "Content", contextID, "global", ""); err != nil {
+		t.Fatal(err)
+	}
+	output, err := tools.EpistemicDebt(DebtOptions{Format: DebtFormatJSON})
+	if err != nil {
+		t.Fatalf("EpistemicDebt failed: %v", err)
+	}
+	var debt DebtReport
+	if err := json.Unmarshal([]byte(output), &debt); err != nil {
+		t.Fatalf("Expected JSON output, got %v:\n%s", err, output)
+	}
+	if len(debt.Trend) != 1 || round2(debt.Score-debt.Trend[0].Score) != 0.5 || len(debt.Items) != 6 {
+		t.Errorf("Expected the new L0 to add 0.50 to the trend, got %+v", debt)
+	}
+	for _, hd := range debt.Holons {
+		if hd.HolonID == "another-idea" && (hd.Previous == nil || *hd.Previous != 0) {
+			t.Errorf("Expected a new holon to start from 0, got %+v", hd)
+		}
+	}
+
+	single, err := tools.EpistemicDebt(DebtOptions{HolonID: "stale"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(single, "**Score: 6.00** (= 0.00 since") || strings.Contains(single, "idea") {
+		t.Errorf("Expected only the stale holon, got:\n%s", single)
+	}
+
+	if _, err := tools.EpistemicDebt(DebtOptions{Format: "yaml"}); err == nil {
+		t.Error("Expected an unknown format to be rejected")
+	}
+}
@@ -0,0 +1,500 @@
+package fpf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// Doctor issue categories, in report order
+const (
+	IssueLayerMismatch         = "layer_mismatch"
+	IssueMissingProjection     = "missing_projection"
+	IssueOrphanProjection      = "orphan_projection"
+	IssueTamperedProjection    = "tampered_projection"
+	IssueStaleFrontmatter      = "stale_frontmatter"
+	IssueEvidenceMissingFile   = "evidence_missing_file"
+	IssueEvidenceMissingRow    = "evidence_missing_row"
+	IssueDecisionMissingHolon  = "decision_missing_holon"
+	IssueDecisionMissingFile   = "decision_missing_file"
+	IssueDanglingRelation      = "dangling_relation"
+	hypothesisHeadingPrefix    = "# Hypothesis: "
+	decisionFilePrefixDateSize = len("DRR-2006-01-02-")
+)
+
+var issueTitles = []struct{ category, title string }{
+	{IssueLayerMismatch, "Layer mismatch"},
+	{IssueMissingProjection, "Holons without a file"},
+	{IssueOrphanProjection, "Files without a holon"},
+	{IssueTamperedProjection, "Tampered projections"},
+	{IssueStaleFrontmatter, "Frontmatter out of date"},
+	{IssueEvidenceMissingFile, "Evidence rows without a file"},
+	{IssueEvidenceMissingRow, "Evidence files without a row"},
+	{IssueDecisionMissingHolon, "DRRs without a holon"},
+	{IssueDecisionMissingFile, "DRR holons without a file"},
+	{IssueDanglingRelation, "Dangling relations"},
+}
+
+var knowledgeLayers = []string{"L0", "L1", "L2", "invalid"}
+
+// DoctorIssue is one inconsistency between the markdown projection and the DB
+type DoctorIssue struct {
+	Category string
+	Subject  string
+	Detail   string
+	Fixed    bool
+	FixError string
+}
+
+// DoctorReport is the result of a consistency check across all contexts
+type DoctorReport struct {
+	Holons    int
+	Evidence  int
+	Decisions int
+	Relations int
+	Fix       bool
+	Issues    []DoctorIssue
+}
+
+// Unresolved counts the issues that are still present
+func (r *DoctorReport) Unresolved() int {
+	n := 0
+	for _, issue := range r.Issues {
+		if !issue.Fixed {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *DoctorReport) String() string {
+	var sb strings.Builder
+	sb.WriteString("## Doctor Report\n\n")
+	sb.WriteString(fmt.Sprintf("Checked %d holons, %d evidence records, %d decisions and %d relations.\n\n",
+		r.Holons, r.Evidence, r.Decisions, r.Relations))
+
+	if len(r.Issues) == 0 {
+		sb.WriteString("No issues found. Files and database are consistent.\n")
+		return sb.String()
+	}
+
+	for _, c := range issueTitles {
+		var lines []string
+		for _, issue := range r.Issues {
+			if issue.Category != c.category {
+				continue
+			}
+			line := fmt.Sprintf("- `%s`: %s", issue.Subject, issue.Detail)
+			switch {
+			case issue.Fixed:
+				line += " (fixed)"
+			case issue.FixError != "":
+				line += fmt.Sprintf(" (fix failed: %s)", issue.FixError)
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) > 0 {
+			sb.WriteString(fmt.Sprintf("### %s (%d)\n", c.title, len(lines)))
+			sb.WriteString(strings.Join(lines, "\n"))
+			sb.WriteString("\n\n")
+		}
+	}
+
+	unresolved := r.Unresolved()
+	sb.WriteString(fmt.Sprintf("%d issue(s) found, %d fixed.", len(r.Issues), len(r.Issues)-unresolved))
+	if unresolved > 0 && !r.Fix {
+		sb.WriteString(" Run `quint-code doctor --fix` (or quint_doctor with fix=true) to repair.")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// doctorRun holds the state of one Doctor call
+type doctorRun struct {
+	t        *Tools
+	ctx      context.Context
+	report   *DoctorReport
+	holons   map[string]db.Holon
+	evidence map[string]db.Evidence
+}
+
+// Doctor cross-checks the markdown projection against the database: layer
+// directories against holons.layer, frontmatter hashes, evidence and DRR files
+// against their rows, and relation endpoints. With fix, the DB is treated as
+// authoritative for existing rows (files are regenerated) and files without a
+// row are imported. Every repair is its own unit of work.
+func (t *Tools) Doctor(fix bool) (*DoctorReport, error) {
+	defer t.RecordWork("Doctor", time.Now())
+	if t.DB == nil {
+		return nil, fmt.Errorf("DB not initialized")
+	}
+
+	run := &doctorRun{
+		t:        t,
+		ctx:      context.Background(),
+		report:   &DoctorReport{Fix: fix},
+		holons:   make(map[string]db.Holon),
+		evidence: make(map[string]db.Evidence),
+	}
+
+	holons, err := t.DB.ListAllHolons(run.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holons: %v", err)
+	}
+	for _, h := range holons {
+		run.holons[h.ID] = h
+	}
+	evidence, err := t.DB.ListAllEvidence(run.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list evidence: %v", err)
+	}
+	for _, e := range evidence {
+		run.evidence[e.ID] = e
+	}
+	run.report.Holons = len(holons)
+	run.report.Evidence = len(evidence)
+
+	run.checkKnowledge()
+	run.checkEvidence()
+	run.checkDecisions()
+	if err := run.checkRelations(); err != nil {
+		return nil, err
+	}
+
+	t.AuditLog("quint_doctor", "check", "agent", "", "SUCCESS",
+		map[string]string{"fix": fmt.Sprintf("%t", fix)}, fmt.Sprintf("%d issue(s), %d unresolved", len(run.report.Issues), run.report.Unresolved()))
+	return run.report, nil
+}
+
+// add records an issue and, in fix mode, repairs it as one unit of work
+func (r *doctorRun) add(category, subject, detail string, repair func() error) {
+	issue := DoctorIssue{Category: category, Subject: subject, Detail: detail}
+	if r.report.Fix && repair != nil {
+		if err := r.t.transact(repair); err != nil {
+			issue.FixError = err.Error()
+		} else {
+			issue.Fixed = true
+			r.t.AuditLog("quint_doctor", "repair", "agent", subject, "SUCCESS", map[string]string{"category": category}, detail)
+		}
+	}
+	r.report.Issues = append(r.report.Issues, issue)
+}
+
+func (r *doctorRun) knowledgePath(layer, id string) string {
+	return filepath.Join(r.t.GetFPFDir(), "knowledge", layer, id+".md")
+}
+
+func (r *doctorRun) checkKnowledge() {
+	files := make(map[string][]string) // holon ID -> layers with a file
+	for _, layer := range knowledgeLayers {
+		matches, _ := filepath.Glob(r.knowledgePath(layer, "*"))
+		for _, path := range matches {
+			id := strings.TrimSuffix(filepath.Base(path), ".md")
+			files[id] = append(files[id], layer)
+		}
+	}
+
+	ids := make([]string, 0, len(files))
+	for id := range files {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		layers := files[id]
+		holon, ok := r.holons[id]
+		if !ok {
+			r.checkOrphan(id, layers)
+			continue
+		}
+
+		inLayer := false
+		for _, layer := range layers {
+			if layer == holon.Layer {
+				inLayer = true
+				continue
+			}
+			stray := r.knowledgePath(layer, id)
+			r.add(IssueLayerMismatch, id, fmt.Sprintf("file in %s, DB says %s", layer, holon.Layer), func() error {
+				if _, err := os.Stat(r.knowledgePath(holon.Layer, id)); os.IsNotExist(err) {
+					if err := r.t.writeHolonProjection(r.ctx, id); err != nil {
+						return err
+					}
+				}
+				return r.t.removeFile(stray)
+			})
+		}
+
+		if inLayer {
+			r.checkHolonFile(holon)
+		}
+	}
+
+	for _, id := range sortedKeys(r.holons) {
+		holon := r.holons[id]
+		if holon.Type == "DRR" || len(files[id]) > 0 {
+			continue
+		}
+		r.add(IssueMissingProjection, id, fmt.Sprintf("no file in knowledge/%s", holon.Layer), func() error {
+			return r.t.writeHolonProjection(r.ctx, id)
+		})
+	}
+}
+
+// checkHolonFile compares a holon's file with its row: the frontmatter hash must
+// match the file body, and the body must match the DB content
+func (r *doctorRun) checkHolonFile(holon db.Holon) {
+	path := r.knowledgePath(holon.Layer, holon.ID)
+	content, tampered, _, _, err := ValidateFile(path)
+	if err != nil {
+		return
+	}
+
+	frontmatter, body, _ := parseFrontmatter(content)
+	category, detail := IssueTamperedProjection, ""
+	switch {
+	case tampered:
+		detail = "content_hash does not match the file body"
+	case body != holon.Content:
+		detail = "file body differs from the database"
+	default:
+		expected, _, err := loadHolonProjection(r.ctx, r.t.DB, holon)
+		if err == nil && !sameFields(frontmatterFields(frontmatter), expected) {
+			category, detail = IssueStaleFrontmatter, "frontmatter does not record the holon's current claim, lineage or relations"
+		}
+	}
+	if detail == "" {
+		return
+	}
+
+	r.add(category, holon.ID, detail+" (DB is authoritative)", func() error {
+		return r.t.writeHolonProjection(r.ctx, holon.ID)
+	})
+}
+
+// sameFields compares frontmatter fields, ignoring content_hash and treating
+// a missing field as empty
+func sameFields(actual, expected map[string]string) bool {
+	for k, v := range actual {
+		if k != "content_hash" && expected[k] != v {
+			return false
+		}
+	}
+	for k, v := range expected {
+		if actual[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// checkOrphan reports hypothesis files without a holon row. A file found in a
+// single layer is imported; several copies need a human to pick one.
+func (r *doctorRun) checkOrphan(id string, layers []string) {
+	if len(layers) > 1 {
+		r.add(IssueOrphanProjection, id, fmt.Sprintf("files in %s and no holon; keep one and rerun", strings.Join(layers, ", ")), nil)
+		return
+	}
+
+	layer := layers[0]
+	r.add(IssueOrphanProjection, id, fmt.Sprintf("file in %s has no holon", layer), func() error {
+		return r.importHolonFile(r.knowledgePath(layer, id), readHypothesisFile)
+	})
+}
+
+// importHolonFile imports a hypothesis or DRR file with the relations whose
+// endpoints exist, then rewrites the file so its hash matches the import
+func (r *doctorRun) importHolonFile(path string, read func(string) (*projectedHolon, error)) error {
+	p, err := read(path)
+	if err != nil {
+		return err
+	}
+	if err := r.t.importHolon(r.ctx, p.Holon); err != nil {
+		return err
+	}
+	r.holons[p.Holon.ID] = p.Holon
+
+	for _, rel := range p.Relations {
+		_, sourceOK := r.holons[rel.SourceID]
+		_, targetOK := r.holons[rel.TargetID]
+		if !sourceOK || !targetOK && rel.RelationType != RelationAnchoredTo {
+			continue
+		}
+		if err := r.t.DB.CreateRelation(r.ctx, rel.SourceID, rel.RelationType, rel.TargetID, rel.CL); err != nil {
+			return err
+		}
+	}
+
+	fields, body, err := readProjection(path)
+	if err != nil {
+		return err
+	}
+	return r.t.writeProjection(path, fields, body)
+}
+
+func (r *doctorRun) evidencePath(id string) string {
+	return filepath.Join(r.t.GetFPFDir(), "evidence", id)
+}
+
+func (r *doctorRun) checkEvidence() {
+	matches, _ := filepath.Glob(r.evidencePath("*.md"))
+	onDisk := make(map[string]bool)
+	for _, path := range matches {
+		id := filepath.Base(path)
+		onDisk[id] = true
+
+		e, ok := r.evidence[id]
+		if !ok {
+			r.add(IssueEvidenceMissingRow, id, "evidence file has no row", func() error {
+				return r.importEvidence(path)
+			})
+			continue
+		}
+
+		content, tampered, _, _, err := ValidateFile(path)
+		if err != nil {
+			continue
+		}
+		frontmatter, body, _ := parseFrontmatter(content)
+		fields, expected := evidenceProjection(e)
+		repair := func() error {
+			return r.t.writeProjection(path, fields, expected)
+		}
+		switch {
+		case tampered || body != expected:
+			r.add(IssueTamperedProjection, id, "evidence file differs from the database (DB is authoritative)", repair)
+		case !sameFields(frontmatterFields(frontmatter), fields):
+			r.add(IssueStaleFrontmatter, id, "evidence frontmatter differs from the database (DB is authoritative)", repair)
+		}
+	}
+
+	for _, id := range sortedKeys(r.evidence) {
+		if onDisk[id] {
+			continue
+		}
+		e := r.evidence[id]
+		r.add(IssueEvidenceMissingFile, id, fmt.Sprintf("evidence for %s has no file", e.HolonID), func() error {
+			fields, body := evidenceProjection(e)
+			return r.t.writeProjection(r.evidencePath(id), fields, body)
+		})
+	}
+}
+
+func (r *doctorRun) importEvidence(path string) error {
+	e, err := readEvidenceFile(path)
+	if err != nil {
+		return err
+	}
+	if _, ok := r.holons[e.HolonID]; !ok {
+		return fmt.Errorf("target holon %q does not exist", e.HolonID)
+	}
+	if err := r.t.importEvidence(r.ctx, e); err != nil {
+		return err
+	}
+	r.evidence[e.ID] = e
+
+	fields, body, err := readProjection(path)
+	if err != nil {
+		return err
+	}
+	return r.t.writeProjection(path, fields, body)
+}
+
+func (r *doctorRun) checkDecisions() {
+	matches, _ := filepath.Glob(filepath.Join(r.t.GetFPFDir(), "decisions", "DRR-*.md"))
+	onDisk := make(map[string]bool)
+	for _, path := range matches {
+		name := strings.TrimSuffix(filepath.Base(path), ".md")
+		if len(name) <= decisionFilePrefixDateSize {
+			continue
+		}
+		id := name[decisionFilePrefixDateSize:]
+		onDisk[id] = true
+
+		if h, ok := r.holons[id]; ok && h.Type == "DRR" {
+			continue
+		}
+		r.add(IssueDecisionMissingHolon, filepath.Base(path), fmt.Sprintf("no DRR holon %s", id), func() error {
+			if err := r.importHolonFile(path, readDecisionFile); err != nil {
+				return err
+			}
+			r.report.Decisions++
+			return nil
+		})
+	}
+
+	for _, id := range sortedKeys(r.holons) {
+		holon := r.holons[id]
+		if holon.Type != "DRR" {
+			continue
+		}
+		r.report.Decisions++
+		if onDisk[id] {
+			continue
+		}
+		r.add(IssueDecisionMissingFile, id, "DRR holon has no file in decisions/", func() error {
+			return r.t.writeDecisionProjection(r.ctx, id)
+		})
+	}
+}
+
+// checkRelations runs last so that rows imported above count as endpoints.
+// verifiedBy links evidence (source) to a holon and anchoredTo a holon to code;
+// every other relation links holons.
+func (r *doctorRun) checkRelations() error {
+	relations, err := r.t.DB.ListAllRelations(r.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list relations: %v", err)
+	}
+	r.report.Relations = len(relations)
+
+	for _, rel := range relations {
+		var missing []string
+		if rel.RelationType == "verifiedBy" {
+			if _, ok := r.evidence[rel.SourceID]; !ok {
+				missing = append(missing, "evidence "+rel.SourceID)
+			}
+		} else if _, ok := r.holons[rel.SourceID]; !ok {
+			missing = append(missing, "holon "+rel.SourceID)
+		}
+		if _, ok := r.holons[rel.TargetID]; !ok && rel.RelationType != RelationAnchoredTo {
+			missing = append(missing, "holon "+rel.TargetID)
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		rel := rel
+		subject := fmt.Sprintf("%s -%s-> %s", rel.SourceID, rel.RelationType, rel.TargetID)
+		r.add(IssueDanglingRelation, subject, "missing "+strings.Join(missing, " and "), func() error {
+			if err := r.t.DB.DeleteRelation(r.ctx, rel.SourceID, rel.TargetID, rel.RelationType); err != nil {
+				return err
+			}
+			// The surviving end may list the relation in its frontmatter
+			for _, id := range []string{rel.SourceID, rel.TargetID} {
+				if h, ok := r.holons[id]; ok && h.Type != "DRR" {
+					if err := r.t.writeHolonProjection(r.ctx, id); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
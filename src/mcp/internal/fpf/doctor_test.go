@@ -0,0 +1,141 @@
+package fpf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func issueCategories(report *DoctorReport) map[string]int {
+	counts := make(map[string]int)
+	for _, issue := range report.Issues {
+		counts[issue.Category]++
+	}
+	return counts
+}
+
+func TestDoctor_Consistent(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	_, _ = tools.ProposeHypothesis("Redis", "Use Redis", "api", "system", "{}", "", nil, 3)
+	_, _ = tools.ProposeHypothesis("Memcached", "Use Memcached", "api", "system", "{}", "", nil, 3)
+	if _, _, err := tools.VerifyHypothesis("redis", passingChecks, "PASS"); err != nil {
+		t.Fatalf("VerifyHypothesis failed: %v", err)
+	}
+	if _, err := tools.FinalizeDecision("Cache Choice", "redis", []string{"memcached"}, "Context", "Redis", "Rationale", "Consequences", ""); err != nil {
+		t.Fatalf("FinalizeDecision failed: %v", err)
+	}
+
+	report, err := tools.Doctor(false)
+	if err != nil {
+		t.Fatalf("Doctor failed: %v", err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("Expected no issues, got:\n%s", report)
+	}
+	if report.Holons != 3 || report.Evidence != 1 || report.Decisions != 1 {
+		t.Errorf("Unexpected counts: %d holons, %d evidence, %d decisions", report.Holons, report.Evidence, report.Decisions)
+	}
+}
+
+func TestDoctor_DetectAndFix(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+	knowledge := filepath.Join(tempDir, ".quint", "knowledge")
+
+	_, _ = tools.ProposeHypothesis("Redis", "Use Redis", "api", "system", "{}", "", nil, 3)
+	_, _ = tools.ProposeHypothesis("Queue", "Use a queue", "api", "system", "{}", "", nil, 3)
+	_, _ = tools.ProposeHypothesis("Kafka", "Use Kafka", "api", "system", "{}", "", nil, 3)
+	if _, _, err := tools.VerifyHypothesis("redis", passingChecks, "PASS"); err != nil {
+		t.Fatalf("VerifyHypothesis failed: %v", err)
+	}
+	drrPath, err := tools.FinalizeDecision("Cache Choice", "redis", nil, "Context", "Redis", "Rationale", "Consequences", "")
+	if err != nil {
+		t.Fatalf("FinalizeDecision failed: %v", err)
+	}
+
+	// Hand edit, a file left in the old layer, a file without a row,
+	// a lost evidence file, a lost DRR row and a relation to nowhere
+	queuePath := filepath.Join(knowledge, "L0", "queue.md")
+	data, _ := os.ReadFile(queuePath)
+	_ = os.WriteFile(queuePath, []byte(strings.Replace(string(data), "Use a queue", "Use two queues", 1)), 0644)
+	kafka, _ := os.ReadFile(filepath.Join(knowledge, "L0", "kafka.md"))
+	_ = os.WriteFile(filepath.Join(knowledge, "L1", "kafka.md"), kafka, 0644)
+	_ = os.WriteFile(filepath.Join(knowledge, "L0", "ghost.md"),
+		[]byte(renderWithHash(map[string]string{"scope": "api", "kind": "system", "context": DefaultContextID}, "\n# Hypothesis: Ghost\n\nFrom a merge\n")), 0644)
+	evidenceFiles, _ := filepath.Glob(filepath.Join(tempDir, ".quint", "evidence", "*.md"))
+	if len(evidenceFiles) != 1 {
+		t.Fatalf("Expected one evidence file, got %v", evidenceFiles)
+	}
+	_ = os.Remove(evidenceFiles[0])
+	raw := tools.DB.GetRawDB()
+	if _, err := raw.Exec("DELETE FROM holons WHERE id = 'cache-choice'"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := raw.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('gone', 'queue', 'componentOf', 3)"); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := tools.Doctor(false)
+	if err != nil {
+		t.Fatalf("Doctor failed: %v", err)
+	}
+	got := issueCategories(report)
+	want := map[string]int{
+		IssueTamperedProjection:   1,
+		IssueLayerMismatch:        1,
+		IssueOrphanProjection:     1,
+		IssueEvidenceMissingFile:  1,
+		IssueDecisionMissingHolon: 1,
+		IssueDanglingRelation:     2, // componentOf from "gone", selects from the lost DRR
+	}
+	for category, n := range want {
+		if got[category] != n {
+			t.Errorf("Expected %d %s issue(s), got %d\n%s", n, category, got[category], report)
+		}
+	}
+	if report.Unresolved() != len(report.Issues) {
+		t.Error("Expected nothing fixed without fix mode")
+	}
+	if !strings.Contains(report.String(), "--fix") {
+		t.Error("Expected the report to suggest --fix")
+	}
+
+	report, err = tools.Doctor(true)
+	if err != nil {
+		t.Fatalf("Doctor(fix) failed: %v", err)
+	}
+	if n := report.Unresolved(); n != 0 {
+		t.Errorf("Expected all issues fixed, %d left:\n%s", n, report)
+	}
+
+	report, err = tools.Doctor(false)
+	if err != nil {
+		t.Fatalf("Doctor failed: %v", err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("Expected a clean report after fixing, got:\n%s", report)
+	}
+
+	data, _ = os.ReadFile(queuePath)
+	if strings.Contains(string(data), "two queues") {
+		t.Error("Expected the tampered file to be regenerated from the DB")
+	}
+	if _, err := os.Stat(filepath.Join(knowledge, "L1", "kafka.md")); err == nil {
+		t.Error("Expected the stray L1 copy to be removed")
+	}
+	if ghost, err := tools.DB.GetHolon(ctx, "ghost"); err != nil || ghost.Title != "Ghost" || ghost.Layer != "L0" {
+		t.Errorf("Expected ghost to be imported into L0, got %+v (%v)", ghost, err)
+	}
+	if drr, err := tools.DB.GetHolon(ctx, "cache-choice"); err != nil || drr.Type != "DRR" || drr.ParentID.String != "redis" {
+		t.Errorf("Expected the DRR to be re-imported from %s, got %+v (%v)", drrPath, drr, err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(tempDir, ".quint", "evidence", "*.md")); len(files) != 1 {
+		t.Errorf("Expected the evidence file to be restored, got %v", files)
+	}
+	if files := leftovers(t, tempDir); len(files) > 0 {
+		t.Errorf("Expected no staged files left, got %v", files)
+	}
+}
@@ -0,0 +1,585 @@
+package fpf
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// Drift categories, in report order
+const (
+	DriftDependency = "dependency"
+	DriftImage      = "image"
+	DriftService    = "service"
+	DriftInvariant  = "invariant"
+	DriftVocabulary = "vocabulary"
+)
+
+var driftCategories = []struct{ name, title string }{
+	{DriftDependency, "Dependencies"},
+	{DriftImage, "Base images"},
+	{DriftService, "Services"},
+	{DriftInvariant, "Invariants"},
+	{DriftVocabulary, "Vocabulary"},
+}
+
+// Drift changes
+const (
+	DriftAdded   = "added"
+	DriftRemoved = "removed"
+	DriftChanged = "changed"
+)
+
+// driftItems maps category → item → value (a version, a definition or "")
+type driftItems map[string]map[string]string
+
+func (d driftItems) add(category, item, value string) {
+	if d[category] == nil {
+		d[category] = make(map[string]string)
+	}
+	d[category][item] = value
+}
+
+// manifestParsers read the items of the project manifests actualize snapshots,
+// by file name
+var manifestParsers = map[string]func(content string) driftItems{
+	"go.mod":              parseGoMod,
+	"package.json":        parsePackageJSON,
+	"requirements.txt":    parseRequirements,
+	"Cargo.toml":          parseCargoToml,
+	"Dockerfile":          parseDockerfile,
+	"docker-compose.yml":  parseComposeServices,
+	"docker-compose.yaml": parseComposeServices,
+	"compose.yml":         parseComposeServices,
+	"compose.yaml":        parseComposeServices,
+}
+
+// DriftChange is one difference between the snapshot and the current state
+type DriftChange struct {
+	Category string
+	Source   string // file the item comes from, relative to the project root
+	Change   string // added, removed or changed
+	Item     string
+	Before   string
+	After    string
+}
+
+// DriftedHolon is a holon whose scope mentions a drifted term
+type DriftedHolon struct {
+	Holon db.Holon
+	Terms []string
+}
+
+// DriftReport compares the bounded context and project manifests with the
+// snapshot taken by the previous actualize
+type DriftReport struct {
+	Baseline bool      // no snapshot existed; the current state became the baseline
+	Since    time.Time // when the compared snapshot was taken
+	Sources  []string  // files in the new snapshot
+	Changes  []DriftChange
+	Holons   []DriftedHolon
+}
+
+// CheckContextDrift compares context.md and the project manifests with their
+// last snapshot, then replaces the snapshot with the current state
+func (t *Tools) CheckContextDrift() (*DriftReport, error) {
+	defer t.RecordWork("CheckContextDrift", time.Now())
+	if t.DB == nil {
+		return nil, fmt.Errorf("DB not initialized")
+	}
+	ctx := context.Background()
+	contextID := t.ContextID()
+
+	snapshots, err := t.DB.ListContextSnapshots(ctx, contextID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load context snapshot: %w", err)
+	}
+	previous := make(map[string]string)
+	report := &DriftReport{Baseline: len(snapshots) == 0}
+	for _, s := range snapshots {
+		previous[s.Source] = s.Content
+		if s.TakenAt.Valid && (report.Since.IsZero() || s.TakenAt.Time.Before(report.Since)) {
+			report.Since = s.TakenAt.Time
+		}
+	}
+
+	contextSource := t.contextSource()
+	current := t.driftSources(contextSource)
+	for source := range current {
+		report.Sources = append(report.Sources, source)
+	}
+	sort.Strings(report.Sources)
+
+	if !report.Baseline {
+		for _, source := range unionKeys(previous, current) {
+			parse := manifestParsers[path.Base(source)]
+			if source == contextSource {
+				parse = parseContextFile
+			}
+			if parse == nil {
+				continue
+			}
+			report.Changes = append(report.Changes, diffItems(source, parse(previous[source]), parse(current[source]))...)
+		}
+		sortDriftChanges(report.Changes)
+		if report.Holons, err = t.driftedHolons(ctx, report.Changes, parseContextFile(current[contextSource])); err != nil {
+			return nil, err
+		}
+	}
+
+	err = t.transact(func() error {
+		for source, content := range current {
+			if previous[source] == content {
+				continue
+			}
+			sum := sha256.Sum256([]byte(content))
+			if err := t.DB.UpsertContextSnapshot(ctx, contextID, source, content, hex.EncodeToString(sum[:])); err != nil {
+				return err
+			}
+		}
+		for source := range previous {
+			if _, ok := current[source]; !ok {
+				if err := t.DB.DeleteContextSnapshot(ctx, contextID, source); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save context snapshot: %w", err)
+	}
+
+	if len(report.Changes) > 0 {
+		drifted := make([]string, len(report.Holons))
+		for i, h := range report.Holons {
+			drifted[i] = h.Holon.ID
+		}
+		t.AuditLog("quint_actualize", "context_drift", "agent", contextID, "SUCCESS",
+			map[string]string{"changes": fmt.Sprintf("%d", len(report.Changes))},
+			fmt.Sprintf("holons: %s", strings.Join(drifted, ", ")))
+	}
+	return report, nil
+}
+
+// contextSource is the snapshot key of the active context's context.md
+func (t *Tools) contextSource() string {
+	rel, err := filepath.Rel(t.RootDir, t.ContextFilePath())
+	if err != nil {
+		return filepath.ToSlash(t.ContextFilePath())
+	}
+	return filepath.ToSlash(rel)
+}
+
+// driftSources reads context.md and the manifests tracked by git, or those in
+// the project root outside a git repository
+func (t *Tools) driftSources(contextSource string) map[string]string {
+	sources := make(map[string]string)
+	if content, err := os.ReadFile(filepath.Join(t.RootDir, filepath.FromSlash(contextSource))); err == nil {
+		sources[contextSource] = string(content)
+	}
+
+	var paths []string
+	cmd := exec.Command("git", "ls-files")
+	cmd.Dir = t.RootDir
+	if output, err := cmd.Output(); err == nil {
+		for _, file := range strings.Split(string(output), "\n") {
+			if manifestParsers[path.Base(file)] != nil {
+				paths = append(paths, file)
+			}
+		}
+	} else {
+		for name := range manifestParsers {
+			paths = append(paths, name)
+		}
+	}
+
+	for _, p := range paths {
+		if content, err := os.ReadFile(filepath.Join(t.RootDir, filepath.FromSlash(p))); err == nil {
+			sources[p] = string(content)
+		}
+	}
+	return sources
+}
+
+func diffItems(source string, before, after driftItems) []DriftChange {
+	var changes []DriftChange
+	for _, c := range driftCategories {
+		old, cur := before[c.name], after[c.name]
+		for _, item := range unionKeys(old, cur) {
+			was, hadIt := old[item]
+			is, hasIt := cur[item]
+			change := DriftChange{Category: c.name, Source: source, Item: item, Before: was, After: is}
+			switch {
+			case !hadIt:
+				change.Change = DriftAdded
+			case !hasIt:
+				change.Change = DriftRemoved
+			case was != is:
+				change.Change = DriftChanged
+			default:
+				continue
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func sortDriftChanges(changes []DriftChange) {
+	rank := map[string]int{DriftAdded: 0, DriftRemoved: 1, DriftChanged: 2}
+	order := make(map[string]int)
+	for i, c := range driftCategories {
+		order[c.name] = i
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if order[a.Category] != order[b.Category] {
+			return order[a.Category] < order[b.Category]
+		}
+		if rank[a.Change] != rank[b.Change] {
+			return rank[a.Change] < rank[b.Change]
+		}
+		if a.Item != b.Item {
+			return a.Item < b.Item
+		}
+		return a.Source < b.Source
+	})
+}
+
+// driftedHolons finds holons of the active context whose scope mentions a
+// term of the changes. A changed invariant contributes the vocabulary terms it
+// uses.
+func (t *Tools) driftedHolons(ctx context.Context, changes []DriftChange, contextItems driftItems) ([]DriftedHolon, error) {
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	termSet := make(map[string]bool)
+	for _, c := range changes {
+		for _, term := range driftTerms(c, contextItems[DriftVocabulary]) {
+			termSet[term] = true
+		}
+	}
+	terms := make([]string, 0, len(termSet))
+	for term := range termSet {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+
+	holons, err := t.DB.ListHolonsByContext(ctx, t.ContextID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holons: %w", err)
+	}
+	var drifted []DriftedHolon
+	for _, h := range holons {
+		if h.Type == "DRR" || h.Layer == "invalid" || h.Scope.String == "" {
+			continue
+		}
+		var matched []string
+		for _, term := range terms {
+			if mentionsTerm(h.Scope.String, term) {
+				matched = append(matched, term)
+			}
+		}
+		if len(matched) > 0 {
+			drifted = append(drifted, DriftedHolon{Holon: h, Terms: matched})
+		}
+	}
+	sort.Slice(drifted, func(i, j int) bool { return drifted[i].Holon.ID < drifted[j].Holon.ID })
+	return drifted, nil
+}
+
+var versionSuffix = regexp.MustCompile(`^v\d+$`)
+
+// driftTerms are the words a holon scope would use for a changed item
+func driftTerms(c DriftChange, vocabulary map[string]string) []string {
+	switch c.Category {
+	case DriftDependency, DriftImage:
+		terms := []string{c.Item}
+		parts := strings.Split(c.Item, "/")
+		for len(parts) > 1 && versionSuffix.MatchString(parts[len(parts)-1]) {
+			parts = parts[:len(parts)-1]
+		}
+		if base := parts[len(parts)-1]; base != c.Item {
+			terms = append(terms, base)
+		}
+		return terms
+	case DriftInvariant:
+		var terms []string
+		for term := range vocabulary {
+			if mentionsTerm(c.Item, term) {
+				terms = append(terms, term)
+			}
+		}
+		return terms
+	default:
+		return []string{c.Item}
+	}
+}
+
+// mentionsTerm reports whether text contains term as a whole word, ignoring case
+func mentionsTerm(text, term string) bool {
+	pattern := `(?i)(^|[^\w-])` + regexp.QuoteMeta(term) + `($|[^\w-])`
+	matched, _ := regexp.MatchString(pattern, text)
+	return matched
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make(map[string]bool)
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	return sortedKeys(keys)
+}
+
+// FormatDriftReport renders the drift section of the actualize report
+func FormatDriftReport(r *DriftReport) string {
+	var b strings.Builder
+	if r.Baseline {
+		if len(r.Sources) == 0 {
+			b.WriteString("DRIFT: Nothing to snapshot (no context file or project manifests).\n")
+		} else {
+			b.WriteString(fmt.Sprintf("DRIFT: Recorded baseline snapshot of %s\n", strings.Join(r.Sources, ", ")))
+		}
+		return b.String()
+	}
+
+	since := "the last snapshot"
+	if !r.Since.IsZero() {
+		since = r.Since.Format("2006-01-02 15:04")
+	}
+	if len(r.Changes) == 0 {
+		b.WriteString(fmt.Sprintf("DRIFT: No context drift since %s.\n", since))
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("DRIFT: %d change(s) since %s:\n", len(r.Changes), since))
+	marks := map[string]string{DriftAdded: "+", DriftRemoved: "-", DriftChanged: "~"}
+	for _, category := range driftCategories {
+		header := false
+		for _, c := range r.Changes {
+			if c.Category != category.name {
+				continue
+			}
+			if !header {
+				b.WriteString(category.title + ":\n")
+				header = true
+			}
+			line := fmt.Sprintf("  %s %s: %s", marks[c.Change], c.Source, c.Item)
+			switch {
+			case c.Change == DriftChanged && category.name == DriftVocabulary:
+				line += fmt.Sprintf(" (was: %s)", c.Before)
+			case c.Change == DriftChanged:
+				line += fmt.Sprintf(" %s → %s", c.Before, c.After)
+			case c.After != "" && category.name != DriftVocabulary:
+				line += " " + c.After
+			case c.Before != "" && category.name != DriftVocabulary:
+				line += " " + c.Before
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	if len(r.Holons) == 0 {
+		b.WriteString("No holon scope mentions the drifted terms.\n")
+		return b.String()
+	}
+	b.WriteString("Holons whose scope mentions drifted terms:\n")
+	for _, h := range r.Holons {
+		b.WriteString(fmt.Sprintf("- [%s] %s (%s): %s\n", h.Holon.Layer, h.Holon.ID, h.Holon.Title, strings.Join(h.Terms, ", ")))
+	}
+	return b.String()
+}
+
+// parseContextFile reads the vocabulary terms and invariants RecordContext
+// writes. Invariants are compared without their numbering.
+func parseContextFile(content string) driftItems {
+	items := make(driftItems)
+	section := ""
+	termLine := regexp.MustCompile(`^[-*]\s+\*\*(.+?)\*\*:?\s*(.*)$`)
+	numbered := regexp.MustCompile(`^(\d+\.|[-*])\s+`)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "## ") {
+			section = strings.ToLower(strings.TrimSpace(line[3:]))
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		switch section {
+		case "vocabulary":
+			if m := termLine.FindStringSubmatch(line); m != nil {
+				items.add(DriftVocabulary, strings.TrimSuffix(m[1], ":"), strings.TrimSpace(m[2]))
+			}
+		case "invariants":
+			items.add(DriftInvariant, numbered.ReplaceAllString(line, ""), "")
+		}
+	}
+	return items
+}
+
+func parseGoMod(content string) driftItems {
+	items := make(driftItems)
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		switch {
+		case line == "require (":
+			inBlock = true
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "require "))
+		case !inBlock:
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) == 2 {
+			items.add(DriftDependency, fields[0], fields[1])
+		}
+	}
+	return items
+}
+
+func parsePackageJSON(content string) driftItems {
+	items := make(driftItems)
+	var manifest map[string]json.RawMessage
+	if json.Unmarshal([]byte(content), &manifest) != nil {
+		return items
+	}
+	for _, key := range []string{"dependencies", "devDependencies", "peerDependencies", "optionalDependencies"} {
+		var deps map[string]string
+		if json.Unmarshal(manifest[key], &deps) != nil {
+			continue
+		}
+		for name, version := range deps {
+			items.add(DriftDependency, name, version)
+		}
+	}
+	return items
+}
+
+var requirementName = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(\[[^\]]*\])?\s*(.*)$`)
+
+func parseRequirements(content string) driftItems {
+	items := make(driftItems)
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		if m := requirementName.FindStringSubmatch(line); m != nil {
+			items.add(DriftDependency, strings.ToLower(m[1]), strings.TrimSpace(m[3]))
+		}
+	}
+	return items
+}
+
+func parseCargoToml(content string) driftItems {
+	items := make(driftItems)
+	inDeps := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			section := strings.Trim(line, "[] ")
+			inDeps = section == "dependencies" || strings.HasSuffix(section, "-dependencies") || strings.HasSuffix(section, ".dependencies")
+			continue
+		}
+		if !inDeps || line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if name, value, ok := strings.Cut(line, "="); ok {
+			items.add(DriftDependency, strings.Trim(strings.TrimSpace(name), `"`), strings.TrimSpace(value))
+		}
+	}
+	return items
+}
+
+func parseDockerfile(content string) driftItems {
+	items := make(driftItems)
+	stages := make(map[string]bool)
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+		args := fields[1:]
+		for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			continue
+		}
+		if len(args) >= 3 && strings.EqualFold(args[1], "AS") {
+			stages[strings.ToLower(args[2])] = true
+		}
+		image := args[0]
+		if stages[strings.ToLower(image)] || image == "scratch" {
+			continue
+		}
+		name, tag := image, "latest"
+		if i := strings.Index(image, "@"); i >= 0 {
+			name, tag = image[:i], image[i+1:]
+		} else if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+			name, tag = image[:i], image[i+1:]
+		}
+		items.add(DriftImage, name, tag)
+	}
+	return items
+}
+
+// parseComposeServices reads the keys under the top-level services: mapping
+func parseComposeServices(content string) driftItems {
+	items := make(driftItems)
+	inServices := false
+	indent := -1
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		depth := len(line) - len(strings.TrimLeft(line, " \t"))
+		if depth == 0 {
+			inServices = strings.HasPrefix(trimmed, "services:")
+			indent = -1
+			continue
+		}
+		if !inServices {
+			continue
+		}
+		if indent < 0 {
+			indent = depth
+		}
+		if depth == indent && strings.HasSuffix(trimmed, ":") {
+			items.add(DriftService, strings.Trim(strings.TrimSuffix(trimmed, ":"), `"'`), "")
+		}
+	}
+	return items
+}
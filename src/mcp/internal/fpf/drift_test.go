@@ -0,0 +1,117 @@
+package fpf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManifestParsers(t *testing.T) {
+	goMod := parseGoMod("module example.com/app\n\ngo 1.24\n\nrequire github.com/google/uuid v1.6.0\n\nrequire (\n\tgithub.com/redis/go-redis/v9 v9.5.0 // indirect\n)\n")
+	if goMod[DriftDependency]["github.com/google/uuid"] != "v1.6.0" || goMod[DriftDependency]["github.com/redis/go-redis/v9"] != "v9.5.0" || len(goMod[DriftDependency]) != 2 {
+		t.Errorf("Unexpected go.mod items: %v", goMod)
+	}
+
+	pkg := parsePackageJSON(`{"name": "app", "dependencies": {"express": "^4.18.0"}, "devDependencies": {"jest": "29.0.0"}}`)
+	if pkg[DriftDependency]["express"] != "^4.18.0" || pkg[DriftDependency]["jest"] != "29.0.0" {
+		t.Errorf("Unexpected package.json items: %v", pkg)
+	}
+
+	reqs := parseRequirements("# pinned\nDjango==4.2\nrequests[socks] >= 2.0\n-r base.txt\n")
+	if reqs[DriftDependency]["django"] != "==4.2" || reqs[DriftDependency]["requests"] != ">= 2.0" || len(reqs[DriftDependency]) != 2 {
+		t.Errorf("Unexpected requirements items: %v", reqs)
+	}
+
+	cargo := parseCargoToml("[package]\nname = \"app\"\n\n[dependencies]\nserde = \"1.0\"\n\n[dev-dependencies]\ntokio = { version = \"1\" }\n")
+	if cargo[DriftDependency]["serde"] != `"1.0"` || cargo[DriftDependency]["tokio"] == "" || cargo[DriftDependency]["name"] != "" {
+		t.Errorf("Unexpected Cargo.toml items: %v", cargo)
+	}
+
+	docker := parseDockerfile("FROM --platform=linux/amd64 golang:1.24 AS build\nFROM build AS test\nFROM registry.local:5000/base\nFROM scratch\n")
+	if docker[DriftImage]["golang"] != "1.24" || docker[DriftImage]["registry.local:5000/base"] != "latest" || len(docker[DriftImage]) != 2 {
+		t.Errorf("Unexpected Dockerfile items: %v", docker)
+	}
+
+	compose := parseComposeServices("version: '3'\nservices:\n  api:\n    image: app\n    ports:\n      - \"80:80\"\n  redis:\n    image: redis:7\nvolumes:\n  data:\n")
+	if len(compose[DriftService]) != 2 {
+		t.Errorf("Unexpected compose services: %v", compose)
+	}
+	for _, service := range []string{"api", "redis"} {
+		if _, ok := compose[DriftService][service]; !ok {
+			t.Errorf("Expected service %s, got %v", service, compose)
+		}
+	}
+}
+
+func TestCheckContextDrift(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := tools.RecordContext("Cache: Redis-backed read cache. Session: Login state.", "1. A Session expires after 30 minutes. 2. The Cache is never authoritative."); err != nil {
+		t.Fatal(err)
+	}
+	write("go.mod", "module example.com/app\n\nrequire (\n\tgithub.com/google/uuid v1.6.0\n\tgithub.com/lib/pq v1.10.0\n)\n")
+	write("docker-compose.yml", "services:\n  api:\n    image: app\n  postgres:\n    image: postgres:16\n")
+
+	for id, scope := range map[string]string{
+		"pq-pool":       "Connection pooling for lib/pq and postgres",
+		"session-store": "Session handling in the api service",
+		"unrelated":     "Logging format",
+	} {
+		if err := tools.DB.CreateHolon(ctx, id, "hypothesis", "system", "L1", id, "Content", tools.ContextID(), scope, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	baseline, err := tools.CheckContextDrift()
+	if err != nil {
+		t.Fatalf("CheckContextDrift failed: %v", err)
+	}
+	if !baseline.Baseline || strings.Join(baseline.Sources, ",") != ".quint/context.md,docker-compose.yml,go.mod" {
+		t.Fatalf("Expected a baseline of three sources, got %+v", baseline)
+	}
+
+	unchanged, _ := tools.CheckContextDrift()
+	if unchanged.Baseline || len(unchanged.Changes) != 0 {
+		t.Fatalf("Expected no drift, got %+v", unchanged)
+	}
+
+	if _, err := tools.RecordContext("Cache: Redis-backed read cache. Session: Login state.", "1. A Session expires after 15 minutes. 2. The Cache is never authoritative."); err != nil {
+		t.Fatal(err)
+	}
+	write("go.mod", "module example.com/app\n\nrequire (\n\tgithub.com/google/uuid v1.7.0\n\tgithub.com/redis/go-redis/v9 v9.5.0\n)\n")
+	write("docker-compose.yml", "services:\n  api:\n    image: app\n")
+
+	drift, err := tools.CheckContextDrift()
+	if err != nil {
+		t.Fatalf("CheckContextDrift failed: %v", err)
+	}
+	report := FormatDriftReport(drift)
+	for _, want := range []string{
+		"DRIFT: 6 change(s) since",
+		"Dependencies:\n  + go.mod: github.com/redis/go-redis/v9 v9.5.0\n  - go.mod: github.com/lib/pq v1.10.0\n  ~ go.mod: github.com/google/uuid v1.6.0 → v1.7.0\n",
+		"Services:\n  - docker-compose.yml: postgres\n",
+		"Invariants:\n  + .quint/context.md: A Session expires after 15 minutes.\n  - .quint/context.md: A Session expires after 30 minutes.\n",
+		"- [L1] pq-pool (pq-pool): postgres, pq\n",
+		"- [L1] session-store (session-store): Session\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected %q in report:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "unrelated") {
+		t.Errorf("Expected unrelated holon not to be flagged:\n%s", report)
+	}
+
+	// The snapshot now holds the current state
+	if again, _ := tools.CheckContextDrift(); len(again.Changes) != 0 {
+		t.Errorf("Expected the snapshot to be replaced, got %+v", again.Changes)
+	}
+}
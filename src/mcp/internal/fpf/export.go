@@ -0,0 +1,341 @@
+package fpf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// ExportFormatJSONL writes one file per table with one JSON record per line
+const ExportFormatJSONL = "jsonl"
+
+// conflictMarkers prefix the lines git adds around a conflicting hunk. Import
+// skips them, so both sides of a conflict are read and flagged as one ID.
+var conflictMarkers = []string{"<<<<<<<", "|||||||", "=======", ">>>>>>>"}
+
+// ExportDir returns where export writes and import reads by default
+func (t *Tools) ExportDir() string {
+	return filepath.Join(t.GetFPFDir(), "export")
+}
+
+// Export writes the knowledge graph to <dir>/<table>.jsonl. Rows are sorted by
+// key and fields by name, so unchanged data exports byte for byte the same
+// and branches diff and merge line by line.
+func (t *Tools) Export(dir, format string) ([]string, error) {
+	if format != ExportFormatJSONL {
+		return nil, fmt.Errorf("unsupported export format %q (supported: %s)", format, ExportFormatJSONL)
+	}
+	if t.DB == nil {
+		return nil, fmt.Errorf("DB not initialized")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	var written []string
+	for _, table := range db.ExportTables {
+		rows, err := t.DB.ExportRows(ctx, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %v", table.Name, err)
+		}
+
+		var buf bytes.Buffer
+		for _, row := range rows {
+			line, err := json.Marshal(row)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %s %s: %v", table.Name, table.KeyOf(row), err)
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+
+		path := filepath.Join(dir, table.Name+".jsonl")
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			return nil, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// ImportConflict is a record import left alone because its ID is taken by a different record
+type ImportConflict struct {
+	Table  string
+	Key    string
+	Reason string
+}
+
+// ImportReport summarizes an import of exported records
+type ImportReport struct {
+	Inserted  map[string]int
+	Unchanged int
+	Conflicts []ImportConflict
+}
+
+func (r *ImportReport) String() string {
+	var sb strings.Builder
+	sb.WriteString("## Import Report\n\n")
+
+	var parts []string
+	for _, table := range db.ExportTables {
+		if n := r.Inserted[table.Name]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, table.Name))
+		}
+	}
+	if len(parts) == 0 {
+		sb.WriteString("Nothing new to import.")
+	} else {
+		sb.WriteString("Imported " + strings.Join(parts, ", ") + ".")
+	}
+	sb.WriteString(fmt.Sprintf(" %d record(s) already present.\n", r.Unchanged))
+
+	if len(r.Conflicts) > 0 {
+		sb.WriteString(fmt.Sprintf("\n### Conflicting IDs (%d)\n", len(r.Conflicts)))
+		for _, c := range r.Conflicts {
+			sb.WriteString(fmt.Sprintf("- %s `%s`: %s\n", c.Table, c.Key, c.Reason))
+		}
+		sb.WriteString("\nResolve each conflict in the export files, then import again.\n")
+	}
+	return sb.String()
+}
+
+// Import merges exported records into the database. New records are inserted
+// and projected into .quint/; records already present are skipped. A record
+// whose key is taken by a different record, in the database or on another
+// line of the same file (e.g. after a git merge), is reported as a conflict
+// and not imported. Cached R scores are recomputed afterwards.
+func (t *Tools) Import(dir string) (*ImportReport, error) {
+	defer t.RecordWork("Import", time.Now())
+	if t.DB == nil {
+		return nil, fmt.Errorf("DB not initialized")
+	}
+
+	report := &ImportReport{Inserted: make(map[string]int)}
+	err := t.transact(func() error {
+		ctx := context.Background()
+		inserted := make(map[string][]db.Row)
+
+		for _, table := range db.ExportTables {
+			path := filepath.Join(dir, table.Name+".jsonl")
+			records, err := readExportFile(path, table)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %v", path, err)
+			}
+
+			for _, key := range sortedKeys(records) {
+				versions := records[key]
+				if len(versions) > 1 {
+					report.Conflicts = append(report.Conflicts, ImportConflict{table.Name, key,
+						fmt.Sprintf("%d different records in %s", len(versions), filepath.Base(path))})
+					continue
+				}
+				row := versions[0]
+
+				existing, err := t.DB.GetRow(ctx, table, row)
+				if err != nil {
+					return fmt.Errorf("failed to look up %s %s: %v", table.Name, key, err)
+				}
+				if existing != nil {
+					// Every database seeds the default context with its own timestamp
+					seeded := table.Name == "contexts" && key == DefaultContextID
+					if seeded || sameRecord(existing, row) {
+						report.Unchanged++
+					} else {
+						report.Conflicts = append(report.Conflicts, ImportConflict{table.Name, key, "differs from the record in the database"})
+					}
+					continue
+				}
+
+				if err := t.DB.InsertRow(ctx, table, row); err != nil {
+					return fmt.Errorf("failed to import %s %s: %v", table.Name, key, err)
+				}
+				inserted[table.Name] = append(inserted[table.Name], row)
+				report.Inserted[table.Name]++
+			}
+		}
+
+		return t.projectImported(ctx, inserted)
+	})
+	if err != nil {
+		t.AuditLog("quint_import", "import", "user", "", "ERROR", map[string]string{"dir": dir}, err.Error())
+		return nil, err
+	}
+
+	if err := t.RunDecay(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to recompute R scores: %v\n", err)
+	}
+
+	t.AuditLog("quint_import", "import", "user", "", "SUCCESS", map[string]string{"dir": dir},
+		fmt.Sprintf("%d conflict(s)", len(report.Conflicts)))
+	return report, nil
+}
+
+// projectImported writes the files of imported holons, evidence and waivers,
+// and rewrites the holons whose relations changed
+func (t *Tools) projectImported(ctx context.Context, inserted map[string][]db.Row) error {
+	holons := make(map[string]bool)
+	for _, row := range inserted["holons"] {
+		holons[fmt.Sprint(row["id"])] = true
+	}
+	for _, row := range inserted["relations"] {
+		for _, col := range []string{"source_id", "target_id"} {
+			id := fmt.Sprint(row[col])
+			if _, err := t.DB.GetHolon(ctx, id); err == nil {
+				holons[id] = true
+			}
+		}
+	}
+
+	for _, id := range sortedKeys(holons) {
+		holon, err := t.DB.GetHolon(ctx, id)
+		if err != nil {
+			return fmt.Errorf("holon %s not found: %v", id, err)
+		}
+		if holon.Type == "DRR" {
+			err = t.writeDecisionProjection(ctx, id)
+		} else {
+			err = t.writeHolonProjection(ctx, id)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to project %s: %v", id, err)
+		}
+	}
+
+	for _, row := range inserted["evidence"] {
+		id := fmt.Sprint(row["id"])
+		e, err := t.DB.GetEvidenceByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("evidence %s not found: %v", id, err)
+		}
+		fields, body := evidenceProjection(e)
+		if err := t.writeProjection(filepath.Join(t.GetFPFDir(), "evidence", id), fields, body); err != nil {
+			return err
+		}
+	}
+
+	waiverIDs := make(map[string]bool)
+	evidenceIDs := make(map[string]bool)
+	for _, row := range inserted["waivers"] {
+		waiverIDs[fmt.Sprint(row["id"])] = true
+		evidenceIDs[fmt.Sprint(row["evidence_id"])] = true
+	}
+	for _, evidenceID := range sortedKeys(evidenceIDs) {
+		waivers, err := t.DB.GetWaiversByEvidence(ctx, evidenceID)
+		if err != nil {
+			return err
+		}
+		for _, w := range waivers {
+			if !waiverIDs[w.ID] {
+				continue
+			}
+			if err := os.MkdirAll(filepath.Join(t.GetFPFDir(), "waivers"), 0755); err != nil {
+				return err
+			}
+			fields, body := waiverProjection(w)
+			if err := t.writeProjection(filepath.Join(t.GetFPFDir(), "waivers", w.ID+".md"), fields, body); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readExportFile returns the distinct records of an export file by key.
+// Identical lines collapse; git conflict markers are skipped.
+func readExportFile(path string, table db.ExportTable) (map[string][]db.Row, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	records := make(map[string][]db.Row)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || isConflictMarker(line) {
+			continue
+		}
+
+		dec := json.NewDecoder(strings.NewReader(line))
+		dec.UseNumber()
+		var row db.Row
+		if err := dec.Decode(&row); err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		for _, col := range table.Key {
+			if v, ok := row[col].(string); !ok || v == "" {
+				return nil, fmt.Errorf("line %d: missing %s", n, col)
+			}
+		}
+
+		key := table.KeyOf(row)
+		duplicate := false
+		for _, other := range records[key] {
+			if sameRecord(other, row) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			records[key] = append(records[key], row)
+		}
+	}
+	return records, scanner.Err()
+}
+
+func isConflictMarker(line string) bool {
+	for _, marker := range conflictMarkers {
+		if strings.HasPrefix(line, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameRecord compares two rows by their JSON encoding, so that a number read
+// from a file equals the integer read from the database
+func sameRecord(a, b db.Row) bool {
+	return canonicalJSON(a) == canonicalJSON(b)
+}
+
+func canonicalJSON(row db.Row) string {
+	keys := make([]string, 0, len(row))
+	for k := range row {
+		if row[k] != nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		v := row[k]
+		if n, ok := v.(json.Number); ok {
+			if f, err := n.Float64(); err == nil {
+				v = f
+			}
+		}
+		if i, ok := v.(int64); ok {
+			v = float64(i)
+		}
+		data, _ := json.Marshal(v)
+		sb.WriteString(strconv.Quote(k) + ":" + string(data) + ",")
+	}
+	return sb.String()
+}
@@ -0,0 +1,159 @@
+package fpf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readExport(t *testing.T, dir, table string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, table+".jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestExport_Deterministic(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+
+	_, _ = tools.ProposeHypothesis("Redis", "Use Redis", "api", "system", "{}", "", nil, 3)
+	_, _ = tools.ProposeHypothesis("Session Store", "Sessions in Redis", "api", "system", "{}", "", []string{"redis"}, 2)
+	if _, _, err := tools.VerifyHypothesis("redis", passingChecks, "PASS"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tools.Characterize("redis", "latency", "ratio", "2", "ms"); err != nil {
+		t.Fatal(err)
+	}
+
+	first, second := filepath.Join(tempDir, "first"), filepath.Join(tempDir, "second")
+	if _, err := tools.Export(first, ExportFormatJSONL); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	// Recomputing R must not show up in the knowledge tables
+	if err := tools.RunDecay(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tools.Export(second, ExportFormatJSONL); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	for _, table := range []string{"contexts", "holons", "evidence", "relations", "characteristics", "waivers", "loopbacks"} {
+		if a, b := readExport(t, first, table), readExport(t, second, table); a != b {
+			t.Errorf("%s changed between exports:\n%s\n---\n%s", table, a, b)
+		}
+	}
+
+	holons := strings.Split(strings.TrimSpace(readExport(t, first, "holons")), "\n")
+	if len(holons) != 2 || !strings.HasPrefix(holons[0], `{"claim_scope":null,"content":`) || !strings.Contains(holons[0], `"id":"redis"`) {
+		t.Errorf("Expected holons sorted by id with fields in name order, got:\n%s", strings.Join(holons, "\n"))
+	}
+	if strings.Contains(readExport(t, first, "holons"), "cached_r_score") {
+		t.Error("Expected cached_r_score to be left out of the export")
+	}
+	if !strings.Contains(readExport(t, first, "characteristics"), `"name":"latency"`) {
+		t.Error("Expected the characteristic to be exported")
+	}
+
+	if _, err := tools.Export(first, "csv"); err == nil {
+		t.Error("Expected an unsupported format to fail")
+	}
+}
+
+func TestImport_MergesBranches(t *testing.T) {
+	main, _, mainDir := setupTools(t)
+	branch, _, branchDir := setupTools(t)
+
+	// The branch starts from main's redis and adds a dependent hypothesis with evidence
+	_, _ = main.ProposeHypothesis("Redis", "Use Redis", "api", "system", "{}", "", nil, 3)
+	shared := filepath.Join(mainDir, "shared")
+	if _, err := main.Export(shared, ExportFormatJSONL); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := branch.Import(shared); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = branch.ProposeHypothesis("Session Store", "Sessions in Redis", "api", "system", "{}", "", []string{"redis"}, 2)
+	if _, _, err := branch.VerifyHypothesis("session-store", passingChecks, "PASS"); err != nil {
+		t.Fatal(err)
+	}
+
+	exported := filepath.Join(branchDir, "export")
+	if _, err := branch.Export(exported, ExportFormatJSONL); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := main.Import(exported)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if len(report.Conflicts) != 0 {
+		t.Errorf("Expected no conflicts, got:\n%s", report)
+	}
+	if report.Inserted["holons"] != 1 || report.Inserted["evidence"] != 1 || report.Inserted["relations"] != 2 {
+		t.Errorf("Unexpected import counts:\n%s", report)
+	}
+
+	quintDir := filepath.Join(mainDir, ".quint")
+	if _, err := os.Stat(filepath.Join(quintDir, "knowledge", "L1", "session-store.md")); err != nil {
+		t.Errorf("Expected the imported hypothesis to be projected: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(quintDir, "evidence", evidenceFileName("verification-logic", "session-store"))); err != nil {
+		t.Errorf("Expected the imported evidence to be projected: %v", err)
+	}
+	if doctor, err := main.Doctor(false); err != nil || len(doctor.Issues) != 0 {
+		t.Errorf("Expected doctor to find no issues after import, got:\n%v (%v)", doctor, err)
+	}
+
+	// Importing again changes nothing
+	again, err := main.Import(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Inserted) != 0 || len(again.Conflicts) != 0 {
+		t.Errorf("Expected a repeated import to be a no-op, got:\n%s", again)
+	}
+}
+
+func TestImport_FlagsConflictingIDs(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	_, _ = tools.ProposeHypothesis("Redis", "Use Redis", "api", "system", "{}", "", nil, 3)
+
+	dir := filepath.Join(tempDir, "export")
+	if _, err := tools.Export(dir, ExportFormatJSONL); err != nil {
+		t.Fatal(err)
+	}
+	holons := strings.TrimSpace(readExport(t, dir, "holons"))
+
+	// The record was changed on this side after the export
+	edited := strings.Replace(holons, `"scope":"api"`, `"scope":"api gateway"`, 1)
+	if err := os.WriteFile(filepath.Join(dir, "holons.jsonl"), []byte(edited+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report, err := tools.Import(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Conflicts) != 1 || !strings.Contains(report.Conflicts[0].Reason, "differs from the record in the database") {
+		t.Errorf("Expected a conflict with the database, got:\n%s", report)
+	}
+
+	// Two branches changed the same record: git leaves both sides in the file
+	queue := strings.NewReplacer(`"id":"redis"`, `"id":"queue"`, "Use Redis", "Use a queue").Replace(holons)
+	merged := strings.Join([]string{"<<<<<<< HEAD", queue, "=======", strings.Replace(queue, "Use a queue", "Use a durable queue", 1), ">>>>>>> branch"}, "\n")
+	if err := os.WriteFile(filepath.Join(dir, "holons.jsonl"), []byte(merged+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report, err = tools.Import(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Conflicts) != 1 || report.Conflicts[0].Key != "queue" || !strings.Contains(report.Conflicts[0].Reason, "2 different records") {
+		t.Errorf("Expected the merged record to be flagged, got:\n%s", report)
+	}
+	if _, err := tools.DB.GetHolon(t.Context(), "queue"); err == nil {
+		t.Error("Expected the conflicting record to stay out of the database")
+	}
+}
@@ -68,8 +68,11 @@ type TransitionRule struct {
 
 // FSM manages the state transitions
 type FSM struct {
-	State State
-	DB    *sql.DB
+	State           State
+	DB              *sql.DB
+	ContextID       string
+	AssuranceConfig assurance.Config
+	RootDir         string // Project root, for re-checking evidence artifacts
 }
 
 // LoadState reads state from fpf_state table in SQLite
@@ -79,7 +82,8 @@ func LoadState(contextID string, db *sql.DB) (*FSM, error) {
 			Phase:              PhaseIdle,
 			AssuranceThreshold: 0.8,
 		},
-		DB: db,
+		DB:        db,
+		ContextID: contextID,
 	}
 
 	if db == nil {
@@ -118,10 +122,18 @@ func LoadState(contextID string, db *sql.DB) (*FSM, error) {
 	return fsm, nil
 }
 
+// CurrentContext returns the bounded context this FSM is scoped to
+func (f *FSM) CurrentContext() string {
+	if f.ContextID == "" {
+		return DefaultContextID
+	}
+	return f.ContextID
+}
+
 // GetPhase returns the current phase, deriving from DB if available
 func (f *FSM) GetPhase() Phase {
 	if f.DB != nil {
-		return f.DerivePhase("default")
+		return f.DerivePhase(f.CurrentContext())
 	}
 	return f.State.Phase
 }
@@ -276,7 +288,11 @@ func (f *FSM) CanTransition(target Phase, assignment RoleAssignment, evidence *E
 			return false, "Transition to Operation requires a specific Holon ID in evidence stub"
 		}
 
-		calc := assurance.New(f.DB)
+		calc, err := assurance.NewFromConfig(f.DB, f.AssuranceConfig)
+		if err != nil {
+			return false, fmt.Sprintf("Invalid assurance config: %v", err)
+		}
+		calc.RootDir = f.RootDir
 		report, err := calc.CalculateReliability(context.Background(), evidence.HolonID)
 		if err != nil {
 			return false, fmt.Sprintf("Failed to calculate assurance: %v", err)
@@ -0,0 +1,321 @@
+package fpf
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MCPEndpoint is the path of the Streamable HTTP endpoint
+const MCPEndpoint = "/mcp"
+
+const (
+	sessionHeader         = "Mcp-Session-Id"
+	protocolVersionHeader = "Mcp-Protocol-Version"
+)
+
+const (
+	// maxRequestBytes bounds a single JSON-RPC message
+	maxRequestBytes = 4 << 20
+	// maxSessions bounds how many sessions may be open at once
+	maxSessions = 64
+	// sessionIdleTimeout expires sessions whose client went away without DELETE
+	sessionIdleTimeout = 30 * time.Minute
+)
+
+// HTTPHandler implements the MCP Streamable HTTP transport. Every client gets a
+// session on initialize; all sessions share one Tools and Store, and tool calls
+// from any session are serialized. Idle sessions expire and their number is capped.
+type HTTPHandler struct {
+	server *Server
+	hosts  map[string]bool
+
+	mu       sync.Mutex
+	sessions map[string]*httpSession
+}
+
+type httpSession struct {
+	server   *Server
+	lastUsed time.Time
+}
+
+// NewHTTPHandler serves s to clients that address it by a loopback name or by one
+// of the extra hosts (typically the address the server is bound to).
+func NewHTTPHandler(s *Server, hosts ...string) *HTTPHandler {
+	allowed := map[string]bool{"localhost": true, "127.0.0.1": true, "::1": true}
+	for _, host := range hosts {
+		// An unspecified bind address (0.0.0.0, ::) names no host clients can use
+		if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+			continue
+		}
+		allowed[strings.ToLower(host)] = true
+	}
+	return &HTTPHandler{server: s, hosts: allowed, sessions: make(map[string]*httpSession)}
+}
+
+// HTTPListenAddr fills in the loopback interface when addr names only a port,
+// so the unauthenticated endpoint is not exposed to the network by default.
+func HTTPListenAddr(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host != "" {
+		return addr
+	}
+	return net.JoinHostPort("127.0.0.1", port)
+}
+
+// ListenHTTP serves MCP over Streamable HTTP at addr until the listener fails.
+// A bare ":port" binds to 127.0.0.1; binding elsewhere must be explicit.
+func (s *Server) ListenHTTP(addr string) error {
+	addr = HTTPListenAddr(addr)
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(MCPEndpoint, NewHTTPHandler(s, host))
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		// Generous: a tool call can stream progress for a long time
+		WriteTimeout: 30 * time.Minute,
+		IdleTimeout:  2 * time.Minute,
+	}
+	return srv.ListenAndServe()
+}
+
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.allowedHost(r) {
+		http.Error(w, "Forbidden: host not allowed", http.StatusForbidden)
+		return
+	}
+	if !h.allowedOrigin(r) {
+		http.Error(w, "Forbidden: origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		h.handlePost(w, r)
+	case http.MethodDelete:
+		h.handleDelete(w, r)
+	default:
+		// No server-initiated stream: every response travels on its POST
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *HTTPHandler) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Bad request: failed to read body", http.StatusBadRequest)
+		return
+	}
+	body = bytes.TrimSpace(body)
+	if !json.Valid(body) {
+		writeJSON(w, http.StatusBadRequest, encode(errorResponse(nil, CodeParseError, "Parse error")))
+		return
+	}
+
+	var session *Server
+	sessionID := r.Header.Get(sessionHeader)
+	if isInitialize(body) {
+		if sessionID != "" {
+			http.Error(w, "Bad request: initialize must not carry a session ID", http.StatusBadRequest)
+			return
+		}
+		sessionID = newSessionID()
+		session = h.server.NewSession()
+		if !h.openSession(sessionID, session) {
+			http.Error(w, "Too many open sessions", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set(sessionHeader, sessionID)
+	} else {
+		if sessionID == "" {
+			http.Error(w, "Bad request: missing "+sessionHeader+" header", http.StatusBadRequest)
+			return
+		}
+		session = h.session(sessionID)
+		if session == nil {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		if v := r.Header.Get(protocolVersionHeader); v != "" && NegotiateProtocolVersion(v) != v {
+			http.Error(w, "Bad request: unsupported protocol version "+v, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Answer with plain JSON unless the request emits notifications (progress)
+	// before completing; then switch to an SSE stream so they reach the client.
+	stream := &sseWriter{w: w}
+	resp := session.HandleMessage(r.Context(), body, stream.notify)
+
+	if stream.started {
+		if resp != nil {
+			stream.event(resp)
+		}
+		return
+	}
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *HTTPHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.Header.Get(sessionHeader)
+	if sessionID == "" {
+		http.Error(w, "Bad request: missing "+sessionHeader+" header", http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	_, ok := h.sessions[sessionID]
+	delete(h.sessions, sessionID)
+	h.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SessionCount reports how many sessions are open
+func (h *HTTPHandler) SessionCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.sessions)
+}
+
+// openSession registers a session after expiring idle ones; false when the cap is reached
+func (h *HTTPHandler) openSession(id string, s *Server) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	for sid, sess := range h.sessions {
+		if now.Sub(sess.lastUsed) > sessionIdleTimeout {
+			delete(h.sessions, sid)
+		}
+	}
+	if len(h.sessions) >= maxSessions {
+		return false
+	}
+	h.sessions[id] = &httpSession{server: s, lastUsed: now}
+	return true
+}
+
+// session returns an open session and marks it used, or nil if it is unknown or expired
+func (h *HTTPHandler) session(id string) *Server {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sess := h.sessions[id]
+	if sess == nil {
+		return nil
+	}
+	now := time.Now()
+	if now.Sub(sess.lastUsed) > sessionIdleTimeout {
+		delete(h.sessions, id)
+		return nil
+	}
+	sess.lastUsed = now
+	return sess.server
+}
+
+// sseWriter turns the response into a text/event-stream on the first notification
+type sseWriter struct {
+	w       http.ResponseWriter
+	mu      sync.Mutex
+	started bool
+}
+
+func (s *sseWriter) notify(msg interface{}) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	s.event(data)
+}
+
+func (s *sseWriter) event(data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.started {
+		s.w.Header().Set("Content-Type", "text/event-stream")
+		s.w.Header().Set("Cache-Control", "no-cache")
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+	}
+	fmt.Fprintf(s.w, "event: message\ndata: %s\n\n", data)
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, data []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+func isInitialize(body []byte) bool {
+	var req JSONRPCRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return false
+	}
+	return req.Method == "initialize"
+}
+
+// allowedHost guards against DNS rebinding: a rebound name still carries the
+// attacker's host in the Host header, so only loopback names and the bind host pass.
+func (h *HTTPHandler) allowedHost(r *http.Request) bool {
+	return h.hosts[strings.ToLower(hostOnly(r.Host))]
+}
+
+// allowedOrigin admits browsers only from an allowed host. Requests without
+// Origin are not from a browser.
+func (h *HTTPHandler) allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return h.hosts[strings.ToLower(u.Hostname())]
+}
+
+func hostOnly(hostport string) string {
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		return h
+	}
+	return strings.Trim(hostport, "[]")
+}
+
+func newSessionID() string {
+	return rand.Text()
+}
@@ -0,0 +1,265 @@
+package fpf
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func postMCP(t *testing.T, url, sessionID, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID != "" {
+		req.Header.Set(sessionHeader, sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	return resp
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func initializeSession(t *testing.T, url string) string {
+	t.Helper()
+	resp := postMCP(t, url, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+	body := readBody(t, resp)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("initialize: expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	sessionID := resp.Header.Get(sessionHeader)
+	if sessionID == "" {
+		t.Fatal("initialize did not return a session ID")
+	}
+	return sessionID
+}
+
+func TestHTTPHandler_Sessions(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	handler := NewHTTPHandler(NewServer(tools))
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	sessionID := initializeSession(t, ts.URL)
+
+	resp := postMCP(t, ts.URL, sessionID, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	readBody(t, resp)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("notification: expected 202, got %d", resp.StatusCode)
+	}
+
+	resp = postMCP(t, ts.URL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"quint_status","arguments":{}}}`)
+	body := readBody(t, resp)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("tools/call: expected 200 JSON, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(body, "context: default") {
+		t.Errorf("unexpected tools/call response: %s", body)
+	}
+
+	resp = postMCP(t, ts.URL, "", `{"jsonrpc":"2.0","id":3,"method":"ping"}`)
+	readBody(t, resp)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("missing session: expected 400, got %d", resp.StatusCode)
+	}
+
+	resp = postMCP(t, ts.URL, "unknown", `{"jsonrpc":"2.0","id":4,"method":"ping"}`)
+	readBody(t, resp)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown session: expected 404, got %d", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, ts.URL, nil)
+	req.Header.Set(sessionHeader, sessionID)
+	delResp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readBody(t, delResp)
+	if delResp.StatusCode != http.StatusNoContent {
+		t.Errorf("DELETE: expected 204, got %d", delResp.StatusCode)
+	}
+	if handler.SessionCount() != 0 {
+		t.Errorf("expected session to be terminated, %d open", handler.SessionCount())
+	}
+
+	resp = postMCP(t, ts.URL, sessionID, `{"jsonrpc":"2.0","id":5,"method":"ping"}`)
+	readBody(t, resp)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("terminated session: expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestHTTPHandler_ProgressUsesSSE(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ts := httptest.NewServer(NewHTTPHandler(NewServer(tools)))
+	defer ts.Close()
+
+	sessionID := initializeSession(t, ts.URL)
+	resp := postMCP(t, ts.URL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"quint_status","arguments":{},"_meta":{"progressToken":1}}}`)
+	body := readBody(t, resp)
+
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected SSE stream, got %s", resp.Header.Get("Content-Type"))
+	}
+	if strings.Count(body, "notifications/progress") != 2 || !strings.Contains(body, `"id":2`) {
+		t.Errorf("expected two progress events followed by the response, got:\n%s", body)
+	}
+}
+
+func TestHTTPHandler_RejectsForeignOrigin(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ts := httptest.NewServer(NewHTTPHandler(NewServer(tools)))
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+	req.Header.Set("Origin", "http://evil.example")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readBody(t, resp)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403 for foreign origin, got %d", resp.StatusCode)
+	}
+}
+
+func TestHTTPHandler_RejectsForeignHost(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ts := httptest.NewServer(NewHTTPHandler(NewServer(tools), "quint.internal"))
+	defer ts.Close()
+
+	for host, want := range map[string]int{
+		"attacker.example:80": http.StatusForbidden,
+		"quint.internal:8080": http.StatusOK,
+		"localhost":           http.StatusOK,
+	} {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+		req.Host = host
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		readBody(t, resp)
+		if resp.StatusCode != want {
+			t.Errorf("Host %s: expected %d, got %d", host, want, resp.StatusCode)
+		}
+	}
+}
+
+func TestHTTPListenAddr(t *testing.T) {
+	for addr, want := range map[string]string{
+		":8080":          "127.0.0.1:8080",
+		"0.0.0.0:8080":   "0.0.0.0:8080",
+		"localhost:9000": "localhost:9000",
+	} {
+		if got := HTTPListenAddr(addr); got != want {
+			t.Errorf("HTTPListenAddr(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
+
+func TestHTTPHandler_Limits(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	handler := NewHTTPHandler(NewServer(tools))
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	big := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"pad":"` + strings.Repeat("x", maxRequestBytes) + `"}}`
+	resp := postMCP(t, ts.URL, "", big)
+	readBody(t, resp)
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized body: expected 413, got %d", resp.StatusCode)
+	}
+
+	sessionID := initializeSession(t, ts.URL)
+	handler.mu.Lock()
+	handler.sessions[sessionID].lastUsed = time.Now().Add(-sessionIdleTimeout - time.Minute)
+	handler.mu.Unlock()
+	resp = postMCP(t, ts.URL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	readBody(t, resp)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("idle session: expected 404, got %d", resp.StatusCode)
+	}
+
+	for i := 0; i < maxSessions; i++ {
+		initializeSession(t, ts.URL)
+	}
+	resp = postMCP(t, ts.URL, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+	readBody(t, resp)
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("session cap: expected 503, got %d", resp.StatusCode)
+	}
+}
+
+func TestHTTPHandler_ContextSwitchRefused(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ts := httptest.NewServer(NewHTTPHandler(NewServer(tools)))
+	defer ts.Close()
+
+	if _, err := tools.CreateContext("payments", ""); err != nil {
+		t.Fatal(err)
+	}
+	sessionID := initializeSession(t, ts.URL)
+	resp := postMCP(t, ts.URL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"quint_context","arguments":{"action":"switch","name":"payments"}}}`)
+	body := readBody(t, resp)
+	if !strings.Contains(body, `"isError":true`) || tools.ContextID() != DefaultContextID {
+		t.Errorf("expected switch to be refused, context %s: %s", tools.ContextID(), body)
+	}
+}
+
+func TestHTTPHandler_ConcurrentToolCalls(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ts := httptest.NewServer(NewHTTPHandler(NewServer(tools)))
+	defer ts.Close()
+
+	sessions := []string{initializeSession(t, ts.URL), initializeSession(t, ts.URL)}
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"quint_propose","arguments":{"title":"Hypothesis %d","content":"c","scope":"s","kind":"system","rationale":"{}"}}}`, i+10, i)
+			resp := postMCP(t, ts.URL, sessions[i%2], body)
+			text := readBody(t, resp)
+			if resp.StatusCode != http.StatusOK || strings.Contains(text, `"isError":true`) {
+				errs <- text
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for e := range errs {
+		t.Errorf("concurrent tool call failed: %s", e)
+	}
+
+	holons, err := tools.DB.ListHolonsByContext(t.Context(), DefaultContextID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holons) != 10 {
+		t.Errorf("expected 10 holons after concurrent proposals, got %d", len(holons))
+	}
+}
@@ -0,0 +1,325 @@
+package fpf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/assurance"
+)
+
+// Test report formats accepted by IngestTestResults
+const (
+	FormatGoTest = assurance.ArtifactGoTest // go test -json output
+	FormatJUnit  = assurance.ArtifactJUnit  // JUnit XML report
+)
+
+// IngestDegradeRatio is the pass ratio at or above which a suite with failing
+// tests is recorded as degrade rather than FAIL
+const IngestDegradeRatio = 0.8
+
+// maxFailureOutput caps the lines of output kept per failed test
+const maxFailureOutput = 20
+
+// TestResult is one test case parsed from a report
+type TestResult struct {
+	Name    string
+	Package string
+	Status  string // pass, fail or skip
+	Elapsed float64
+	Output  string
+}
+
+// ParseTestResults reads a test report in the given format
+func ParseTestResults(format string, data []byte) ([]TestResult, error) {
+	switch strings.ToLower(format) {
+	case FormatGoTest:
+		return parseGoTestJSON(data)
+	case FormatJUnit:
+		return parseJUnit(data)
+	default:
+		return nil, fmt.Errorf("format must be %s or %s, got %q", FormatGoTest, FormatJUnit, format)
+	}
+}
+
+// goTestEvent is one line of go test -json output
+type goTestEvent struct {
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// parseGoTestJSON collects the top-level tests of go test -json output.
+// Subtests are folded into their parent, which fails when any of them does.
+func parseGoTestJSON(data []byte) ([]TestResult, error) {
+	var results []TestResult
+	index := make(map[string]int)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var ev goTestEvent
+		if err := json.Unmarshal(line, &ev); err != nil {
+			return nil, fmt.Errorf("line %d is not a go test -json event: %v", n, err)
+		}
+		if ev.Test == "" {
+			continue
+		}
+
+		name := strings.SplitN(ev.Test, "/", 2)[0]
+		key := ev.Package + "\x00" + name
+		i, ok := index[key]
+		if !ok {
+			i = len(results)
+			index[key] = i
+			results = append(results, TestResult{Name: name, Package: ev.Package})
+		}
+
+		r := &results[i]
+		switch ev.Action {
+		case "output":
+			r.Output += ev.Output
+		case "pass", "fail", "skip":
+			if name == ev.Test {
+				r.Status = ev.Action
+				r.Elapsed = ev.Elapsed
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Tests still running when the binary died (panic, timeout) count as failed
+	for i := range results {
+		if results[i].Status == "" {
+			results[i].Status = "fail"
+		}
+	}
+	return results, nil
+}
+
+type junitSuite struct {
+	Name   string       `xml:"name,attr"`
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []junitCase  `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Error     *junitMessage `xml:"error"`
+	Skipped   *junitMessage `xml:"skipped"`
+	SystemOut string        `xml:"system-out"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// parseJUnit collects the test cases of a JUnit report rooted at either
+// <testsuites> or a single <testsuite>
+func parseJUnit(data []byte) ([]TestResult, error) {
+	var root junitSuite
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid JUnit XML: %v", err)
+	}
+
+	var results []TestResult
+	var walk func(s junitSuite)
+	walk = func(s junitSuite) {
+		for _, c := range s.Cases {
+			pkg := c.Classname
+			if pkg == "" {
+				pkg = s.Name
+			}
+			r := TestResult{Name: c.Name, Package: pkg, Status: "pass", Output: c.SystemOut}
+			r.Elapsed, _ = strconv.ParseFloat(c.Time, 64)
+			switch {
+			case c.Failure != nil:
+				r.Status = "fail"
+				r.Output = joinNonEmpty(c.Failure.Message, c.Failure.Body, c.SystemOut)
+			case c.Error != nil:
+				r.Status = "fail"
+				r.Output = joinNonEmpty(c.Error.Message, c.Error.Body, c.SystemOut)
+			case c.Skipped != nil:
+				r.Status = "skip"
+			}
+			results = append(results, r)
+		}
+		for _, nested := range s.Suites {
+			walk(nested)
+		}
+	}
+	walk(root)
+	return results, nil
+}
+
+func joinNonEmpty(parts ...string) string {
+	var kept []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// TestSummary counts the results of a report and derives a verdict from the
+// pass ratio: PASS when every test passed, degrade at IngestDegradeRatio or
+// above, FAIL below. Skipped tests do not count.
+type TestSummary struct {
+	Passed  int
+	Failed  int
+	Skipped int
+	Verdict string
+}
+
+// SummarizeTestResults counts results and derives the suite verdict
+func SummarizeTestResults(results []TestResult) TestSummary {
+	var s TestSummary
+	for _, r := range results {
+		switch r.Status {
+		case "pass":
+			s.Passed++
+		case "fail":
+			s.Failed++
+		default:
+			s.Skipped++
+		}
+	}
+
+	ratio := 0.0
+	if run := s.Passed + s.Failed; run > 0 {
+		ratio = float64(s.Passed) / float64(run)
+	}
+	switch {
+	case s.Failed == 0:
+		s.Verdict = "PASS"
+	case ratio >= IngestDegradeRatio:
+		s.Verdict = "DEGRADE"
+	default:
+		s.Verdict = "FAIL"
+	}
+	return s
+}
+
+// IngestTestResults records a test report as validation evidence for a
+// hypothesis. The suite verdict goes through ManageEvidence, so PASS promotes
+// L1 to L2, FAIL invalidates and degrade records without moving; every test
+// case that ran is recorded as its own evidence item, and the report is
+// attached to the suite evidence as its artifact.
+func (t *Tools) IngestTestResults(holonID, format, path, evidenceType string) (string, error) {
+	defer t.RecordWork("IngestTestResults", time.Now())
+	if err := t.checkHolonContext(holonID); err != nil {
+		return "", err
+	}
+	format = strings.ToLower(strings.TrimSpace(format))
+	if evidenceType == "" {
+		evidenceType = "internal"
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(t.RootDir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read test report: %v", err)
+	}
+	results, err := ParseTestResults(format, data)
+	if err != nil {
+		return "", err
+	}
+	summary := SummarizeTestResults(results)
+	if summary.Passed+summary.Failed == 0 {
+		return "", fmt.Errorf("no test results in %s", filepath.Base(path))
+	}
+
+	level := "L2"
+	if summary.Verdict != "PASS" {
+		level = "L1"
+	}
+	suiteContent := fmt.Sprintf("Test report: %s (%s)\nPassed: %d\nFailed: %d\nSkipped: %d",
+		filepath.Base(path), format, summary.Passed, summary.Failed, summary.Skipped)
+
+	var suitePath string
+	err = t.transact(func() error {
+		var suiteID string
+		var err error
+		if suitePath, suiteID, err = t.ManageEvidence(PhaseInduction, "add", holonID, evidenceType, suiteContent, summary.Verdict, level, "test-runner", ""); err != nil {
+			return err
+		}
+
+		validUntil := defaultValidUntil()
+		seen := make(map[string]bool)
+		for i, r := range results {
+			if r.Status != "pass" && r.Status != "fail" {
+				continue
+			}
+			slug := t.Slugify(r.Name)
+			if slug == "" {
+				slug = fmt.Sprintf("case-%d", i+1)
+			}
+			if seen[slug] {
+				slug = t.Slugify(r.Package + " " + r.Name)
+			}
+			// Reruns and names that differ only in case or punctuation slug alike;
+			// a shared ID would let a later PASS overwrite an earlier FAIL
+			for base, n := slug, 2; seen[slug]; n++ {
+				slug = fmt.Sprintf("%s-%d", base, n)
+			}
+			seen[slug] = true
+
+			caseLevel := "L2"
+			if r.Status == "fail" {
+				caseLevel = "L1"
+			}
+			if _, err := t.recordEvidence(evidenceFileName(evidenceType+"-"+slug, holonID), holonID, evidenceType,
+				testCaseContent(r), r.Status, caseLevel, "test-runner", validUntil); err != nil {
+				return err
+			}
+		}
+
+		_, err = t.AttachArtifact(suiteID, format, path)
+		return err
+	})
+	if err != nil {
+		t.AuditLog("quint_ingest_tests", "ingest", "agent", holonID, "ERROR", map[string]string{"format": format, "file": path}, err.Error())
+		return "", err
+	}
+
+	t.AuditLog("quint_ingest_tests", "ingest", "agent", holonID, "SUCCESS", map[string]string{
+		"format":  format,
+		"verdict": summary.Verdict,
+		"passed":  fmt.Sprint(summary.Passed),
+		"failed":  fmt.Sprint(summary.Failed),
+		"skipped": fmt.Sprint(summary.Skipped),
+	}, "")
+
+	return fmt.Sprintf("Ingested %d tests for %s: %d passed, %d failed, %d skipped → %s\n%s",
+		len(results), holonID, summary.Passed, summary.Failed, summary.Skipped, summary.Verdict, suitePath), nil
+}
+
+func testCaseContent(r TestResult) string {
+	content := fmt.Sprintf("Test: %s\nPackage: %s\nResult: %s\nElapsed: %.3fs", r.Name, r.Package, strings.ToUpper(r.Status), r.Elapsed)
+	if tail := outputTail(r.Output, maxFailureOutput); r.Status == "fail" && tail != "" {
+		content += "\n\n```\n" + tail + "\n```"
+	}
+	return content
+}
@@ -0,0 +1,211 @@
+package fpf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const goTestReport = `{"Action":"run","Package":"cache","Test":"TestHit"}
+{"Action":"output","Package":"cache","Test":"TestHit","Output":"=== RUN   TestHit\n"}
+{"Action":"pass","Package":"cache","Test":"TestHit","Elapsed":0.01}
+{"Action":"run","Package":"cache","Test":"TestEvict"}
+{"Action":"run","Package":"cache","Test":"TestEvict/lru"}
+{"Action":"output","Package":"cache","Test":"TestEvict/lru","Output":"    cache_test.go:42: expected 3 entries, got 4\n"}
+{"Action":"fail","Package":"cache","Test":"TestEvict/lru","Elapsed":0}
+{"Action":"fail","Package":"cache","Test":"TestEvict","Elapsed":0.02}
+{"Action":"run","Package":"cache","Test":"TestSlow"}
+{"Action":"skip","Package":"cache","Test":"TestSlow","Elapsed":0}
+{"Action":"fail","Package":"cache","Elapsed":0.05}
+`
+
+const junitReport = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="cache">
+    <testcase classname="cache.Hit" name="hit" time="0.01"/>
+    <testcase classname="cache.Evict" name="evict" time="0.02">
+      <failure message="expected 3 entries">got 4</failure>
+    </testcase>
+    <testcase classname="cache.Slow" name="slow"><skipped/></testcase>
+  </testsuite>
+</testsuites>`
+
+func TestParseTestResults(t *testing.T) {
+	for _, tt := range []struct{ format, report string }{{FormatGoTest, goTestReport}, {FormatJUnit, junitReport}} {
+		results, err := ParseTestResults(tt.format, []byte(tt.report))
+		if err != nil {
+			t.Fatalf("ParseTestResults(%s) failed: %v", tt.format, err)
+		}
+		if len(results) != 3 {
+			t.Fatalf("%s: expected 3 tests (subtests folded), got %+v", tt.format, results)
+		}
+		var statuses []string
+		for _, r := range results {
+			statuses = append(statuses, r.Status)
+		}
+		if strings.Join(statuses, ",") != "pass,fail,skip" {
+			t.Errorf("%s: unexpected statuses %v", tt.format, statuses)
+		}
+		if !strings.Contains(results[1].Output, "got 4") {
+			t.Errorf("%s: expected the failure output to be kept, got %q", tt.format, results[1].Output)
+		}
+	}
+
+	if _, err := ParseTestResults(FormatGoTest, []byte("ok  \tcache\t0.05s\n")); err == nil {
+		t.Error("Expected plain go test output to be rejected")
+	}
+	if _, err := ParseTestResults("tap", nil); err == nil {
+		t.Error("Expected an unknown format to be rejected")
+	}
+}
+
+func TestSummarizeTestResults(t *testing.T) {
+	suite := func(passed, failed int) []TestResult {
+		var results []TestResult
+		for i := 0; i < passed; i++ {
+			results = append(results, TestResult{Status: "pass"})
+		}
+		for i := 0; i < failed; i++ {
+			results = append(results, TestResult{Status: "fail"})
+		}
+		return append(results, TestResult{Status: "skip"})
+	}
+
+	tests := []struct {
+		passed, failed int
+		verdict        string
+	}{
+		{5, 0, "PASS"},
+		{8, 2, "DEGRADE"},
+		{7, 3, "FAIL"},
+		{0, 1, "FAIL"},
+	}
+	for _, tt := range tests {
+		s := SummarizeTestResults(suite(tt.passed, tt.failed))
+		if s.Verdict != tt.verdict || s.Skipped != 1 {
+			t.Errorf("%d passed, %d failed: expected %s, got %+v", tt.passed, tt.failed, tt.verdict, s)
+		}
+	}
+}
+
+func setupL1Hypothesis(t *testing.T, tools *Tools) {
+	t.Helper()
+	if _, err := tools.ProposeHypothesis("Cache", "Add a cache", "api", "system", "{}", "", nil, 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := tools.VerifyHypothesis("cache", []VerificationCheck{{Name: "logic", Category: CheckLogicalConsistency, Result: "pass"}}, "PASS"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIngestTestResults_Promotes(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+	setupL1Hypothesis(t, tools)
+
+	report := strings.Replace(goTestReport, `"Action":"fail","Package":"cache","Test":"TestEvict",`, `"Action":"pass","Package":"cache","Test":"TestEvict",`, 1)
+	if err := os.WriteFile(filepath.Join(tempDir, "test.json"), []byte(report), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := tools.IngestTestResults("cache", FormatGoTest, "test.json", "")
+	if err != nil {
+		t.Fatalf("IngestTestResults failed: %v", err)
+	}
+	if !strings.Contains(msg, "2 passed, 0 failed, 1 skipped → PASS") {
+		t.Errorf("Unexpected message: %s", msg)
+	}
+	if holon, _ := tools.DB.GetHolon(ctx, "cache"); holon.Layer != "L2" {
+		t.Errorf("Expected a passing suite to promote to L2, got %s", holon.Layer)
+	}
+
+	suite, err := tools.DB.GetEvidenceByID(ctx, evidenceFileName("internal", "cache"))
+	if err != nil {
+		t.Fatalf("Expected suite evidence: %v", err)
+	}
+	if suite.Verdict != "pass" || suite.ArtifactKind.String != FormatGoTest {
+		t.Errorf("Expected a passing suite with the report attached, got %+v", suite)
+	}
+	for _, name := range []string{"testhit", "testevict"} {
+		e, err := tools.DB.GetEvidenceByID(ctx, evidenceFileName("internal-"+name, "cache"))
+		if err != nil || e.Verdict != "pass" || e.CarrierRef.String != "test-runner" {
+			t.Errorf("Expected passing evidence for %s, got %+v (%v)", name, e, err)
+		}
+	}
+	if _, err := tools.DB.GetEvidenceByID(ctx, evidenceFileName("internal-testslow", "cache")); err == nil {
+		t.Error("Expected skipped tests not to be recorded")
+	}
+}
+
+func TestIngestTestResults_Degrade(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+	setupL1Hypothesis(t, tools)
+
+	var cases []string
+	for i := 1; i <= 5; i++ {
+		cases = append(cases, fmt.Sprintf(`<testcase classname="cache" name="case %d"/>`, i))
+	}
+	cases = append(cases, `<testcase classname="cache" name="case 6"><error message="panic: nil map"/></testcase>`)
+	report := `<testsuite name="cache">` + strings.Join(cases, "") + `</testsuite>`
+	if err := os.WriteFile(filepath.Join(tempDir, "junit.xml"), []byte(report), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := tools.IngestTestResults("cache", FormatJUnit, filepath.Join(tempDir, "junit.xml"), "")
+	if err != nil {
+		t.Fatalf("IngestTestResults failed: %v", err)
+	}
+	if !strings.Contains(msg, "→ DEGRADE") {
+		t.Errorf("Expected 5 of 6 passing to degrade, got: %s", msg)
+	}
+	if holon, _ := tools.DB.GetHolon(ctx, "cache"); holon.Layer != "L1" {
+		t.Errorf("Expected a degraded suite to stay in L1, got %s", holon.Layer)
+	}
+
+	failed, err := tools.DB.GetEvidenceByID(ctx, evidenceFileName("internal-case-6", "cache"))
+	if err != nil || failed.Verdict != "fail" || !strings.Contains(failed.Content, "panic: nil map") {
+		t.Errorf("Expected the erroring case as failing evidence, got %+v (%v)", failed, err)
+	}
+}
+
+func TestIngestTestResults_DuplicateNames(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+	setupL1Hypothesis(t, tools)
+
+	// A rerun of the same test, and a name that only differs in case and punctuation
+	report := `<testsuite name="cache">` +
+		`<testcase classname="cache" name="hit"><failure message="miss">cold cache</failure></testcase>` +
+		`<testcase classname="cache" name="hit"/>` +
+		`<testcase classname="cache" name="Hit!"/>` +
+		`</testsuite>`
+	if err := os.WriteFile(filepath.Join(tempDir, "junit.xml"), []byte(report), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tools.IngestTestResults("cache", FormatJUnit, "junit.xml", ""); err != nil {
+		t.Fatalf("IngestTestResults failed: %v", err)
+	}
+
+	for slug, verdict := range map[string]string{"hit": "fail", "cache-hit": "pass", "cache-hit-2": "pass"} {
+		e, err := tools.DB.GetEvidenceByID(ctx, evidenceFileName("internal-"+slug, "cache"))
+		if err != nil || e.Verdict != verdict {
+			t.Errorf("Expected %s evidence for %s, got %+v (%v)", verdict, slug, e, err)
+		}
+	}
+}
+
+func TestIngestTestResults_Preconditions(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	if _, err := tools.ProposeHypothesis("Cache", "Add a cache", "api", "system", "{}", "", nil, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	err := tools.CheckPreconditions("quint_ingest_tests", map[string]string{"hypothesis_id": "cache", "format": FormatGoTest, "path": "test.json"})
+	if err == nil || !strings.Contains(err.Error(), "still in L0") {
+		t.Errorf("Expected an L0 hypothesis to be rejected, got %v", err)
+	}
+}
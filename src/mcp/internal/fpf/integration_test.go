@@ -124,7 +124,7 @@ func TestFullFPFWorkflowIntegration(t *testing.T) {
 		evidenceContent := "Deductive logic check passes."
 		verdict := "PASS"
 
-		evidencePath, err := tools.ManageEvidence(fsm.State.Phase, "add", hypo1ID, "logic", evidenceContent, verdict, "L1", "logic-carrier", "2025-12-31")
+		evidencePath, _, err := tools.ManageEvidence(fsm.State.Phase, "add", hypo1ID, "logic", evidenceContent, verdict, "L1", "logic-carrier", "2025-12-31")
 		if err != nil {
 			t.Fatalf("ManageEvidence (Deduction PASS) failed: %v", err)
 		}
@@ -162,7 +162,7 @@ func TestFullFPFWorkflowIntegration(t *testing.T) {
 			t.Fatalf("Hypothesis %s not found in L1 before Induction PASS test", hypo1ID)
 		}
 
-		evidencePath, err := tools.ManageEvidence(fsm.State.Phase, "add", hypo1ID, "empirical", evidenceContent, verdict, "L2", "empirical-carrier", "2025-12-31")
+		evidencePath, _, err := tools.ManageEvidence(fsm.State.Phase, "add", hypo1ID, "empirical", evidenceContent, verdict, "L2", "empirical-carrier", "2025-12-31")
 		if err != nil {
 			t.Fatalf("ManageEvidence (Induction PASS) failed: %v", err)
 		}
@@ -241,7 +241,7 @@ func TestFullFPFWorkflowIntegration(t *testing.T) {
 		verdict := "PASS"
 
 		// hypo2ID is the new child hypothesis, created in L0
-		evidencePath, err := tools.ManageEvidence(fsm.State.Phase, "add", hypo2ID, "logic", evidenceContent, verdict, "L1", "logic-carrier-2", "2025-12-31")
+		evidencePath, _, err := tools.ManageEvidence(fsm.State.Phase, "add", hypo2ID, "logic", evidenceContent, verdict, "L1", "logic-carrier-2", "2025-12-31")
 		if err != nil {
 			t.Fatalf("ManageEvidence (Deduction PASS for refined) failed: %v", err)
 		}
@@ -280,7 +280,7 @@ func TestFullFPFWorkflowIntegration(t *testing.T) {
 		verdict := "PASS"
 
 		// hypo2ID is in L1
-		evidencePath, err := tools.ManageEvidence(fsm.State.Phase, "add", hypo2ID, "empirical", evidenceContent, verdict, "L2", "empirical-carrier-2", "2025-12-31")
+		evidencePath, _, err := tools.ManageEvidence(fsm.State.Phase, "add", hypo2ID, "empirical", evidenceContent, verdict, "L2", "empirical-carrier-2", "2025-12-31")
 		if err != nil {
 			t.Fatalf("ManageEvidence (Induction PASS refined) failed: %v", err)
 		}
@@ -0,0 +1,291 @@
+package fpf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// MergeDriverName is the merge driver registered in .gitattributes and git config
+const MergeDriverName = "quint"
+
+// mergeDriverPatterns are the projection files merged by the driver
+var mergeDriverPatterns = []string{
+	".quint/knowledge/**/*.md",
+	".quint/decisions/*.md",
+	".quint/evidence/*.md",
+	".quint/waivers/*.md",
+}
+
+// listFields hold comma-separated sets; additions from both sides are kept
+var listFields = map[string]bool{
+	"depends_on":       true,
+	"decision_context": true,
+	"rejected_ids":     true,
+	"anchors":          true,
+}
+
+// MergeResult is the outcome of a three-way merge of a projection file
+type MergeResult struct {
+	Content   string
+	Merged    []string // frontmatter fields taken from theirs or combined
+	Conflicts []string // frontmatter fields changed differently on both sides, and "body"
+}
+
+// MergeProjection merges two versions of a projection file against their
+// common ancestor. Frontmatter is merged field by field, the body line by line
+// with git merge-file, and content_hash is recomputed for the merged body.
+// When conflicts remain the result carries conflict markers and no hash.
+func MergeProjection(base, ours, theirs string) (*MergeResult, error) {
+	baseFM, baseBody, baseOK := parseFrontmatter(base)
+	oursFM, oursBody, oursOK := parseFrontmatter(ours)
+	theirsFM, theirsBody, theirsOK := parseFrontmatter(theirs)
+
+	// Without frontmatter on both sides there is nothing to merge semantically
+	if !oursOK || !theirsOK {
+		content, conflicts, err := mergeText(base, ours, theirs)
+		if err != nil {
+			return nil, err
+		}
+		result := &MergeResult{Content: content}
+		if conflicts {
+			result.Conflicts = []string{"body"}
+		}
+		return result, nil
+	}
+	if !baseOK {
+		baseBody = base
+	}
+
+	result := &MergeResult{}
+	fields, fieldConflicts := mergeFields(frontmatterFields(baseFM), frontmatterFields(oursFM), frontmatterFields(theirsFM), result)
+
+	body, bodyConflicts, err := mergeText(baseBody, oursBody, theirsBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fieldConflicts) == 0 && !bodyConflicts {
+		result.Content = renderWithHash(fields, body)
+		return result, nil
+	}
+
+	var fm strings.Builder
+	fm.WriteString("---\n")
+	for _, k := range sortedKeys(fields) {
+		fm.WriteString(fmt.Sprintf("%s: %s\n", k, fields[k]))
+	}
+	for _, c := range fieldConflicts {
+		fm.WriteString(c)
+	}
+	fm.WriteString("---\n")
+	result.Content = fm.String() + body
+	if bodyConflicts {
+		result.Conflicts = append(result.Conflicts, "body")
+	}
+	return result, nil
+}
+
+// mergeFields merges frontmatter field by field. A field changed on one side
+// only takes that change; list fields combine both sides' additions and
+// removals. Fields changed differently on both sides are returned as blocks
+// with conflict markers.
+func mergeFields(base, ours, theirs map[string]string, result *MergeResult) (map[string]string, []string) {
+	keys := make(map[string]bool)
+	for _, m := range []map[string]string{base, ours, theirs} {
+		for k := range m {
+			if k != "content_hash" {
+				keys[k] = true
+			}
+		}
+	}
+
+	merged := make(map[string]string)
+	var conflicts []string
+	for _, k := range sortedKeys(keys) {
+		b, inBase := base[k]
+		o, inOurs := ours[k]
+		th, inTheirs := theirs[k]
+
+		switch {
+		case inOurs == inTheirs && o == th:
+			if inOurs {
+				merged[k] = o
+			}
+		case inOurs == inBase && o == b:
+			if inTheirs {
+				merged[k] = th
+			}
+			result.Merged = append(result.Merged, k)
+		case inTheirs == inBase && th == b:
+			if inOurs {
+				merged[k] = o
+			}
+		case listFields[k]:
+			merged[k] = mergeList(b, o, th)
+			result.Merged = append(result.Merged, k)
+		default:
+			conflicts = append(conflicts, fmt.Sprintf("<<<<<<< ours\n%s=======\n%s>>>>>>> theirs\n",
+				fieldLine(k, o, inOurs), fieldLine(k, th, inTheirs)))
+			result.Conflicts = append(result.Conflicts, k)
+		}
+	}
+	return merged, conflicts
+}
+
+func fieldLine(k, v string, present bool) string {
+	if !present {
+		return ""
+	}
+	return fmt.Sprintf("%s: %s\n", k, v)
+}
+
+// mergeList keeps every item of ours and theirs except those one side removed from base
+func mergeList(base, ours, theirs string) string {
+	inBase := make(map[string]bool)
+	for _, item := range splitList(base) {
+		inBase[item] = true
+	}
+	inOurs := make(map[string]bool)
+	for _, item := range splitList(ours) {
+		inOurs[item] = true
+	}
+	inTheirs := make(map[string]bool)
+	for _, item := range splitList(theirs) {
+		inTheirs[item] = true
+	}
+
+	var items []string
+	for _, item := range sortedKeys(mergeSets(inOurs, inTheirs)) {
+		removed := inBase[item] && (!inOurs[item] || !inTheirs[item])
+		if !removed {
+			items = append(items, item)
+		}
+	}
+	return strings.Join(items, ", ")
+}
+
+func mergeSets(a, b map[string]bool) map[string]bool {
+	union := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		union[k] = true
+	}
+	for k := range b {
+		union[k] = true
+	}
+	return union
+}
+
+// mergeText runs a three-way line merge through git merge-file and reports
+// whether conflicts remain
+func mergeText(base, ours, theirs string) (string, bool, error) {
+	if ours == theirs {
+		return ours, false, nil
+	}
+	if base == ours {
+		return theirs, false, nil
+	}
+	if base == theirs {
+		return ours, false, nil
+	}
+
+	dir, err := os.MkdirTemp("", "quint-merge-")
+	if err != nil {
+		return "", false, err
+	}
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	paths := make([]string, 3)
+	for i, content := range []string{ours, base, theirs} {
+		paths[i] = filepath.Join(dir, []string{"ours", "base", "theirs"}[i])
+		if err := os.WriteFile(paths[i], []byte(content), 0644); err != nil {
+			return "", false, err
+		}
+	}
+
+	cmd := exec.Command("git", "merge-file", "-p", "-L", "ours", "-L", "base", "-L", "theirs", paths[0], paths[1], paths[2])
+	output, err := cmd.Output()
+	if err == nil {
+		return string(output), false, nil
+	}
+	// merge-file exits with the number of conflicts; anything above 127 is an error
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+		return string(output), true, nil
+	}
+	return "", false, fmt.Errorf("git merge-file failed: %v", err)
+}
+
+// MergeFile is the git merge driver: it merges base and theirs into the file
+// at oursPath, as git expects, and records the merge in the audit log. name is
+// the path of the file in the repository.
+func (t *Tools) MergeFile(basePath, oursPath, theirsPath, name string) (*MergeResult, error) {
+	var versions [3]string
+	for i, path := range []string{basePath, oursPath, theirsPath} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		versions[i] = string(data)
+	}
+
+	result, err := MergeProjection(versions[0], versions[1], versions[2])
+	if err != nil {
+		t.AuditLog("quint_merge_driver", "merge", "git", name, "ERROR", nil, err.Error())
+		return nil, err
+	}
+	if err := os.WriteFile(oursPath, []byte(result.Content), 0644); err != nil {
+		return nil, err
+	}
+
+	outcome := "SUCCESS"
+	if len(result.Conflicts) > 0 {
+		outcome = "CONFLICT"
+	}
+	t.AuditLog("quint_merge_driver", "merge", "git", name, outcome,
+		map[string]string{"base": ComputeContentHash(versions[0]), "ours": ComputeContentHash(versions[1]), "theirs": ComputeContentHash(versions[2])},
+		fmt.Sprintf("merged: %s; conflicts: %s", strings.Join(result.Merged, ", "), strings.Join(result.Conflicts, ", ")))
+	return result, nil
+}
+
+// InstallMergeDriver registers the merge driver in the repository's git config
+// and routes the projection files to it in .gitattributes
+func InstallMergeDriver(root string) error {
+	for _, args := range [][]string{
+		{"config", "merge." + MergeDriverName + ".name", "Quint knowledge file merge"},
+		{"config", "merge." + MergeDriverName + ".driver", "quint-code merge-driver %O %A %B %P"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git %s failed: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	path := filepath.Join(root, ".gitattributes")
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	lines := strings.Split(string(existing), "\n")
+	present := make(map[string]bool)
+	for _, line := range lines {
+		present[strings.TrimSpace(line)] = true
+	}
+
+	content := strings.TrimRight(string(existing), "\n")
+	for _, pattern := range mergeDriverPatterns {
+		line := pattern + " merge=" + MergeDriverName
+		if present[line] {
+			continue
+		}
+		if content != "" {
+			content += "\n"
+		}
+		content += line
+	}
+	return os.WriteFile(path, []byte(content+"\n"), 0644)
+}
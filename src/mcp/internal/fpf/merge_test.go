@@ -0,0 +1,125 @@
+package fpf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const mergeBaseBody = "\n# Hypothesis: Redis\n\nUse Redis\n\n## Rationale\nFast\n\n## Notes\nNone\n"
+
+func TestMergeProjection_Clean(t *testing.T) {
+	base := renderWithHash(map[string]string{"kind": "system", "scope": "api", "context": "default", "depends_on": "auth (CL3)", "anchors": "path:api/**"}, mergeBaseBody)
+	// Ours narrows the scope and adds a dependency; theirs adds another and edits the notes
+	ours := renderWithHash(map[string]string{"kind": "system", "scope": "api gateway", "context": "default", "depends_on": "auth (CL3), cache (CL2)", "anchors": "path:api/**, pkg:internal/cache"}, mergeBaseBody)
+	theirsBody := strings.Replace(mergeBaseBody, "None", "Benchmarked in staging", 1)
+	theirs := renderWithHash(map[string]string{"kind": "system", "scope": "api", "context": "default", "depends_on": "auth (CL3), queue (CL3)", "formality": "F2", "anchors": "path:api/**, symbol:internal/queue.Publish"}, theirsBody)
+
+	result, err := MergeProjection(base, ours, theirs)
+	if err != nil {
+		t.Fatalf("MergeProjection failed: %v", err)
+	}
+	if len(result.Conflicts) != 0 {
+		t.Fatalf("Expected a clean merge, got conflicts %v:\n%s", result.Conflicts, result.Content)
+	}
+
+	frontmatter, body, ok := parseFrontmatter(result.Content)
+	if !ok {
+		t.Fatalf("Expected frontmatter in:\n%s", result.Content)
+	}
+	fields := frontmatterFields(frontmatter)
+	want := map[string]string{"scope": "api gateway", "depends_on": "auth (CL3), cache (CL2), queue (CL3)", "formality": "F2",
+		"anchors": "path:api/**, pkg:internal/cache, symbol:internal/queue.Publish"}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Errorf("Expected %s: %s, got %q", k, v, fields[k])
+		}
+	}
+	if body != theirsBody {
+		t.Errorf("Expected theirs' body edit, got:\n%s", body)
+	}
+	if fields["content_hash"] != ComputeContentHash(body) {
+		t.Error("Expected content_hash to match the merged body")
+	}
+}
+
+func TestMergeProjection_ListRemovals(t *testing.T) {
+	base := renderWithHash(map[string]string{"rejected_ids": "a, b"}, "\n# DRR\n")
+	ours := renderWithHash(map[string]string{"rejected_ids": "b"}, "\n# DRR\n")
+	theirs := renderWithHash(map[string]string{"rejected_ids": "a, b, c"}, "\n# DRR\n")
+
+	result, err := MergeProjection(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frontmatter, _, _ := parseFrontmatter(result.Content)
+	if got := frontmatterFields(frontmatter)["rejected_ids"]; got != "b, c" {
+		t.Errorf("Expected ours' removal and theirs' addition to both apply, got %q", got)
+	}
+}
+
+func TestMergeProjection_Conflicts(t *testing.T) {
+	base := renderWithHash(map[string]string{"kind": "system", "scope": "api"}, mergeBaseBody)
+	ours := renderWithHash(map[string]string{"kind": "system", "scope": "api gateway"}, strings.Replace(mergeBaseBody, "Fast", "Fast and simple", 1))
+	theirs := renderWithHash(map[string]string{"kind": "system", "scope": "internal"}, strings.Replace(mergeBaseBody, "Fast", "Battle-tested", 1))
+
+	result, err := MergeProjection(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(result.Conflicts, ",") != "scope,body" {
+		t.Errorf("Expected scope and body conflicts, got %v", result.Conflicts)
+	}
+	for _, want := range []string{"<<<<<<< ours\nscope: api gateway\n=======\nscope: internal\n>>>>>>> theirs\n", "Fast and simple", "Battle-tested", "kind: system"} {
+		if !strings.Contains(result.Content, want) {
+			t.Errorf("Expected %q in:\n%s", want, result.Content)
+		}
+	}
+	if strings.Contains(result.Content, "content_hash") {
+		t.Error("Expected no content_hash while conflicts remain")
+	}
+}
+
+func TestMergeFile_WritesOursAndAudits(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+
+	base := renderWithHash(map[string]string{"scope": "api"}, mergeBaseBody)
+	paths := map[string]string{
+		"base":   base,
+		"ours":   renderWithHash(map[string]string{"scope": "api", "formality": "F1"}, mergeBaseBody),
+		"theirs": renderWithHash(map[string]string{"scope": "api", "claim_scope": "linux"}, mergeBaseBody),
+	}
+	for name, content := range paths {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	name := ".quint/knowledge/L0/redis.md"
+	oursPath := filepath.Join(tempDir, "ours")
+	result, err := tools.MergeFile(filepath.Join(tempDir, "base"), oursPath, filepath.Join(tempDir, "theirs"), name)
+	if err != nil {
+		t.Fatalf("MergeFile failed: %v", err)
+	}
+	if len(result.Conflicts) != 0 {
+		t.Fatalf("Expected a clean merge, got %v", result.Conflicts)
+	}
+
+	if _, tampered, _, _, err := ValidateFile(oursPath); err != nil || tampered {
+		t.Errorf("Expected the merged file to validate (tampered=%v, err=%v)", tampered, err)
+	}
+	merged, _ := os.ReadFile(oursPath)
+	if !strings.Contains(string(merged), "formality: F1") || !strings.Contains(string(merged), "claim_scope: linux") {
+		t.Errorf("Expected both sides' fields in:\n%s", merged)
+	}
+
+	logs, err := tools.DB.GetAuditLogByTarget(context.Background(), name)
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("Expected one audit entry for the merge, got %d (%v)", len(logs), err)
+	}
+	if logs[0].ToolName != "quint_merge_driver" || logs[0].Result != "SUCCESS" || !strings.Contains(logs[0].Details.String, "claim_scope") {
+		t.Errorf("Unexpected audit entry: %+v", logs[0])
+	}
+}
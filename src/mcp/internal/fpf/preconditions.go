@@ -25,6 +25,10 @@ func (t *Tools) CheckPreconditions(toolName string, args map[string]string) erro
 		return t.checkVerifyPreconditions(args)
 	case "quint_test":
 		return t.checkTestPreconditions(args)
+	case "quint_ingest_tests":
+		return t.checkIngestTestsPreconditions(args)
+	case "quint_refine":
+		return t.checkRefinePreconditions(args)
 	case "quint_audit":
 		return t.checkAuditPreconditions(args)
 	case "quint_decide":
@@ -33,6 +37,8 @@ func (t *Tools) CheckPreconditions(toolName string, args map[string]string) erro
 		return t.checkCalculateRPreconditions(args)
 	case "quint_audit_tree":
 		return t.checkAuditTreePreconditions(args)
+	case "quint_context":
+		return t.checkContextPreconditions(args)
 	default:
 		return nil
 	}
@@ -94,11 +100,73 @@ func (t *Tools) checkVerifyPreconditions(args map[string]string) error {
 	return nil
 }
 
+func (t *Tools) checkRefinePreconditions(args map[string]string) error {
+	parentID := args["parent_id"]
+	if parentID == "" {
+		return &PreconditionError{
+			Tool:       "quint_refine",
+			Condition:  "parent_id is required",
+			Suggestion: "Specify which hypothesis to refine",
+		}
+	}
+
+	for _, layer := range []string{"L0", "L1", "invalid"} {
+		if _, err := os.Stat(filepath.Join(t.GetFPFDir(), "knowledge", layer, parentID+".md")); err == nil {
+			return nil
+		}
+	}
+	return &PreconditionError{
+		Tool:       "quint_refine",
+		Condition:  fmt.Sprintf("hypothesis '%s' not found in L0, L1 or invalid", parentID),
+		Suggestion: "Only hypotheses that went through verification or validation can be refined; check the hypothesis ID",
+	}
+}
+
 func (t *Tools) checkTestPreconditions(args map[string]string) error {
-	hypoID := args["hypothesis_id"]
-	if hypoID == "" {
+	if err := t.checkTestableHypothesis("quint_test", args["hypothesis_id"]); err != nil {
+		return err
+	}
+
+	verdict := args["verdict"]
+	if verdict != "PASS" && verdict != "FAIL" && verdict != "REFINE" {
 		return &PreconditionError{
 			Tool:       "quint_test",
+			Condition:  "verdict must be PASS, FAIL, or REFINE",
+			Suggestion: "Specify the test outcome",
+		}
+	}
+
+	return nil
+}
+
+func (t *Tools) checkIngestTestsPreconditions(args map[string]string) error {
+	if err := t.checkTestableHypothesis("quint_ingest_tests", args["hypothesis_id"]); err != nil {
+		return err
+	}
+
+	if args["format"] != FormatGoTest && args["format"] != FormatJUnit {
+		return &PreconditionError{
+			Tool:       "quint_ingest_tests",
+			Condition:  fmt.Sprintf("format must be %s or %s", FormatGoTest, FormatJUnit),
+			Suggestion: "Use gotest for go test -json output, junit for JUnit XML reports",
+		}
+	}
+	if args["path"] == "" {
+		return &PreconditionError{
+			Tool:       "quint_ingest_tests",
+			Condition:  "path is required",
+			Suggestion: "Point at the test report file",
+		}
+	}
+
+	return nil
+}
+
+// checkTestableHypothesis requires a hypothesis in L1, or in L2 for a refresh
+func (t *Tools) checkTestableHypothesis(tool, hypoID string) error {
+	if hypoID == "" {
+		return &PreconditionError{
+			Tool:       tool,
 			Condition:  "hypothesis_id is required",
 			Suggestion: "Specify which hypothesis to test",
 		}
@@ -107,7 +175,7 @@ func (t *Tools) checkTestPreconditions(args map[string]string) error {
 	l0Path := filepath.Join(t.GetFPFDir(), "knowledge", "L0", hypoID+".md")
 	if _, err := os.Stat(l0Path); err == nil {
 		return &PreconditionError{
-			Tool:       "quint_test",
+			Tool:       tool,
 			Condition:  fmt.Sprintf("hypothesis '%s' is still in L0", hypoID),
 			Suggestion: "Run /q2-verify first to promote the hypothesis to L1 before testing",
 		}
@@ -131,29 +199,20 @@ func (t *Tools) checkTestPreconditions(args map[string]string) error {
 			holon, err := t.DB.GetHolon(ctx, hypoID)
 			if err != nil || (holon.Layer != "L1" && holon.Layer != "L2") {
 				return &PreconditionError{
-					Tool:       "quint_test",
+					Tool:       tool,
 					Condition:  fmt.Sprintf("hypothesis '%s' not found in L1 or L2", hypoID),
 					Suggestion: "Ensure hypothesis exists and has been verified (L0 -> L1) first. L2 hypotheses can also be tested to refresh evidence.",
 				}
 			}
 		} else {
 			return &PreconditionError{
-				Tool:       "quint_test",
+				Tool:       tool,
 				Condition:  fmt.Sprintf("hypothesis '%s' not found in L1 or L2", hypoID),
 				Suggestion: "Ensure hypothesis exists and has been verified (L0 -> L1) first. L2 hypotheses can also be tested to refresh evidence.",
 			}
 		}
 	}
 
-	verdict := args["verdict"]
-	if verdict != "PASS" && verdict != "FAIL" && verdict != "REFINE" {
-		return &PreconditionError{
-			Tool:       "quint_test",
-			Condition:  "verdict must be PASS, FAIL, or REFINE",
-			Suggestion: "Specify the test outcome",
-		}
-	}
-
 	return nil
 }
 
@@ -209,7 +268,7 @@ func (t *Tools) checkDecidePreconditions(args map[string]string) error {
 
 	if t.DB != nil {
 		ctx := context.Background()
-		counts, _ := t.DB.CountHolonsByLayer(ctx, "default")
+		counts, _ := t.DB.CountHolonsByLayer(ctx, t.ContextID())
 
 		l2Count := int64(0)
 		for _, c := range counts {
@@ -282,3 +341,33 @@ func (t *Tools) checkAuditTreePreconditions(args map[string]string) error {
 
 	return nil
 }
+
+func (t *Tools) checkContextPreconditions(args map[string]string) error {
+	if t.DB == nil {
+		return &PreconditionError{
+			Tool:       "quint_context",
+			Condition:  "database not initialized",
+			Suggestion: "Run /q0-init to initialize the project first",
+		}
+	}
+
+	switch args["action"] {
+	case "list":
+		return nil
+	case "create", "switch", "archive":
+		if args["name"] == "" {
+			return &PreconditionError{
+				Tool:       "quint_context",
+				Condition:  fmt.Sprintf("name is required for action '%s'", args["action"]),
+				Suggestion: "Run quint_context with action=list to see available contexts",
+			}
+		}
+		return nil
+	default:
+		return &PreconditionError{
+			Tool:       "quint_context",
+			Condition:  "action must be create, list, switch or archive",
+			Suggestion: "Specify which context operation to perform",
+		}
+	}
+}
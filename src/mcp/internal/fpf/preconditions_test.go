@@ -88,7 +88,7 @@ func TestCheckPreconditions_Verify(t *testing.T) {
 			name: "valid verify with existing L0 hypo",
 			args: map[string]string{
 				"hypothesis_id": hypoID,
-				"checks_json":   "{}",
+				"checks":        "[]",
 				"verdict":       "PASS",
 			},
 			wantErr: false,
@@ -96,8 +96,8 @@ func TestCheckPreconditions_Verify(t *testing.T) {
 		{
 			name: "missing hypothesis_id",
 			args: map[string]string{
-				"checks_json": "{}",
-				"verdict":     "PASS",
+				"checks":  "[]",
+				"verdict": "PASS",
 			},
 			wantErr: true,
 		},
@@ -105,7 +105,7 @@ func TestCheckPreconditions_Verify(t *testing.T) {
 			name: "non-existent hypothesis",
 			args: map[string]string{
 				"hypothesis_id": "non-existent",
-				"checks_json":   "{}",
+				"checks":        "[]",
 				"verdict":       "PASS",
 			},
 			wantErr: true,
@@ -114,7 +114,7 @@ func TestCheckPreconditions_Verify(t *testing.T) {
 			name: "invalid verdict",
 			args: map[string]string{
 				"hypothesis_id": hypoID,
-				"checks_json":   "{}",
+				"checks":        "[]",
 				"verdict":       "INVALID",
 			},
 			wantErr: true,
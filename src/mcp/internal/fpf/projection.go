@@ -6,8 +6,11 @@ import (
 	"encoding/hex"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/m0n0x41d/quint-code/db"
 )
@@ -49,17 +52,54 @@ func extractHashFromFrontmatter(frontmatter string) string {
 }
 
 func WriteWithHash(path string, frontmatterFields map[string]string, body string) error {
+	return os.WriteFile(path, []byte(renderWithHash(frontmatterFields, body)), 0644)
+}
+
+// renderWithHash builds a projection file: frontmatter with the body's content_hash, then the body.
+// Fields are written in name order so that unchanged files stay byte-identical.
+func renderWithHash(frontmatterFields map[string]string, body string) string {
 	hash := ComputeContentHash(body)
 
 	var fm strings.Builder
 	fm.WriteString("---\n")
-	for k, v := range frontmatterFields {
-		fm.WriteString(fmt.Sprintf("%s: %s\n", k, v))
+	for _, k := range sortedKeys(frontmatterFields) {
+		fm.WriteString(fmt.Sprintf("%s: %s\n", k, frontmatterFields[k]))
 	}
 	fm.WriteString(fmt.Sprintf("content_hash: %s\n", hash))
 	fm.WriteString("---\n")
 
-	content := fm.String() + body
+	return fm.String() + body
+}
+
+// UpdateFrontmatter sets frontmatter fields of an existing projection file in place.
+// The body and its content_hash are left untouched.
+func UpdateFrontmatter(path string, fields map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	frontmatter, body, ok := parseFrontmatter(string(data))
+	if !ok {
+		return fmt.Errorf("no frontmatter in %s", path)
+	}
+
+	lines := strings.Split(frontmatter, "\n")
+	for k, v := range fields {
+		replaced := false
+		for i, line := range lines {
+			if strings.HasPrefix(line, k+":") {
+				lines[i] = fmt.Sprintf("%s: %s", k, v)
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			lines = append(lines, fmt.Sprintf("%s: %s", k, v))
+		}
+	}
+
+	content := "---\n" + strings.Join(lines, "\n") + "\n---\n" + body
 	return os.WriteFile(path, []byte(content), 0644)
 }
 
@@ -146,13 +186,10 @@ func (t *Tools) regenerateFromDB(path string) (bool, error) {
 		return false, nil
 	}
 
-	body := fmt.Sprintf("\n# Hypothesis: %s\n\n%s", holon.Title, holon.Content)
-
-	fields := map[string]string{
-		"scope": holon.Scope.String,
-		"kind":  holon.Kind.String,
+	fields, body, err := loadHolonProjection(ctx, t.DB, holon)
+	if err != nil {
+		return false, err
 	}
-
 	if err := WriteWithHash(path, fields, body); err != nil {
 		return false, err
 	}
@@ -198,12 +235,192 @@ func RegenerateHolonFile(store *db.Store, holonID, fpfDir string) error {
 
 	path := fmt.Sprintf("%s/knowledge/%s/%s.md", fpfDir, holon.Layer, holonID)
 
-	body := fmt.Sprintf("\n# Hypothesis: %s\n\n%s", holon.Title, holon.Content)
+	fields, body, err := loadHolonProjection(ctx, store, holon)
+	if err != nil {
+		return err
+	}
+	return WriteWithHash(path, fields, body)
+}
 
+// holonProjection returns the frontmatter and body of a hypothesis file. The
+// holon content already is the projection body written by ProposeHypothesis.
+// Claims, lineage and the relations the holon owns go into the frontmatter so
+// that reindex can rebuild them from the files alone.
+func holonProjection(holon db.Holon, relations []db.Relation) (map[string]string, string) {
 	fields := map[string]string{
-		"scope": holon.Scope.String,
-		"kind":  holon.Kind.String,
+		"scope":   holon.Scope.String,
+		"kind":    holon.Kind.String,
+		"context": holon.ContextID,
+	}
+	if holon.Formality.Valid && holon.Formality.Int64 > 0 {
+		fields["formality"] = fmt.Sprintf("F%d", holon.Formality.Int64)
+	}
+	if holon.ClaimScope.String != "" {
+		fields["claim_scope"] = holon.ClaimScope.String
+	}
+	if holon.ParentID.String != "" {
+		fields["parent_id"] = holon.ParentID.String
 	}
 
-	return WriteWithHash(path, fields, body)
+	var deps, contexts []string
+	for _, r := range relations {
+		switch {
+		case r.TargetID == holon.ID && (r.RelationType == "componentOf" || r.RelationType == "constituentOf"):
+			deps = append(deps, fmt.Sprintf("%s (CL%d)", r.SourceID, congruenceLevel(r)))
+		case r.SourceID == holon.ID && r.RelationType == "memberOf":
+			contexts = append(contexts, r.TargetID)
+		}
+	}
+	if len(deps) > 0 {
+		sort.Strings(deps)
+		fields["depends_on"] = strings.Join(deps, ", ")
+	}
+	if len(contexts) > 0 {
+		sort.Strings(contexts)
+		fields["decision_context"] = strings.Join(contexts, ", ")
+	}
+	if anchors := anchorsOf(holon.ID, relations); len(anchors) > 0 {
+		fields["anchors"] = strings.Join(anchors, ", ")
+	}
+	return fields, holon.Content
+}
+
+// decisionProjection returns the frontmatter and body of a DRR file
+func decisionProjection(holon db.Holon, relations []db.Relation) (map[string]string, string) {
+	created := time.Now()
+	if holon.CreatedAt.Valid {
+		created = holon.CreatedAt.Time
+	}
+	fields := map[string]string{
+		"type":      "DRR",
+		"winner_id": holon.ParentID.String,
+		"context":   holon.ContextID,
+		"created":   created.Format(time.RFC3339),
+	}
+
+	var rejected []string
+	for _, r := range relations {
+		if r.SourceID == holon.ID && r.RelationType == "rejects" {
+			rejected = append(rejected, r.TargetID)
+		}
+	}
+	if len(rejected) > 0 {
+		sort.Strings(rejected)
+		fields["rejected_ids"] = strings.Join(rejected, ", ")
+	}
+	if anchors := anchorsOf(holon.ID, relations); len(anchors) > 0 {
+		fields["anchors"] = strings.Join(anchors, ", ")
+	}
+	return fields, holon.Content
+}
+
+// evidenceProjection returns the frontmatter and body of an evidence file, as ManageEvidence writes it
+func evidenceProjection(e db.Evidence) (map[string]string, string) {
+	fields := map[string]string{
+		"id":              e.ID,
+		"type":            e.Type,
+		"target":          e.HolonID,
+		"verdict":         e.Verdict,
+		"assurance_level": e.AssuranceLevel.String,
+		"carrier_ref":     e.CarrierRef.String,
+		"valid_until":     "",
+		"date":            "",
+	}
+	if e.ValidUntil.Valid {
+		fields["valid_until"] = e.ValidUntil.Time.Format("2006-01-02")
+	}
+	if e.CreatedAt.Valid {
+		fields["date"] = e.CreatedAt.Time.Format("2006-01-02")
+	}
+	if e.Formality.Valid {
+		fields["formality"] = fmt.Sprintf("F%d", e.Formality.Int64)
+	}
+	if e.ArtifactHash.Valid {
+		fields["artifact_kind"] = e.ArtifactKind.String
+		fields["artifact_ref"] = e.ArtifactRef.String
+		fields["artifact_hash"] = e.ArtifactHash.String
+		if e.ArtifactSource.String != "" {
+			fields["artifact_source"] = e.ArtifactSource.String
+		}
+	}
+	if e.Recipe.Valid {
+		fields["recipe"] = e.Recipe.String
+	}
+	return fields, "\n" + e.Content
+}
+
+// loadHolonProjection reads a holon's relations and renders its hypothesis file
+func loadHolonProjection(ctx context.Context, store *db.Store, holon db.Holon) (map[string]string, string, error) {
+	relations, err := store.GetHolonRelations(ctx, holon.ID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load relations of %s: %w", holon.ID, err)
+	}
+	fields, body := holonProjection(holon, relations)
+	return fields, body, nil
+}
+
+// writeHolonProjection renders a hypothesis file from the database
+func (t *Tools) writeHolonProjection(ctx context.Context, holonID string) error {
+	holon, err := t.DB.GetHolon(ctx, holonID)
+	if err != nil {
+		return fmt.Errorf("holon not found: %w", err)
+	}
+	fields, body, err := loadHolonProjection(ctx, t.DB, holon)
+	if err != nil {
+		return err
+	}
+	return t.writeProjection(filepath.Join(t.GetFPFDir(), "knowledge", holon.Layer, holonID+".md"), fields, body)
+}
+
+// writeDecisionProjection renders a DRR file from the database
+func (t *Tools) writeDecisionProjection(ctx context.Context, holonID string) error {
+	holon, err := t.DB.GetHolon(ctx, holonID)
+	if err != nil {
+		return fmt.Errorf("holon not found: %w", err)
+	}
+	relations, err := t.DB.GetHolonRelations(ctx, holonID)
+	if err != nil {
+		return fmt.Errorf("failed to load relations of %s: %w", holonID, err)
+	}
+	fields, body := decisionProjection(holon, relations)
+	created, _ := time.Parse(time.RFC3339, fields["created"])
+	path := filepath.Join(t.GetFPFDir(), "decisions", fmt.Sprintf("DRR-%s-%s.md", created.Format("2006-01-02"), holonID))
+	return t.writeProjection(path, fields, body)
+}
+
+// waiverProjection returns the frontmatter and body of a waiver file
+func waiverProjection(w db.Waiver) (map[string]string, string) {
+	created := time.Now()
+	if w.CreatedAt.Valid {
+		created = w.CreatedAt.Time
+	}
+	fields := map[string]string{
+		"id":           w.ID,
+		"evidence_id":  w.EvidenceID,
+		"waived_by":    w.WaivedBy,
+		"waived_until": w.WaivedUntil.Format(time.RFC3339),
+		"created":      created.Format(time.RFC3339),
+	}
+	if w.RevokedAt.Valid {
+		fields["revoked_at"] = w.RevokedAt.Time.Format(time.RFC3339)
+	}
+	return fields, "\n" + w.Rationale + "\n"
+}
+
+func congruenceLevel(r db.Relation) int {
+	if r.CongruenceLevel.Valid {
+		return int(r.CongruenceLevel.Int64)
+	}
+	return 3
+}
+
+// frontmatterFields parses the "key: value" lines of a frontmatter block
+func frontmatterFields(frontmatter string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(frontmatter, "\n") {
+		if k, v, ok := strings.Cut(line, ":"); ok {
+			fields[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return fields
 }
@@ -0,0 +1,119 @@
+package fpf
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PromptArgumentsName is the single free-form argument every prompt accepts.
+// Its value replaces $ARGUMENTS; its whitespace-separated fields replace $1…$9.
+const PromptArgumentsName = "arguments"
+
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type PromptMessage struct {
+	Role    string      `json:"role"`
+	Content ContentItem `json:"content"`
+}
+
+// PromptTemplate is a slash command served through prompts/get
+type PromptTemplate struct {
+	Prompt
+	Body string
+}
+
+var (
+	descriptionRe = regexp.MustCompile(`(?m)^description:\s*(.+?)\s*$`)
+	positionalRe  = regexp.MustCompile(`\$([1-9])`)
+)
+
+// LoadPrompts reads every *.md command at the root of fsys. The prompt name is
+// the file name without extension; the description comes from the frontmatter,
+// falling back to the first heading.
+func LoadPrompts(fsys fs.FS) ([]PromptTemplate, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompts: %w", err)
+	}
+
+	var prompts []PromptTemplate
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".md" {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt %s: %w", entry.Name(), err)
+		}
+		prompts = append(prompts, parsePrompt(strings.TrimSuffix(entry.Name(), ".md"), string(data)))
+	}
+
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+	return prompts, nil
+}
+
+func parsePrompt(name, content string) PromptTemplate {
+	frontmatter, body, _ := parseFrontmatter(content)
+
+	description := ""
+	if m := descriptionRe.FindStringSubmatch(frontmatter); m != nil {
+		description = strings.Trim(m[1], `"'`)
+	}
+	if description == "" {
+		for _, line := range strings.Split(body, "\n") {
+			if strings.HasPrefix(line, "#") {
+				description = strings.TrimSpace(strings.TrimLeft(line, "# "))
+				break
+			}
+		}
+	}
+
+	return PromptTemplate{
+		Prompt: Prompt{
+			Name:        name,
+			Description: description,
+			Arguments: []PromptArgument{{
+				Name:        PromptArgumentsName,
+				Description: "Free-form input for the command ($ARGUMENTS; $1, $2… are its whitespace-separated words)",
+			}},
+		},
+		Body: strings.TrimLeft(body, "\n"),
+	}
+}
+
+// Render substitutes $ARGUMENTS and $1…$9. When the command does not reference
+// $ARGUMENTS, non-empty input is appended so it still reaches the model.
+func (p PromptTemplate) Render(arguments string) string {
+	arguments = strings.TrimSpace(arguments)
+	fields := strings.Fields(arguments)
+
+	text := positionalRe.ReplaceAllStringFunc(p.Body, func(m string) string {
+		i := int(m[1] - '1')
+		if i < len(fields) {
+			return fields[i]
+		}
+		return ""
+	})
+
+	if strings.Contains(text, "$ARGUMENTS") {
+		return strings.ReplaceAll(text, "$ARGUMENTS", arguments)
+	}
+	if arguments != "" {
+		text = strings.TrimRight(text, "\n") + "\n\nARGUMENTS: " + arguments + "\n"
+	}
+	return text
+}
@@ -0,0 +1,94 @@
+package fpf
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func testPromptFS() fstest.MapFS {
+	return fstest.MapFS{
+		"q1-hypothesize.md": {Data: []byte("---\ndescription: \"Generate Hypotheses (Abduction)\"\n---\n\n# Phase 1\n\nProblem: $ARGUMENTS\nFirst word: $1\n")},
+		"q-decay.md":        {Data: []byte("# q-decay: Evidence Freshness\n\nCheck decay.\n")},
+		"notes.txt":         {Data: []byte("ignored")},
+	}
+}
+
+func TestLoadPrompts(t *testing.T) {
+	prompts, err := LoadPrompts(testPromptFS())
+	if err != nil {
+		t.Fatalf("LoadPrompts failed: %v", err)
+	}
+
+	if len(prompts) != 2 {
+		t.Fatalf("expected 2 prompts, got %d", len(prompts))
+	}
+	if prompts[0].Name != "q-decay" || prompts[0].Description != "q-decay: Evidence Freshness" {
+		t.Errorf("expected heading fallback for q-decay, got %+v", prompts[0].Prompt)
+	}
+	if prompts[1].Name != "q1-hypothesize" || prompts[1].Description != "Generate Hypotheses (Abduction)" {
+		t.Errorf("expected frontmatter description, got %+v", prompts[1].Prompt)
+	}
+	if strings.Contains(prompts[1].Body, "description:") {
+		t.Error("frontmatter should not be part of the prompt body")
+	}
+}
+
+func TestPromptTemplate_Render(t *testing.T) {
+	prompts, _ := LoadPrompts(testPromptFS())
+	hypothesize, decay := prompts[1], prompts[0]
+
+	text := hypothesize.Render("slow checkout page")
+	if !strings.Contains(text, "Problem: slow checkout page") || !strings.Contains(text, "First word: slow") {
+		t.Errorf("unexpected substitution:\n%s", text)
+	}
+
+	text = hypothesize.Render("")
+	if !strings.Contains(text, "First word: \n") || strings.Contains(text, "$") {
+		t.Errorf("expected placeholders removed without input:\n%s", text)
+	}
+
+	text = decay.Render("only auth")
+	if !strings.HasSuffix(text, "ARGUMENTS: only auth\n") {
+		t.Errorf("expected input appended when $ARGUMENTS is not referenced:\n%s", text)
+	}
+}
+
+func TestServer_Prompts(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	s := NewServer(tools)
+	prompts, _ := LoadPrompts(testPromptFS())
+	s.SetPrompts(prompts)
+
+	resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`), nil)
+	if !strings.Contains(string(resp), `"prompts"`) {
+		t.Errorf("expected prompts capability, got %s", resp)
+	}
+
+	resp = s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"prompts/list"}`), nil)
+	if !strings.Contains(string(resp), `"q1-hypothesize"`) {
+		t.Errorf("expected q1-hypothesize in prompts/list, got %s", resp)
+	}
+
+	resp = s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":3,"method":"prompts/get","params":{"name":"q1-hypothesize","arguments":{"arguments":"cache layer"}}}`), nil)
+	var msg struct {
+		Result struct {
+			Messages []PromptMessage `json:"messages"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(resp, &msg); err != nil || len(msg.Result.Messages) != 1 {
+		t.Fatalf("unexpected prompts/get response %s: %v", resp, err)
+	}
+	if !strings.Contains(msg.Result.Messages[0].Content.Text, "Problem: cache layer") {
+		t.Errorf("expected substituted prompt, got %q", msg.Result.Messages[0].Content.Text)
+	}
+
+	resp = s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":4,"method":"prompts/get","params":{"name":"q9-nope"}}`), nil)
+	var errMsg map[string]interface{}
+	_ = json.Unmarshal(resp, &errMsg)
+	if errorCode(errMsg) != CodeInvalidParams {
+		t.Errorf("expected -32602 for unknown prompt, got %s", resp)
+	}
+}
@@ -0,0 +1,498 @@
+package fpf
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/assurance"
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// projectedRelation is a relation read back from a file's frontmatter
+type projectedRelation struct {
+	SourceID     string
+	RelationType string
+	TargetID     string
+	CL           int
+}
+
+// projectedHolon is a hypothesis or DRR file parsed back into its row and the relations it owns
+type projectedHolon struct {
+	Holon     db.Holon
+	Relations []projectedRelation
+}
+
+var dependencyPattern = regexp.MustCompile(`^(\S+)\s*\(CL([0-3])\)$`)
+
+// ReindexFailure is a file reindex could not import
+type ReindexFailure struct {
+	Path   string
+	Reason string
+}
+
+// ReindexReport summarizes a database rebuild from the markdown projection
+type ReindexReport struct {
+	Holons    int
+	Decisions int
+	Evidence  int
+	Relations int
+	Waivers   int
+	Failures  []ReindexFailure
+}
+
+func (r *ReindexReport) String() string {
+	var sb strings.Builder
+	sb.WriteString("## Reindex Report\n\n")
+	sb.WriteString(fmt.Sprintf("Imported %d hypotheses, %d decisions, %d evidence records, %d relations and %d waivers.\n",
+		r.Holons, r.Decisions, r.Evidence, r.Relations, r.Waivers))
+
+	if len(r.Failures) > 0 {
+		sb.WriteString(fmt.Sprintf("\n### Not imported (%d)\n", len(r.Failures)))
+		for _, f := range r.Failures {
+			sb.WriteString(fmt.Sprintf("- `%s`: %s\n", f.Path, f.Reason))
+		}
+	}
+	return sb.String()
+}
+
+// Reindex rebuilds holons, evidence, relations and waivers from the files in
+// .quint/, e.g. after a fresh clone where quint.db is git-ignored. Everything
+// is replaced in one unit of work; files that cannot be imported are reported
+// and skipped. Cached R scores are recomputed afterwards.
+func (t *Tools) Reindex() (*ReindexReport, error) {
+	defer t.RecordWork("Reindex", time.Now())
+	if t.DB == nil {
+		return nil, fmt.Errorf("DB not initialized")
+	}
+
+	report := &ReindexReport{}
+	fpfDir := t.GetFPFDir()
+	fail := func(path string, err error) {
+		if rel, relErr := filepath.Rel(fpfDir, path); relErr == nil {
+			path = rel
+		}
+		report.Failures = append(report.Failures, ReindexFailure{Path: path, Reason: err.Error()})
+	}
+
+	err := t.transact(func() error {
+		ctx := context.Background()
+		if err := t.DB.ClearKnowledge(ctx); err != nil {
+			return fmt.Errorf("failed to clear database: %v", err)
+		}
+
+		imported := make(map[string]bool)
+		contexts := make(map[string]bool)
+		type pendingRelation struct {
+			path string
+			rel  projectedRelation
+		}
+		var pending []pendingRelation
+
+		// Hypotheses; an ID filed in several layers is ambiguous and left to doctor
+		byID := make(map[string][]string)
+		for _, layer := range knowledgeLayers {
+			matches, _ := filepath.Glob(filepath.Join(fpfDir, "knowledge", layer, "*.md"))
+			for _, path := range matches {
+				id := strings.TrimSuffix(filepath.Base(path), ".md")
+				byID[id] = append(byID[id], path)
+			}
+		}
+		for _, id := range sortedKeys(byID) {
+			paths := byID[id]
+			if len(paths) > 1 {
+				for _, path := range paths {
+					fail(path, fmt.Errorf("%s is filed in %d layers; keep one and rerun", id, len(paths)))
+				}
+				continue
+			}
+			p, err := readHypothesisFile(paths[0])
+			if err == nil {
+				err = t.importHolon(ctx, p.Holon)
+			}
+			if err != nil {
+				fail(paths[0], err)
+				continue
+			}
+			imported[id] = true
+			contexts[p.Holon.ContextID] = true
+			report.Holons++
+			for _, rel := range p.Relations {
+				pending = append(pending, pendingRelation{paths[0], rel})
+			}
+		}
+
+		decisions, _ := filepath.Glob(filepath.Join(fpfDir, "decisions", "DRR-*.md"))
+		for _, path := range decisions {
+			p, err := readDecisionFile(path)
+			if err == nil && imported[p.Holon.ID] {
+				err = fmt.Errorf("holon %s already imported from knowledge/", p.Holon.ID)
+			}
+			if err == nil {
+				err = t.importHolon(ctx, p.Holon)
+			}
+			if err != nil {
+				fail(path, err)
+				continue
+			}
+			imported[p.Holon.ID] = true
+			contexts[p.Holon.ContextID] = true
+			report.Decisions++
+			for _, rel := range p.Relations {
+				pending = append(pending, pendingRelation{path, rel})
+			}
+		}
+
+		// Relations once every endpoint is known; anchors point at code, not holons
+		for _, p := range pending {
+			endpoints := []string{p.rel.SourceID, p.rel.TargetID}
+			if p.rel.RelationType == RelationAnchoredTo {
+				endpoints = endpoints[:1]
+			}
+			missing := false
+			for _, id := range endpoints {
+				if !imported[id] {
+					fail(p.path, fmt.Errorf("%s relation %s -> %s: holon %s not found", p.rel.RelationType, p.rel.SourceID, p.rel.TargetID, id))
+					missing = true
+					break
+				}
+			}
+			if missing {
+				continue
+			}
+			if err := t.DB.CreateRelation(ctx, p.rel.SourceID, p.rel.RelationType, p.rel.TargetID, p.rel.CL); err != nil {
+				fail(p.path, err)
+				continue
+			}
+			report.Relations++
+		}
+
+		evidenceIDs := make(map[string]bool)
+		evidence, _ := filepath.Glob(filepath.Join(fpfDir, "evidence", "*.md"))
+		for _, path := range evidence {
+			e, err := readEvidenceFile(path)
+			if err == nil && !imported[e.HolonID] {
+				err = fmt.Errorf("target holon %s not found", e.HolonID)
+			}
+			if err == nil {
+				err = t.importEvidence(ctx, e)
+			}
+			if err != nil {
+				fail(path, err)
+				continue
+			}
+			evidenceIDs[e.ID] = true
+			report.Evidence++
+			report.Relations++ // verifiedBy
+		}
+
+		waivers, _ := filepath.Glob(filepath.Join(fpfDir, "waivers", "*.md"))
+		for _, path := range waivers {
+			w, err := readWaiverFile(path)
+			if err == nil && !evidenceIDs[w.EvidenceID] {
+				err = fmt.Errorf("evidence %s not found", w.EvidenceID)
+			}
+			if err == nil {
+				err = t.DB.CreateWaiver(ctx, w.ID, w.EvidenceID, w.WaivedBy, w.WaivedUntil, w.Rationale)
+			}
+			if err == nil && w.RevokedAt.Valid {
+				err = t.DB.RevokeWaiver(ctx, w.ID, w.RevokedAt.Time)
+			}
+			if err != nil {
+				fail(path, err)
+				continue
+			}
+			report.Waivers++
+		}
+
+		// Contexts only exist in the DB; recreate the ones files refer to
+		for _, id := range sortedKeys(contexts) {
+			if id == DefaultContextID {
+				continue
+			}
+			if _, err := t.DB.GetContext(ctx, id); err == nil {
+				continue
+			}
+			if err := t.DB.CreateContext(ctx, id, id, ""); err != nil {
+				return fmt.Errorf("failed to recreate context %s: %v", id, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.AuditLog("quint_reindex", "reindex", "user", "", "ERROR", nil, err.Error())
+		return nil, err
+	}
+
+	if err := t.RunDecay(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to recompute R scores: %v\n", err)
+	}
+
+	t.AuditLog("quint_reindex", "reindex", "user", "", "SUCCESS",
+		map[string]string{"holons": strconv.Itoa(report.Holons + report.Decisions), "evidence": strconv.Itoa(report.Evidence)},
+		fmt.Sprintf("%d file(s) not imported", len(report.Failures)))
+	return report, nil
+}
+
+// importHolon inserts a holon parsed from a file, including its F-G claim
+func (t *Tools) importHolon(ctx context.Context, h db.Holon) error {
+	if err := t.DB.CreateHolon(ctx, h.ID, h.Type, h.Kind.String, h.Layer, h.Title, h.Content, h.ContextID, h.Scope.String, h.ParentID.String); err != nil {
+		return err
+	}
+	if h.Formality.Valid || h.ClaimScope.String != "" {
+		return t.DB.UpdateHolonClaim(ctx, h.ID, int(h.Formality.Int64), h.ClaimScope.String)
+	}
+	return nil
+}
+
+// importEvidence inserts evidence parsed from a file and links it to its target
+func (t *Tools) importEvidence(ctx context.Context, e db.Evidence) error {
+	validUntil := ""
+	if e.ValidUntil.Valid {
+		validUntil = e.ValidUntil.Time.Format(time.RFC3339)
+	}
+	if err := t.DB.AddEvidence(ctx, e.ID, e.HolonID, e.Type, e.Content, e.Verdict,
+		e.AssuranceLevel.String, e.CarrierRef.String, validUntil); err != nil {
+		return err
+	}
+	if e.Formality.Valid {
+		if err := t.DB.UpdateEvidenceFormality(ctx, e.ID, int(e.Formality.Int64)); err != nil {
+			return err
+		}
+	}
+	if e.ArtifactHash.Valid {
+		if err := t.DB.UpdateEvidenceArtifact(ctx, e.ID, e.ArtifactKind.String, e.ArtifactRef.String, e.ArtifactHash.String, e.ArtifactSource.String); err != nil {
+			return err
+		}
+	}
+	if e.Recipe.Valid {
+		if err := t.DB.UpdateEvidenceRecipe(ctx, e.ID, e.Recipe.String); err != nil {
+			return err
+		}
+	}
+	return t.DB.Link(ctx, e.ID, e.HolonID, "verifiedBy")
+}
+
+// readProjection returns a file's frontmatter fields (without content_hash) and body
+func readProjection(path string) (map[string]string, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	frontmatter, body, ok := parseFrontmatter(string(data))
+	if !ok {
+		return nil, "", fmt.Errorf("no frontmatter")
+	}
+	fields := frontmatterFields(frontmatter)
+	delete(fields, "content_hash")
+	return fields, body, nil
+}
+
+// readHypothesisFile parses knowledge/<layer>/<id>.md
+func readHypothesisFile(path string) (*projectedHolon, error) {
+	fields, body, err := readProjection(path)
+	if err != nil {
+		return nil, err
+	}
+
+	id := strings.TrimSuffix(filepath.Base(path), ".md")
+	kind := fields["kind"]
+	h := db.Holon{
+		ID:         id,
+		Type:       "hypothesis",
+		Kind:       nullString(kind),
+		Layer:      filepath.Base(filepath.Dir(path)),
+		Title:      headingTitle(body, hypothesisHeadingPrefix, id),
+		Content:    body,
+		ContextID:  contextOrDefault(fields["context"]),
+		Scope:      nullString(fields["scope"]),
+		ParentID:   nullString(fields["parent_id"]),
+		ClaimScope: nullString(fields["claim_scope"]),
+	}
+	if h.Formality, err = parseFormality(fields["formality"]); err != nil {
+		return nil, err
+	}
+	p := &projectedHolon{Holon: h}
+
+	relationType := "componentOf"
+	if kind == "episteme" {
+		relationType = "constituentOf"
+	}
+	for _, dep := range splitList(fields["depends_on"]) {
+		depID, cl := dep, 3
+		if m := dependencyPattern.FindStringSubmatch(dep); m != nil {
+			depID = m[1]
+			cl, _ = strconv.Atoi(m[2])
+		}
+		p.Relations = append(p.Relations, projectedRelation{SourceID: depID, RelationType: relationType, TargetID: id, CL: cl})
+	}
+	for _, contextID := range splitList(fields["decision_context"]) {
+		p.Relations = append(p.Relations, projectedRelation{SourceID: id, RelationType: "memberOf", TargetID: contextID, CL: 3})
+	}
+	p.Relations = append(p.Relations, anchorRelations(id, fields["anchors"])...)
+	return p, nil
+}
+
+// readDecisionFile parses decisions/DRR-<date>-<id>.md
+func readDecisionFile(path string) (*projectedHolon, error) {
+	name := strings.TrimSuffix(filepath.Base(path), ".md")
+	if len(name) <= decisionFilePrefixDateSize {
+		return nil, fmt.Errorf("file name is not DRR-<date>-<id>.md")
+	}
+	fields, body, err := readProjection(path)
+	if err != nil {
+		return nil, err
+	}
+
+	id := name[decisionFilePrefixDateSize:]
+	winnerID := fields["winner_id"]
+	h := db.Holon{
+		ID:        id,
+		Type:      "DRR",
+		Layer:     "DRR",
+		Title:     headingTitle(body, "# ", id),
+		Content:   body,
+		ContextID: contextOrDefault(fields["context"]),
+		ParentID:  nullString(winnerID),
+	}
+	if created, err := time.Parse(time.RFC3339, fields["created"]); err == nil {
+		h.CreatedAt = sql.NullTime{Time: created, Valid: true}
+	}
+	p := &projectedHolon{Holon: h}
+
+	if winnerID != "" {
+		p.Relations = append(p.Relations, projectedRelation{SourceID: id, RelationType: "selects", TargetID: winnerID, CL: 3})
+	}
+	for _, rejID := range splitList(fields["rejected_ids"]) {
+		p.Relations = append(p.Relations, projectedRelation{SourceID: id, RelationType: "rejects", TargetID: rejID, CL: 3})
+	}
+	p.Relations = append(p.Relations, anchorRelations(id, fields["anchors"])...)
+	return p, nil
+}
+
+// readEvidenceFile parses evidence/<date>-<type>-<target>.md
+func readEvidenceFile(path string) (db.Evidence, error) {
+	fields, body, err := readProjection(path)
+	if err != nil {
+		return db.Evidence{}, err
+	}
+	if fields["target"] == "" || fields["type"] == "" {
+		return db.Evidence{}, fmt.Errorf("missing target or type in frontmatter")
+	}
+
+	e := db.Evidence{
+		ID:             filepath.Base(path),
+		HolonID:        fields["target"],
+		Type:           fields["type"],
+		Content:        strings.TrimPrefix(body, "\n"),
+		Verdict:        fields["verdict"],
+		AssuranceLevel: nullString(fields["assurance_level"]),
+		CarrierRef:     nullString(fields["carrier_ref"]),
+		ValidUntil:     parseDate(fields["valid_until"]),
+		CreatedAt:      parseDate(fields["date"]),
+		ArtifactKind:   nullString(fields["artifact_kind"]),
+		ArtifactRef:    nullString(fields["artifact_ref"]),
+		ArtifactHash:   nullString(fields["artifact_hash"]),
+		ArtifactSource: nullString(fields["artifact_source"]),
+		Recipe:         nullString(fields["recipe"]),
+	}
+	if e.Formality, err = parseFormality(fields["formality"]); err != nil {
+		return db.Evidence{}, err
+	}
+	return e, nil
+}
+
+// readWaiverFile parses waivers/<id>.md
+func readWaiverFile(path string) (db.Waiver, error) {
+	fields, body, err := readProjection(path)
+	if err != nil {
+		return db.Waiver{}, err
+	}
+	until := parseDate(fields["waived_until"])
+	if fields["evidence_id"] == "" || !until.Valid {
+		return db.Waiver{}, fmt.Errorf("missing evidence_id or waived_until in frontmatter")
+	}
+
+	w := db.Waiver{
+		ID:          fields["id"],
+		EvidenceID:  fields["evidence_id"],
+		WaivedBy:    fields["waived_by"],
+		WaivedUntil: until.Time,
+		Rationale:   strings.TrimSpace(body),
+		CreatedAt:   parseDate(fields["created"]),
+		RevokedAt:   parseDate(fields["revoked_at"]),
+	}
+	if w.ID == "" {
+		w.ID = strings.TrimSuffix(filepath.Base(path), ".md")
+	}
+	if w.WaivedBy == "" {
+		w.WaivedBy = "user"
+	}
+	return w, nil
+}
+
+// headingTitle returns the text of the first body line starting with prefix
+func headingTitle(body, prefix, fallback string) string {
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(line, prefix))
+		}
+	}
+	return fallback
+}
+
+func contextOrDefault(id string) string {
+	if id == "" {
+		return DefaultContextID
+	}
+	return id
+}
+
+// parseFormality reads an "F<n>" frontmatter value
+func parseFormality(v string) (sql.NullInt64, error) {
+	if v == "" {
+		return sql.NullInt64{}, nil
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(v, "F"))
+	if err != nil || n < 0 || n > assurance.MaxFormality {
+		return sql.NullInt64{}, fmt.Errorf("invalid formality %q", v)
+	}
+	return sql.NullInt64{Int64: int64(n), Valid: true}, nil
+}
+
+// parseDate reads a YYYY-MM-DD or RFC3339 frontmatter value
+func parseDate(v string) sql.NullTime {
+	if v == "" {
+		return sql.NullTime{}
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		if t, err = time.Parse("2006-01-02", v); err != nil {
+			return sql.NullTime{}
+		}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+// splitList splits a comma-separated frontmatter value
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	sort.Strings(items)
+	return items
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,172 @@
+package fpf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// knowledgeSnapshot lists the rows reindex must restore, leaving out timestamps
+func knowledgeSnapshot(t *testing.T, tools *Tools) []string {
+	t.Helper()
+	ctx := context.Background()
+	var rows []string
+
+	holons, err := tools.DB.ListAllHolons(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range holons {
+		rows = append(rows, fmt.Sprintf("holon %s %s %s %s %q %q %s %s %s F%d %s R=%.3f",
+			h.ID, h.Type, h.Kind.String, h.Layer, h.Title, h.Content, h.ContextID, h.Scope.String,
+			h.ParentID.String, h.Formality.Int64, h.ClaimScope.String, h.CachedRScore.Float64))
+	}
+
+	evidence, err := tools.DB.ListAllEvidence(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range evidence {
+		rows = append(rows, fmt.Sprintf("evidence %s %s %s %q %s %s %s %s %v",
+			e.ID, e.HolonID, e.Type, e.Content, e.Verdict, e.AssuranceLevel.String, e.CarrierRef.String,
+			e.ValidUntil.Time.Format("2006-01-02"), e.Formality))
+	}
+
+	relations, err := tools.DB.ListAllRelations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range relations {
+		rows = append(rows, fmt.Sprintf("relation %s %s %s CL%d", r.SourceID, r.RelationType, r.TargetID, congruenceLevel(r)))
+	}
+
+	waivers, err := tools.DB.GetAllActiveWaivers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range waivers {
+		rows = append(rows, fmt.Sprintf("waiver %s %s %s %d %q", w.ID, w.EvidenceID, w.WaivedBy, w.WaivedUntil.Unix(), w.Rationale))
+	}
+	return rows
+}
+
+func TestReindex_RoundTrip(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	var verificationIDs []string
+	var benchmarkID string
+	steps := []func() error{
+		func() error {
+			_, err := tools.ProposeHypothesis("Caching Strategy", "Pick a cache", "api", "episteme", "{}", "", nil, 3)
+			return err
+		},
+		func() error {
+			_, err := tools.ProposeHypothesis("Redis", "Use Redis", "api", "system", "{}", "caching-strategy", nil, 3)
+			return err
+		},
+		func() error {
+			_, err := tools.ProposeHypothesis("Memcached", "Use Memcached", "api", "system", "{}", "caching-strategy", nil, 3)
+			return err
+		},
+		func() error {
+			_, err := tools.ProposeHypothesis("Session Store", "Sessions in the cache", "api", "system", "{}", "", []string{"redis"}, 2)
+			return err
+		},
+		func() error { return tools.RecordClaim("redis", 3, []string{"linux"}) },
+		func() error {
+			var err error
+			_, verificationIDs, err = tools.VerifyHypothesis("redis", passingChecks, "PASS")
+			return err
+		},
+		func() error { return tools.RecordVerificationFormality(verificationIDs, 2) },
+		func() error {
+			var err error
+			_, benchmarkID, err = tools.ManageEvidence(PhaseInduction, "add", "redis", "internal", "Benchmarks pass", "PASS", "L2", "bench/redis_test.go", "")
+			return err
+		},
+		func() error {
+			_, err := tools.FinalizeDecision("Cache Choice", "redis", []string{"memcached"}, "Context", "Redis", "Rationale", "Consequences", "")
+			return err
+		},
+		func() error {
+			_, err := tools.RefineHypothesis("memcached", "Needs persistence", "Memcached Persistent", "Use Memcached with a disk tier", "")
+			return err
+		},
+		func() error {
+			_, err := tools.CheckDecay("", benchmarkID, time.Now().AddDate(0, 1, 0).Format("2006-01-02"), "Benchmarks rerun next sprint")
+			return err
+		},
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("setup step %d failed: %v", i, err)
+		}
+	}
+	if err := tools.RunDecay(); err != nil {
+		t.Fatal(err)
+	}
+	before := knowledgeSnapshot(t, tools)
+
+	// A fresh clone: the files are there, the database is empty
+	if err := tools.DB.ClearKnowledge(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := tools.Reindex()
+	if err != nil {
+		t.Fatalf("Reindex failed: %v", err)
+	}
+	if len(report.Failures) != 0 {
+		t.Errorf("Expected every file to import, got:\n%s", report)
+	}
+	if report.Holons != 5 || report.Decisions != 1 || report.Evidence != 2 || report.Waivers != 1 {
+		t.Errorf("Unexpected counts:\n%s", report)
+	}
+
+	after := knowledgeSnapshot(t, tools)
+	if strings.Join(before, "\n") != strings.Join(after, "\n") {
+		t.Errorf("Reindex did not restore the database.\nbefore:\n%s\n\nafter:\n%s", strings.Join(before, "\n"), strings.Join(after, "\n"))
+	}
+
+	if doctor, err := tools.Doctor(false); err != nil || len(doctor.Issues) != 0 {
+		t.Errorf("Expected doctor to find no issues after reindex, got:\n%v (%v)", doctor, err)
+	}
+}
+
+func TestReindex_ReportsUnimportableFiles(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+	quintDir := filepath.Join(tempDir, ".quint")
+
+	_, _ = tools.ProposeHypothesis("Redis", "Use Redis", "api", "system", "{}", "", nil, 3)
+	_, _ = tools.ProposeHypothesis("Queue", "Use a queue", "api", "system", "{}", "", nil, 3)
+
+	queue, _ := os.ReadFile(filepath.Join(quintDir, "knowledge", "L0", "queue.md"))
+	_ = os.WriteFile(filepath.Join(quintDir, "knowledge", "L1", "queue.md"), queue, 0644)
+	_ = os.WriteFile(filepath.Join(quintDir, "knowledge", "L0", "notes.md"), []byte("# Notes without frontmatter\n"), 0644)
+	_ = os.WriteFile(filepath.Join(quintDir, "evidence", "2025-01-01-internal-gone.md"),
+		[]byte(renderWithHash(map[string]string{"type": "internal", "target": "gone", "verdict": "pass"}, "\nOrphaned\n")), 0644)
+
+	report, err := tools.Reindex()
+	if err != nil {
+		t.Fatalf("Reindex failed: %v", err)
+	}
+	if report.Holons != 1 {
+		t.Errorf("Expected only redis to be imported, got %d holons", report.Holons)
+	}
+	if len(report.Failures) != 4 {
+		t.Errorf("Expected 4 failures (two queue copies, notes, orphaned evidence), got:\n%s", report)
+	}
+	for _, want := range []string{"knowledge/L0/queue.md", "knowledge/L1/queue.md", "knowledge/L0/notes.md", "target holon gone not found"} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("Expected report to mention %q, got:\n%s", want, report)
+		}
+	}
+	if _, err := tools.DB.GetHolon(ctx, "queue"); err == nil {
+		t.Error("Expected the ambiguous holon to stay out of the database")
+	}
+}
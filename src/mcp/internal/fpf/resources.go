@@ -0,0 +1,299 @@
+package fpf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ContextResourceURI names the bounded context currently in use
+const ContextResourceURI = "quint://context"
+
+const resourceMimeType = "text/markdown"
+
+// ErrResourceNotFound is returned by ReadResource for URIs that name nothing
+var ErrResourceNotFound = errors.New("resource not found")
+
+// Projection states reported with every holon, evidence and decision resource
+const (
+	ProjectionVerified = "verified" // file hash matches the DB content
+	ProjectionTampered = "tampered" // file was edited outside the tools
+	ProjectionMissing  = "missing"  // no projection file, or one without a content_hash
+)
+
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type ResourceContents struct {
+	URI      string                 `json:"uri"`
+	MimeType string                 `json:"mimeType,omitempty"`
+	Text     string                 `json:"text"`
+	Meta     map[string]interface{} `json:"_meta,omitempty"`
+}
+
+func resourceTemplates() []ResourceTemplate {
+	return []ResourceTemplate{
+		{URITemplate: "quint://holon/{id}", Name: "holon", Description: "Hypothesis at any layer (L0, L1, L2, invalid)", MimeType: resourceMimeType},
+		{URITemplate: "quint://evidence/{id}", Name: "evidence", Description: "Verification or validation evidence attached to a holon", MimeType: resourceMimeType},
+		{URITemplate: "quint://decision/{id}", Name: "decision", Description: "Design Rationale Record (DRR)", MimeType: resourceMimeType},
+	}
+}
+
+// ListResources enumerates the active context, its holons, decisions and evidence
+func (t *Tools) ListResources() ([]Resource, error) {
+	resources := []Resource{{
+		URI:         ContextResourceURI,
+		Name:        "context",
+		Title:       "Bounded Context: " + t.ContextID(),
+		Description: "Vocabulary and invariants of the active bounded context",
+		MimeType:    resourceMimeType,
+	}}
+
+	if t.DB == nil {
+		return resources, nil
+	}
+
+	ctx := context.Background()
+	holons, err := t.DB.ListHolonsByContext(ctx, t.ContextID())
+	if err != nil {
+		return nil, err
+	}
+
+	var decisions []Resource
+	for _, h := range holons {
+		if h.Type == "DRR" {
+			decisions = append(decisions, Resource{
+				URI:         "quint://decision/" + h.ID,
+				Name:        h.ID,
+				Title:       h.Title,
+				Description: "Decision selecting " + h.ParentID.String,
+				MimeType:    resourceMimeType,
+			})
+			continue
+		}
+		description := fmt.Sprintf("%s %s hypothesis", h.Layer, h.Kind.String)
+		if h.CachedRScore.Valid {
+			description += fmt.Sprintf(", R_eff %.2f", h.CachedRScore.Float64)
+		}
+		resources = append(resources, Resource{
+			URI:         "quint://holon/" + h.ID,
+			Name:        h.ID,
+			Title:       h.Title,
+			Description: description,
+			MimeType:    resourceMimeType,
+		})
+	}
+	resources = append(resources, decisions...)
+
+	evidence, err := t.DB.ListEvidenceByContext(ctx, t.ContextID())
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range evidence {
+		resources = append(resources, Resource{
+			URI:         "quint://evidence/" + e.ID,
+			Name:        e.ID,
+			Description: fmt.Sprintf("%s evidence for %s: %s", e.Type, e.HolonID, e.Verdict),
+			MimeType:    resourceMimeType,
+		})
+	}
+
+	return resources, nil
+}
+
+// ReadResource serves a quint:// URI from the database. Holon, evidence and
+// decision content is checked against the content_hash of its projection file.
+func (t *Tools) ReadResource(uri string) (ResourceContents, error) {
+	if uri == ContextResourceURI {
+		return t.readContextResource()
+	}
+
+	rest, ok := strings.CutPrefix(uri, "quint://")
+	if !ok {
+		return ResourceContents{}, fmt.Errorf("%w: %s", ErrResourceNotFound, uri)
+	}
+	kind, id, ok := strings.Cut(rest, "/")
+	if !ok || id == "" {
+		return ResourceContents{}, fmt.Errorf("%w: %s", ErrResourceNotFound, uri)
+	}
+	if t.DB == nil {
+		return ResourceContents{}, fmt.Errorf("DB not initialized")
+	}
+
+	switch kind {
+	case "holon":
+		return t.readHolonResource(uri, id, false)
+	case "decision":
+		return t.readHolonResource(uri, id, true)
+	case "evidence":
+		return t.readEvidenceResource(uri, id)
+	default:
+		return ResourceContents{}, fmt.Errorf("%w: %s", ErrResourceNotFound, uri)
+	}
+}
+
+func (t *Tools) readContextResource() (ResourceContents, error) {
+	id := t.ContextID()
+	fields := [][2]string{{"id", id}}
+
+	if t.DB != nil {
+		if c, err := t.DB.GetContext(context.Background(), id); err == nil {
+			fields = append(fields, [2]string{"title", c.Title}, [2]string{"status", c.Status})
+			if c.Description.Valid && c.Description.String != "" {
+				fields = append(fields, [2]string{"description", c.Description.String})
+			}
+		}
+	}
+
+	body := "\n_No bounded context recorded yet. Use quint_record_context._\n"
+	if data, err := os.ReadFile(t.ContextFilePath()); err == nil {
+		body = "\n" + string(data)
+	}
+
+	return ResourceContents{
+		URI:      ContextResourceURI,
+		MimeType: resourceMimeType,
+		Text:     renderResource(fields, body),
+	}, nil
+}
+
+func (t *Tools) readHolonResource(uri, id string, decision bool) (ResourceContents, error) {
+	holon, err := t.DB.GetHolon(context.Background(), id)
+	if err != nil || (holon.Type == "DRR") != decision {
+		return ResourceContents{}, fmt.Errorf("%w: %s", ErrResourceNotFound, uri)
+	}
+
+	fields := [][2]string{
+		{"id", holon.ID},
+		{"title", holon.Title},
+		{"layer", holon.Layer},
+		{"context", holon.ContextID},
+	}
+	var path string
+	if decision {
+		fields = append(fields, [2]string{"winner_id", holon.ParentID.String})
+		path = t.decisionProjectionPath(holon.ID)
+	} else {
+		fields = append(fields, [2]string{"kind", holon.Kind.String}, [2]string{"scope", holon.Scope.String})
+		if holon.CachedRScore.Valid {
+			fields = append(fields, [2]string{"r_eff", fmt.Sprintf("%.2f", holon.CachedRScore.Float64)})
+		}
+		path = filepath.Join(t.GetFPFDir(), "knowledge", holon.Layer, holon.ID+".md")
+	}
+
+	return ResourceContents{
+		URI:      uri,
+		MimeType: resourceMimeType,
+		Text:     renderResource(fields, holon.Content),
+		Meta:     t.projectionMeta(path, holon.Content),
+	}, nil
+}
+
+func (t *Tools) readEvidenceResource(uri, id string) (ResourceContents, error) {
+	e, err := t.DB.GetEvidenceByID(context.Background(), id)
+	if err != nil {
+		return ResourceContents{}, fmt.Errorf("%w: %s", ErrResourceNotFound, uri)
+	}
+
+	fields := [][2]string{
+		{"id", e.ID},
+		{"target", e.HolonID},
+		{"type", e.Type},
+		{"verdict", e.Verdict},
+		{"assurance_level", e.AssuranceLevel.String},
+	}
+	if e.ValidUntil.Valid {
+		fields = append(fields, [2]string{"valid_until", e.ValidUntil.Time.Format("2006-01-02")})
+	}
+
+	body := "\n" + e.Content
+	return ResourceContents{
+		URI:      uri,
+		MimeType: resourceMimeType,
+		Text:     renderResource(fields, body),
+		Meta:     t.projectionMeta(filepath.Join(t.GetFPFDir(), "evidence", e.ID), body),
+	}, nil
+}
+
+// decisionProjectionPath finds the newest DRR file written for a decision ID
+func (t *Tools) decisionProjectionPath(id string) string {
+	matches, _ := filepath.Glob(filepath.Join(t.GetFPFDir(), "decisions", "DRR-*-"+id+".md"))
+	if len(matches) == 0 {
+		return ""
+	}
+	sort.Strings(matches)
+	return matches[len(matches)-1]
+}
+
+// projectionMeta checks the DB content against the projection file: both its
+// content_hash and its body must match. A mismatch means the file was edited by
+// hand; the DB stays authoritative.
+func (t *Tools) projectionMeta(path, body string) map[string]interface{} {
+	hash := ComputeContentHash(body)
+	meta := map[string]interface{}{
+		"contentHash": hash,
+		"projection":  ProjectionMissing,
+	}
+	if path == "" {
+		return meta
+	}
+
+	if rel, err := filepath.Rel(t.RootDir, path); err == nil {
+		meta["projectionPath"] = rel
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return meta
+	}
+	frontmatter, fileBody, ok := parseFrontmatter(string(data))
+	if !ok {
+		return meta
+	}
+	expected := extractHashFromFrontmatter(frontmatter)
+	if expected == "" {
+		return meta
+	}
+
+	if expected != hash || ComputeContentHash(fileBody) != hash {
+		meta["projection"] = ProjectionTampered
+		t.AuditLog("resources_read", "tampering_detected", "system", path, "ALERT", map[string]string{
+			"expected_hash": expected,
+			"db_hash":       hash,
+		}, "Projection does not match database content")
+		return meta
+	}
+
+	meta["projection"] = ProjectionVerified
+	return meta
+}
+
+func renderResource(fields [][2]string, body string) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	for _, f := range fields {
+		if f[1] == "" {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s\n", f[0], f[1]))
+	}
+	sb.WriteString("---\n")
+	sb.WriteString(body)
+	return sb.String()
+}
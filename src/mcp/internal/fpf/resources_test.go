@@ -0,0 +1,122 @@
+package fpf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListResources(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	if _, err := tools.ProposeHypothesis("Redis Cache", "Use Redis", "api", "system", "{}", "", nil, 3); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, _, err := tools.ManageEvidence(PhaseDeduction, "add", "redis-cache", "verification", "checks ok", "PASS", "L1", "internal-logic", ""); err != nil {
+		t.Fatalf("ManageEvidence failed: %v", err)
+	}
+	if _, err := tools.FinalizeDecision("Caching Strategy", "", nil, "ctx", "use redis", "fast", "none", ""); err != nil {
+		t.Fatalf("FinalizeDecision failed: %v", err)
+	}
+
+	resources, err := tools.ListResources()
+	if err != nil {
+		t.Fatalf("ListResources failed: %v", err)
+	}
+
+	var uris []string
+	for _, r := range resources {
+		uris = append(uris, r.URI)
+	}
+	joined := strings.Join(uris, " ")
+
+	for _, want := range []string{ContextResourceURI, "quint://holon/redis-cache", "quint://decision/caching-strategy", "quint://evidence/"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected %s in resources, got %v", want, uris)
+		}
+	}
+	if strings.Contains(joined, "quint://holon/caching-strategy") {
+		t.Errorf("DRR should be listed as a decision, not a holon: %v", uris)
+	}
+}
+
+func TestReadResource_VerifiesProjectionHash(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+
+	if _, err := tools.ProposeHypothesis("Redis Cache", "Use Redis", "api", "system", "{}", "", nil, 3); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+
+	contents, err := tools.ReadResource("quint://holon/redis-cache")
+	if err != nil {
+		t.Fatalf("ReadResource failed: %v", err)
+	}
+	if !strings.Contains(contents.Text, "layer: L0") || !strings.Contains(contents.Text, "Use Redis") {
+		t.Errorf("unexpected resource text:\n%s", contents.Text)
+	}
+	if contents.Meta["projection"] != ProjectionVerified {
+		t.Errorf("expected verified projection, got %v", contents.Meta["projection"])
+	}
+
+	path := filepath.Join(tempDir, ".quint", "knowledge", "L0", "redis-cache.md")
+	data, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, []byte(strings.Replace(string(data), "Use Redis", "Use Memcached", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err = tools.ReadResource("quint://holon/redis-cache")
+	if err != nil {
+		t.Fatalf("ReadResource failed: %v", err)
+	}
+	if contents.Meta["projection"] != ProjectionTampered {
+		t.Errorf("expected tampered projection, got %v", contents.Meta["projection"])
+	}
+	if !strings.Contains(contents.Text, "Use Redis") {
+		t.Error("expected content served from the DB, not the edited file")
+	}
+}
+
+func TestReadResource_Context(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	if _, err := tools.RecordContext("Cache: fast storage", "No data loss"); err != nil {
+		t.Fatalf("RecordContext failed: %v", err)
+	}
+
+	contents, err := tools.ReadResource(ContextResourceURI)
+	if err != nil {
+		t.Fatalf("ReadResource failed: %v", err)
+	}
+	if !strings.Contains(contents.Text, "id: default") || !strings.Contains(contents.Text, "No data loss") {
+		t.Errorf("unexpected context resource:\n%s", contents.Text)
+	}
+}
+
+func TestReadResource_NotFound(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	for _, uri := range []string{"quint://holon/missing", "quint://decision/missing", "quint://evidence/missing", "quint://unknown/x", "file:///etc/passwd"} {
+		if _, err := tools.ReadResource(uri); !errors.Is(err, ErrResourceNotFound) {
+			t.Errorf("%s: expected ErrResourceNotFound, got %v", uri, err)
+		}
+	}
+
+	s := NewServer(tools)
+	resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"quint://holon/missing"}}`), nil)
+	var msg map[string]interface{}
+	if err := json.Unmarshal(resp, &msg); err != nil {
+		t.Fatalf("invalid response %q: %v", resp, err)
+	}
+	if errorCode(msg) != CodeResourceNotFound {
+		t.Errorf("expected %d, got %v", CodeResourceNotFound, msg)
+	}
+
+	resp = s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"resources/templates/list"}`), nil)
+	if !strings.Contains(string(resp), "quint://evidence/{id}") {
+		t.Errorf("expected evidence template, got %s", resp)
+	}
+}
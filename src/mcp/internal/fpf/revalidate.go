@@ -0,0 +1,442 @@
+package fpf
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/assurance"
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// DefaultRecipeTimeout bounds a single recipe run
+const DefaultRecipeTimeout = 10 * time.Minute
+
+// maxRecipeOutput caps the lines of recipe output kept in the evidence body;
+// the full output is stored as the evidence artifact
+const maxRecipeOutput = 30
+
+// trustedRecipePrefix keys the settings that mark a recipe as approved by a
+// person on this machine, through the CLI or a revalidate prompt. quint.db is
+// local, so recipes an agent stores through quint_test or that arrive through
+// committed evidence files (reindex, doctor --fix) start out untrusted.
+const trustedRecipePrefix = "trusted_recipe:"
+
+// RecordEvidenceRecipe stores the command that reproduces a newly recorded
+// evidence item. It comes from an agent, so it stays untrusted until approved.
+func (t *Tools) RecordEvidenceRecipe(evidenceID, recipe string) error {
+	return t.transact(func() error {
+		return t.recordEvidenceRecipe(context.Background(), evidenceID, recipe, false)
+	})
+}
+
+// SetEvidenceRecipe stores the command that reproduces an existing evidence
+// item. It is typed by a person at the CLI, so it is trusted.
+func (t *Tools) SetEvidenceRecipe(evidenceID, recipe string) (string, error) {
+	err := t.transact(func() error {
+		return t.recordEvidenceRecipe(context.Background(), evidenceID, recipe, true)
+	})
+	if err != nil {
+		return "", err
+	}
+	t.AuditLog("revalidate", "set_recipe", "user", evidenceID, "SUCCESS", map[string]string{"recipe": recipe}, "")
+	return fmt.Sprintf("Recipe stored for %s: %s", evidenceID, strings.TrimSpace(recipe)), nil
+}
+
+func (t *Tools) recordEvidenceRecipe(ctx context.Context, id, recipe string, trusted bool) error {
+	recipe = strings.TrimSpace(recipe)
+	if recipe == "" {
+		return fmt.Errorf("recipe is empty")
+	}
+	if strings.ContainsAny(recipe, "\r\n") {
+		return fmt.Errorf("recipe must be a single command line (chain steps with && or call a script)")
+	}
+	if t.DB == nil {
+		return fmt.Errorf("DB not initialized")
+	}
+	if _, err := t.DB.GetEvidenceByID(ctx, id); err != nil {
+		return fmt.Errorf("evidence %s not found", id)
+	}
+	if err := t.DB.UpdateEvidenceRecipe(ctx, id, recipe); err != nil {
+		return err
+	}
+	if trusted {
+		if err := t.trustRecipe(ctx, recipe); err != nil {
+			return err
+		}
+	}
+	e, err := t.DB.GetEvidenceByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	fields, body := evidenceProjection(e)
+	return t.writeProjection(filepath.Join(t.GetFPFDir(), "evidence", id), fields, body)
+}
+
+func recipeTrustKey(recipe string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(recipe)))
+	return trustedRecipePrefix + hex.EncodeToString(sum[:])
+}
+
+// trustRecipe marks a recipe as safe to run on this machine
+func (t *Tools) trustRecipe(ctx context.Context, recipe string) error {
+	return t.DB.SetSetting(ctx, recipeTrustKey(recipe), strings.TrimSpace(recipe))
+}
+
+// recipeTrusted reports whether a person on this machine approved the recipe
+func (t *Tools) recipeTrusted(ctx context.Context, recipe string) bool {
+	value, err := t.DB.GetSetting(ctx, recipeTrustKey(recipe))
+	return err == nil && value == strings.TrimSpace(recipe)
+}
+
+// RevalidateOptions selects what Revalidate runs and how
+type RevalidateOptions struct {
+	HolonID string        // only this holon; every stale holon when empty
+	Timeout time.Duration // per recipe; DefaultRecipeTimeout when zero
+	InPlace bool          // run in the project root instead of a clean checkout of HEAD
+	DryRun  bool          // list the recipes without running them
+	Yes     bool          // run recipes nobody approved on this machine without asking
+
+	// Confirm is asked before running a recipe nobody approved on this machine.
+	// Without it (and without Yes) such recipes are skipped.
+	Confirm func(e db.Evidence) bool
+}
+
+// recipeRun is the outcome of one recipe
+type recipeRun struct {
+	Evidence db.Evidence
+	Verdict  string
+	Detail   string
+	Duration time.Duration
+}
+
+// Revalidate re-runs the stored recipes of stale evidence: expired and not
+// waived, or backed by an artifact that changed. Each recipe runs as a shell
+// command in its own detached git worktree of HEAD (or in the project root with
+// InPlace) under a timeout. The worktree keeps the working tree clean; it is not
+// a sandbox, so recipes nobody approved on this machine need confirmation.
+// Exit status 0 records fresh passing evidence with a new valid_until,
+// anything else failing evidence; the output is stored as a log artifact.
+func (t *Tools) Revalidate(opts RevalidateOptions) (string, error) {
+	defer t.RecordWork("Revalidate", time.Now())
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultRecipeTimeout
+	}
+
+	ctx := context.Background()
+	runnable, missing, err := t.staleRecipes(ctx, opts.HolonID)
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+	result.WriteString("## Re-validation\n\n")
+	if len(runnable) == 0 {
+		result.WriteString("No stale evidence with a recipe.\n")
+		writeMissingRecipes(&result, missing)
+		return result.String(), nil
+	}
+
+	if opts.DryRun {
+		result.WriteString("| Holon | Evidence | Recipe | Approved |\n")
+		result.WriteString("|-------|----------|--------|----------|\n")
+		for _, e := range runnable {
+			trusted := "no"
+			if t.recipeTrusted(ctx, e.Recipe.String) {
+				trusted = "yes"
+			}
+			result.WriteString(fmt.Sprintf("| %s | %s | `%s` | %s |\n", e.HolonID, e.ID, e.Recipe.String, trusted))
+		}
+		writeMissingRecipes(&result, missing)
+		return result.String(), nil
+	}
+
+	// Recipes from agents or committed files run only once approved here
+	var approved, skipped []db.Evidence
+	for _, e := range runnable {
+		if !t.recipeTrusted(ctx, e.Recipe.String) {
+			if !opts.Yes && (opts.Confirm == nil || !opts.Confirm(e)) {
+				skipped = append(skipped, e)
+				continue
+			}
+			if err := t.trustRecipe(ctx, e.Recipe.String); err != nil {
+				return "", err
+			}
+			t.AuditLog("revalidate", "trust_recipe", "user", e.ID, "SUCCESS", map[string]string{"recipe": e.Recipe.String}, "")
+		}
+		approved = append(approved, e)
+	}
+	runnable = approved
+	if len(runnable) == 0 {
+		result.WriteString("No approved recipe to run.\n")
+		writeSkippedRecipes(&result, skipped)
+		writeMissingRecipes(&result, missing)
+		return result.String(), nil
+	}
+
+	calc := t.newCalculator()
+	before := make(map[string]float64)
+	for _, e := range runnable {
+		if _, ok := before[e.HolonID]; !ok {
+			if report, err := calc.CalculateReliability(ctx, e.HolonID); err == nil {
+				before[e.HolonID] = report.FinalScore
+			}
+		}
+	}
+
+	var runs []recipeRun
+	for _, e := range runnable {
+		run, err := t.runRecipe(e, opts)
+		if err != nil {
+			t.AuditLog("revalidate", "run_recipe", "user", e.ID, "ERROR", map[string]string{"recipe": e.Recipe.String}, err.Error())
+			return "", err
+		}
+		runs = append(runs, run)
+	}
+
+	result.WriteString("| Holon | Evidence | Result | Duration | Details |\n")
+	result.WriteString("|-------|----------|--------|----------|---------|\n")
+	failed := make(map[string]int)
+	for _, run := range runs {
+		if run.Verdict != "pass" {
+			failed[run.Evidence.HolonID]++
+		}
+		result.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", run.Evidence.HolonID, run.Evidence.ID,
+			strings.ToUpper(run.Verdict), run.Duration.Round(time.Millisecond), run.Detail))
+	}
+
+	holons := make([]string, 0, len(before))
+	for holonID := range before {
+		holons = append(holons, holonID)
+	}
+	sort.Strings(holons)
+
+	var recovered, degraded []string
+	for _, holonID := range holons {
+		after := before[holonID]
+		if report, err := calc.CalculateReliability(ctx, holonID); err == nil {
+			after = report.FinalScore
+		}
+		line := fmt.Sprintf("- %s: R %.2f → %.2f", holonID, before[holonID], after)
+		if failed[holonID] > 0 {
+			degraded = append(degraded, fmt.Sprintf("%s (%d recipe(s) failed)", line, failed[holonID]))
+		} else {
+			recovered = append(recovered, line)
+		}
+	}
+
+	if len(recovered) > 0 {
+		result.WriteString(fmt.Sprintf("\n### RECOVERED (%d holons)\n\n%s\n", len(recovered), strings.Join(recovered, "\n")))
+	}
+	if len(degraded) > 0 {
+		result.WriteString(fmt.Sprintf("\n### DEGRADED (%d holons)\n\n%s\n", len(degraded), strings.Join(degraded, "\n")))
+		result.WriteString("\nActions:\n")
+		result.WriteString("  → /q3-validate <holon> (investigate and record new evidence)\n")
+		result.WriteString("  → /q-decay --deprecate <holon> (downgrade)\n")
+	}
+	writeSkippedRecipes(&result, skipped)
+	writeMissingRecipes(&result, missing)
+	return result.String(), nil
+}
+
+func writeSkippedRecipes(result *strings.Builder, skipped []db.Evidence) {
+	if len(skipped) == 0 {
+		return
+	}
+	result.WriteString(fmt.Sprintf("\n%d recipe(s) were not approved on this machine and did not run; review them with `quint-code revalidate --dry-run` and approve with --yes:\n", len(skipped)))
+	for _, e := range skipped {
+		result.WriteString(fmt.Sprintf("- %s (%s): `%s`\n", e.ID, e.HolonID, e.Recipe.String))
+	}
+}
+
+func writeMissingRecipes(result *strings.Builder, missing []db.Evidence) {
+	if len(missing) == 0 {
+		return
+	}
+	result.WriteString(fmt.Sprintf("\n%d stale evidence item(s) have no recipe; refresh them with /q3-validate or store one with `quint-code evidence recipe <evidence_id> \"<command>\"`:\n", len(missing)))
+	for _, e := range missing {
+		result.WriteString(fmt.Sprintf("- %s (%s)\n", e.ID, e.HolonID))
+	}
+}
+
+// staleRecipes splits the stale evidence of the active context into items with
+// a recipe and items without
+func (t *Tools) staleRecipes(ctx context.Context, holonID string) (runnable, missing []db.Evidence, err error) {
+	evidence, err := t.DB.ListEvidenceByContext(ctx, t.ContextID())
+	if err != nil {
+		return nil, nil, err
+	}
+	changed, err := t.staleArtifacts(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	changedIDs := make(map[string]bool, len(changed))
+	for _, a := range changed {
+		changedIDs[a.Evidence] = true
+	}
+
+	now := time.Now()
+	for _, e := range evidence {
+		if holonID != "" && e.HolonID != holonID {
+			continue
+		}
+		stale := changedIDs[e.ID]
+		if !stale && e.ValidUntil.Valid && e.ValidUntil.Time.Before(now) {
+			_, waiverErr := t.DB.GetActiveWaiverForEvidence(ctx, e.ID)
+			stale = waiverErr != nil
+		}
+		if !stale {
+			continue
+		}
+		if e.Recipe.Valid && e.Recipe.String != "" {
+			runnable = append(runnable, e)
+		} else {
+			missing = append(missing, e)
+		}
+	}
+	return runnable, missing, nil
+}
+
+// runRecipe executes one recipe in a clean checkout and records the fresh evidence
+func (t *Tools) runRecipe(e db.Evidence, opts RevalidateOptions) (recipeRun, error) {
+	dir, cleanup, err := t.recipeCheckout(opts.InPlace)
+	if err != nil {
+		return recipeRun{}, err
+	}
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	cmd := recipeCommand(ctx, e.Recipe.String)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "QUINT_EVIDENCE_ID="+e.ID, "QUINT_HOLON_ID="+e.HolonID)
+	cmd.WaitDelay = 5 * time.Second
+
+	start := time.Now()
+	output, runErr := cmd.CombinedOutput()
+	run := recipeRun{Evidence: e, Verdict: "pass", Detail: "exit 0", Duration: time.Since(start)}
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		run.Verdict, run.Detail = "fail", fmt.Sprintf("timed out after %s", opts.Timeout)
+	case errors.As(runErr, &exitErr):
+		run.Verdict, run.Detail = "fail", fmt.Sprintf("exit %d", exitErr.ExitCode())
+	case runErr != nil:
+		run.Verdict, run.Detail = "fail", runErr.Error()
+	}
+
+	err = t.transact(func() error {
+		return t.recordRecipeRun(run, output)
+	})
+	if err != nil {
+		return recipeRun{}, err
+	}
+
+	t.AuditLog("revalidate", "run_recipe", "user", e.ID, "SUCCESS",
+		map[string]string{"recipe": e.Recipe.String, "verdict": run.Verdict, "detail": run.Detail}, "")
+	return run, nil
+}
+
+// recordRecipeRun replaces the evidence with the outcome of its recipe,
+// keeping its type, carrier, formality and recipe
+func (t *Tools) recordRecipeRun(run recipeRun, output []byte) error {
+	ctx := context.Background()
+	e := run.Evidence
+
+	content := fmt.Sprintf("Re-validated by recipe: `%s`\nResult: %s (%s)", e.Recipe.String, strings.ToUpper(run.Verdict), run.Detail)
+	if tail := outputTail(string(output), maxRecipeOutput); tail != "" {
+		content += "\n\n```\n" + tail + "\n```"
+	}
+	if err := t.DB.AddEvidence(ctx, e.ID, e.HolonID, e.Type, content, run.Verdict,
+		e.AssuranceLevel.String, e.CarrierRef.String, defaultValidUntil()); err != nil {
+		return fmt.Errorf("failed to record evidence: %v", err)
+	}
+	if e.Formality.Valid {
+		if err := t.DB.UpdateEvidenceFormality(ctx, e.ID, int(e.Formality.Int64)); err != nil {
+			return err
+		}
+	}
+	if err := t.DB.UpdateEvidenceRecipe(ctx, e.ID, e.Recipe.String); err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp("", "quint-recipe-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp) //nolint:errcheck
+	logPath := filepath.Join(tmp, strings.TrimSuffix(e.ID, ".md")+".log")
+	if err := os.WriteFile(logPath, output, 0644); err != nil {
+		return fmt.Errorf("failed to write recipe output: %v", err)
+	}
+	artifact, err := t.StoreArtifact(assurance.ArtifactLog, logPath)
+	if err != nil {
+		return err
+	}
+	if err := t.DB.UpdateEvidenceArtifact(ctx, e.ID, artifact.Kind, artifact.Ref, artifact.Hash, artifact.Source); err != nil {
+		return err
+	}
+
+	updated, err := t.DB.GetEvidenceByID(ctx, e.ID)
+	if err != nil {
+		return err
+	}
+	fields, body := evidenceProjection(updated)
+	return t.writeProjection(filepath.Join(t.GetFPFDir(), "evidence", e.ID), fields, body)
+}
+
+// recipeCheckout returns the directory a recipe runs in: a fresh detached
+// worktree of HEAD, so recipes see committed code and leave the working tree
+// alone, or the project root itself with inPlace. It isolates files in the
+// checkout only; the recipe runs with the user's full permissions.
+func (t *Tools) recipeCheckout(inPlace bool) (string, func(), error) {
+	if inPlace {
+		return t.RootDir, func() {}, nil
+	}
+
+	tmp, err := os.MkdirTemp("", "quint-revalidate-")
+	if err != nil {
+		return "", nil, err
+	}
+	dir := filepath.Join(tmp, "checkout")
+	add := exec.Command("git", "worktree", "add", "--detach", dir, "HEAD")
+	add.Dir = t.RootDir
+	if output, err := add.CombinedOutput(); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", nil, fmt.Errorf("failed to create a clean checkout for recipes (use --in-place outside git repositories): %v: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	cleanup := func() {
+		remove := exec.Command("git", "worktree", "remove", "--force", dir)
+		remove.Dir = t.RootDir
+		_ = remove.Run()
+		_ = os.RemoveAll(tmp)
+	}
+	return dir, cleanup, nil
+}
+
+// outputTail returns the last lines of command output
+func outputTail(output string, lines int) string {
+	output = strings.TrimRight(output, "\n")
+	if output == "" {
+		return ""
+	}
+	all := strings.Split(output, "\n")
+	if len(all) > lines {
+		all = all[len(all)-lines:]
+	}
+	return strings.Join(all, "\n")
+}
@@ -0,0 +1,199 @@
+package fpf
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+func setupStaleEvidence(t *testing.T, tools *Tools, holonID, evidenceID, recipe string) {
+	t.Helper()
+	ctx := context.Background()
+	if err := tools.DB.CreateHolon(ctx, holonID, "hypothesis", "system", "L2", holonID, "Content", tools.ContextID(), "global", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.DB.AddEvidence(ctx, evidenceID, holonID, "internal", "Tests pass", "pass", "L2", "test-runner", "2020-01-01"); err != nil {
+		t.Fatal(err)
+	}
+	if recipe != "" {
+		if _, err := tools.SetEvidenceRecipe(evidenceID, recipe); err != nil {
+			t.Fatalf("SetEvidenceRecipe failed: %v", err)
+		}
+	}
+}
+
+func TestRevalidate(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+
+	setupStaleEvidence(t, tools, "fast", "fast-test", "echo ok from $QUINT_HOLON_ID")
+	setupStaleEvidence(t, tools, "broken", "broken-test", "echo boom; exit 3")
+	setupStaleEvidence(t, tools, "slow", "slow-test", "sleep 5")
+	setupStaleEvidence(t, tools, "manual", "manual-test", "")
+
+	if _, err := tools.SetEvidenceRecipe("fast-test", "echo a\necho b"); err == nil {
+		t.Error("Expected a multi-line recipe to be rejected")
+	}
+
+	dry, err := tools.Revalidate(RevalidateOptions{InPlace: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if !strings.Contains(dry, "`echo boom; exit 3`") || !strings.Contains(dry, "manual-test (manual)") {
+		t.Errorf("Unexpected dry run:\n%s", dry)
+	}
+	if e, _ := tools.DB.GetEvidenceByID(ctx, "fast-test"); e.ValidUntil.Time.After(time.Now()) {
+		t.Error("Expected a dry run not to record evidence")
+	}
+
+	report, err := tools.Revalidate(RevalidateOptions{InPlace: true, Timeout: 500 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Revalidate failed: %v", err)
+	}
+	for _, want := range []string{
+		"| fast | fast-test | PASS |",
+		"| broken | broken-test | FAIL |",
+		"exit 3",
+		"timed out after 500ms",
+		"### RECOVERED (1 holons)\n\n- fast: R 0.10 → 1.00",
+		"### DEGRADED (2 holons)",
+		"1 stale evidence item(s) have no recipe",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected %q in report:\n%s", want, report)
+		}
+	}
+
+	fresh, _ := tools.DB.GetEvidenceByID(ctx, "fast-test")
+	if fresh.Verdict != "pass" || !fresh.ValidUntil.Time.After(time.Now()) || fresh.Recipe.String != "echo ok from $QUINT_HOLON_ID" {
+		t.Errorf("Expected fresh passing evidence keeping its recipe, got %+v", fresh)
+	}
+	if fresh.ArtifactKind.String != "log" {
+		t.Fatalf("Expected the recipe output as a log artifact, got %+v", fresh)
+	}
+	log, err := os.ReadFile(filepath.Join(tools.RootDir, fresh.ArtifactRef.String))
+	if err != nil || string(log) != "ok from fast\n" {
+		t.Errorf("Unexpected log artifact %q (%v)", log, err)
+	}
+	content, _ := os.ReadFile(filepath.Join(tools.GetFPFDir(), "evidence", "fast-test"))
+	if !strings.Contains(string(content), "recipe: echo ok from $QUINT_HOLON_ID") {
+		t.Errorf("Expected the recipe in the evidence file, got:\n%s", content)
+	}
+	if holon, _ := tools.DB.GetHolon(ctx, "fast"); holon.CachedRScore.Float64 != 1.0 {
+		t.Errorf("Expected the cached R to recover, got %v", holon.CachedRScore)
+	}
+
+	if broken, _ := tools.DB.GetEvidenceByID(ctx, "broken-test"); broken.Verdict != "fail" || !strings.Contains(broken.Content, "boom") {
+		t.Errorf("Expected failing evidence with the output, got %+v", broken)
+	}
+
+	// Fresh evidence is not stale any more; failed runs are fresh too
+	again, _ := tools.Revalidate(RevalidateOptions{InPlace: true, HolonID: "fast"})
+	if !strings.Contains(again, "No stale evidence with a recipe") {
+		t.Errorf("Expected nothing to re-run, got:\n%s", again)
+	}
+}
+
+func TestRevalidate_CleanWorktree(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.email=test@example.com", "-c", "user.name=Test"}, args...)...)
+		cmd.Dir = tempDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+	git("init")
+	if err := os.WriteFile(filepath.Join(tempDir, "VERSION"), []byte("committed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "VERSION")
+	git("commit", "-m", "Initial commit")
+	// Uncommitted edits are not visible to the recipe
+	if err := os.WriteFile(filepath.Join(tempDir, "VERSION"), []byte("dirty\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	setupStaleEvidence(t, tools, "versioned", "versioned-test", "grep -q committed VERSION && touch created-by-recipe")
+	report, err := tools.Revalidate(RevalidateOptions{})
+	if err != nil {
+		t.Fatalf("Revalidate failed: %v", err)
+	}
+	if !strings.Contains(report, "| versioned | versioned-test | PASS |") {
+		t.Errorf("Expected the recipe to see committed code, got:\n%s", report)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "created-by-recipe")); !os.IsNotExist(err) {
+		t.Error("Expected the recipe not to touch the project root")
+	}
+
+	list := exec.Command("git", "worktree", "list")
+	list.Dir = tempDir
+	if output, _ := list.Output(); strings.Count(string(output), "\n") != 1 {
+		t.Errorf("Expected the recipe worktree to be removed, got:\n%s", output)
+	}
+}
+
+func TestRevalidate_UntrustedRecipe(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+
+	// A recipe imported from a committed evidence file, as reindex does
+	setupStaleEvidence(t, tools, "imported", "imported-test", "")
+	if err := tools.DB.UpdateEvidenceRecipe(ctx, "imported-test", "touch ran"); err != nil {
+		t.Fatal(err)
+	}
+
+	dry, _ := tools.Revalidate(RevalidateOptions{InPlace: true, DryRun: true})
+	if !strings.Contains(dry, "| imported | imported-test | `touch ran` | no |") {
+		t.Errorf("Expected the recipe to be marked as not approved:\n%s", dry)
+	}
+
+	report, err := tools.Revalidate(RevalidateOptions{InPlace: true})
+	if err != nil {
+		t.Fatalf("Revalidate failed: %v", err)
+	}
+	if !strings.Contains(report, "1 recipe(s) were not approved on this machine and did not run") {
+		t.Errorf("Expected the untrusted recipe to be skipped:\n%s", report)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "ran")); !os.IsNotExist(err) {
+		t.Fatal("Expected the untrusted recipe not to run")
+	}
+
+	declined := 0
+	if _, err := tools.Revalidate(RevalidateOptions{InPlace: true, Confirm: func(e db.Evidence) bool { declined++; return false }}); err != nil {
+		t.Fatal(err)
+	}
+	if declined != 1 {
+		t.Errorf("Expected one confirmation prompt, got %d", declined)
+	}
+
+	report, _ = tools.Revalidate(RevalidateOptions{InPlace: true, Confirm: func(e db.Evidence) bool { return true }})
+	if !strings.Contains(report, "| imported | imported-test | PASS |") {
+		t.Errorf("Expected the confirmed recipe to run:\n%s", report)
+	}
+	if !tools.recipeTrusted(ctx, "touch ran") {
+		t.Error("Expected a confirmed recipe to be trusted from then on")
+	}
+
+	// A recipe an agent stores through quint_test is not approved by that
+	setupStaleEvidence(t, tools, "agent", "agent-test", "")
+	if err := tools.RecordEvidenceRecipe("agent-test", "touch agent-ran"); err != nil {
+		t.Fatalf("RecordEvidenceRecipe failed: %v", err)
+	}
+	if tools.recipeTrusted(ctx, "touch agent-ran") {
+		t.Error("Expected a recipe from quint_test to stay untrusted")
+	}
+	report, _ = tools.Revalidate(RevalidateOptions{InPlace: true})
+	if !strings.Contains(report, "- agent-test (agent): `touch agent-ran`") {
+		t.Errorf("Expected the agent's recipe to be skipped:\n%s", report)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "agent-ran")); !os.IsNotExist(err) {
+		t.Error("Expected the agent's recipe not to run unasked")
+	}
+}
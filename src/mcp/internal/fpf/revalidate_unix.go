@@ -0,0 +1,18 @@
+//go:build !windows
+
+package fpf
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+)
+
+// recipeCommand runs a recipe through sh in its own process group, so a
+// timeout kills everything it started, not just the shell
+func recipeCommand(ctx context.Context, recipe string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "sh", "-c", recipe)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error { return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL) }
+	return cmd
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package fpf
+
+import (
+	"context"
+	"os/exec"
+)
+
+// recipeCommand runs a recipe through cmd.exe. A timeout kills the shell;
+// WaitDelay bounds how long its children may hold the output open.
+func recipeCommand(ctx context.Context, recipe string) *exec.Cmd {
+	return exec.CommandContext(ctx, "cmd", "/C", recipe)
+}
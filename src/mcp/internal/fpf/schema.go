@@ -0,0 +1,122 @@
+package fpf
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// normalizeSchema converts a Go-literal schema (nested maps of mixed types)
+// into its decoded JSON form so it can be walked uniformly.
+func normalizeSchema(schema interface{}) map[string]interface{} {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil
+	}
+	return normalized
+}
+
+// validateArguments checks tool arguments against the subset of JSON Schema used
+// by the tool definitions: required properties, types, enums, array items and
+// numeric bounds. Properties not declared in the schema are allowed.
+func validateArguments(schema map[string]interface{}, args map[string]interface{}) error {
+	var problems []string
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if v, present := args[name]; !present || v == nil {
+				problems = append(problems, fmt.Sprintf("missing required property %q", name))
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := args[name]
+		prop, ok := properties[name].(map[string]interface{})
+		if !ok || value == nil {
+			continue
+		}
+		problems = append(problems, validateValue(name, prop, value)...)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func validateValue(path string, schema map[string]interface{}, value interface{}) []string {
+	typ, _ := schema["type"].(string)
+
+	switch typ {
+	case "string":
+		if _, ok := value.(string); !ok {
+			return []string{fmt.Sprintf("%s must be a string", path)}
+		}
+	case "integer", "number":
+		n, ok := value.(float64)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be a %s", path, typ)}
+		}
+		if typ == "integer" && n != math.Trunc(n) {
+			return []string{fmt.Sprintf("%s must be an integer", path)}
+		}
+		if min, ok := schema["minimum"].(float64); ok && n < min {
+			return []string{fmt.Sprintf("%s must be >= %g", path, min)}
+		}
+		if max, ok := schema["maximum"].(float64); ok && n > max {
+			return []string{fmt.Sprintf("%s must be <= %g", path, max)}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s must be a boolean", path)}
+		}
+	case "object":
+		if _, ok := value.(map[string]interface{}); !ok {
+			return []string{fmt.Sprintf("%s must be an object", path)}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s must be an array", path)}
+		}
+		itemSchema, _ := schema["items"].(map[string]interface{})
+		if itemSchema == nil {
+			break
+		}
+		var problems []string
+		for i, item := range items {
+			problems = append(problems, validateValue(fmt.Sprintf("%s[%d]", path, i), itemSchema, item)...)
+		}
+		return problems
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		for _, allowed := range enum {
+			if allowed == value {
+				return nil
+			}
+		}
+		options := make([]string, len(enum))
+		for i, e := range enum {
+			options[i] = fmt.Sprint(e)
+		}
+		return []string{fmt.Sprintf("%s must be one of: %s", path, strings.Join(options, ", "))}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package fpf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// SearchOptions narrows a knowledge base search. Zero values mean no filter.
+type SearchOptions struct {
+	Layer string
+	Kind  string
+	Scope string
+	MinR  float64
+	Limit int
+}
+
+// Search runs a full-text query over the holons, DRRs and evidence of the active
+// context and renders the hits as a markdown table, best match first.
+func (t *Tools) Search(query string, opts SearchOptions) (string, error) {
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+	if strings.TrimSpace(query) == "" {
+		return "", fmt.Errorf("search query is required")
+	}
+
+	results, err := t.DB.Search(context.Background(), db.SearchParams{
+		Query:     query,
+		ContextID: t.ContextID(),
+		Layer:     opts.Layer,
+		Kind:      opts.Kind,
+		Scope:     opts.Scope,
+		MinR:      opts.MinR,
+		Limit:     opts.Limit,
+	})
+	if err != nil {
+		return "", fmt.Errorf("search failed: %v", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Search Results for %q\n\n", query))
+	if filters := opts.describe(); filters != "" {
+		sb.WriteString(fmt.Sprintf("Filters: %s\n\n", filters))
+	}
+	if len(results) == 0 {
+		sb.WriteString("No matches.\n")
+		return sb.String(), nil
+	}
+
+	sb.WriteString("| Match | Type | Holon | Layer | Kind | R_eff | Snippet |\n")
+	sb.WriteString("|-------|------|-------|-------|------|-------|---------|\n")
+	for _, r := range results {
+		rScore := "-"
+		if r.CachedRScore.Valid {
+			rScore = fmt.Sprintf("%.2f", r.CachedRScore.Float64)
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+			r.DocID, r.DocType, r.HolonID, r.Layer, r.Kind.String, rScore, tableCell(r.Snippet)))
+	}
+	sb.WriteString(fmt.Sprintf("\n%d result(s). Read details with quint://holon/<id>, quint://decision/<id> or quint://evidence/<id>.\n", len(results)))
+
+	return sb.String(), nil
+}
+
+func (o SearchOptions) describe() string {
+	var parts []string
+	if o.Layer != "" {
+		parts = append(parts, "layer="+o.Layer)
+	}
+	if o.Kind != "" {
+		parts = append(parts, "kind="+o.Kind)
+	}
+	if o.Scope != "" {
+		parts = append(parts, "scope~"+o.Scope)
+	}
+	if o.MinR > 0 {
+		parts = append(parts, fmt.Sprintf("R_eff>=%.2f", o.MinR))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// tableCell keeps free text from breaking a markdown table row
+func tableCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
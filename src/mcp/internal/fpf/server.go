@@ -2,23 +2,62 @@ package fpf
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sync"
+
+	"github.com/m0n0x41d/quint-code/assurance"
+)
+
+// ProtocolVersions lists the MCP protocol revisions the server speaks, newest first
+var ProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC 2.0 error codes
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+
+	// CodeResourceNotFound is the MCP error for resources/read of an unknown URI
+	CodeResourceNotFound = -32002
+
+	// CodeServerBusy answers requests that arrive while the request queue is full
+	CodeServerBusy = -32000
 )
 
+// maxQueuedRequests bounds the stdio requests waiting behind the running one
+const maxQueuedRequests = 64
+
 type JSONRPCRequest struct {
 	JSONRPC string          `json:"jsonrpc"`
 	Method  string          `json:"method"`
-	Params  json.RawMessage `json:"params"`
-	ID      interface{}     `json:"id"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+// IsNotification reports whether the sender expects no response
+func (r JSONRPCRequest) IsNotification() bool {
+	return len(r.ID) == 0
 }
 
 type JSONRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type JSONRPCNotification struct {
 	JSONRPC string      `json:"jsonrpc"`
-	Result  interface{} `json:"result,omitempty"`
-	Error   *RPCError   `json:"error,omitempty"`
-	ID      interface{} `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
 }
 
 type RPCError struct {
@@ -42,85 +81,413 @@ type ContentItem struct {
 	Text string `json:"text"`
 }
 
+// requestMeta carries the optional _meta block of a request
+type requestMeta struct {
+	Meta struct {
+		ProgressToken json.RawMessage `json:"progressToken,omitempty"`
+	} `json:"_meta"`
+}
+
+// Server speaks MCP over JSON-RPC 2.0. It is transport-agnostic: Serve drives it
+// over newline-delimited stdio, HandleMessage processes one message from any transport.
 type Server struct {
-	tools *Tools
+	tools   *Tools
+	prompts []PromptTemplate
+	schemas map[string]map[string]interface{} // tool input schemas, decoded once for validation
+
+	// calls serializes tool execution: Tools, the FSM and the Store are not safe
+	// for concurrent use. A channel rather than a mutex so waiting can be cancelled.
+	calls chan struct{}
+
+	// shared marks a session that shares its Tools, and so the active context,
+	// with other sessions
+	shared bool
+
+	mu              sync.Mutex
+	protocolVersion string
+	inflight        map[string]context.CancelFunc
+	cancelled       map[string]bool
 }
 
 func NewServer(t *Tools) *Server {
-	return &Server{tools: t}
+	return &Server{
+		tools:     t,
+		schemas:   toolSchemas(),
+		calls:     make(chan struct{}, 1),
+		inflight:  make(map[string]context.CancelFunc),
+		cancelled: make(map[string]bool),
+	}
 }
 
-func (s *Server) Start() {
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
+// NewSession returns a server for one client session. It shares the tools, prompts
+// and tool-call serialization with s but negotiates its own protocol version.
+// Because the active context is shared too, sessions cannot switch it.
+func (s *Server) NewSession() *Server {
+	return &Server{
+		tools:     s.tools,
+		prompts:   s.prompts,
+		schemas:   s.schemas,
+		calls:     s.calls,
+		shared:    true,
+		inflight:  make(map[string]context.CancelFunc),
+		cancelled: make(map[string]bool),
+	}
+}
+
+// SetPrompts registers the slash commands offered through prompts/list and prompts/get
+func (s *Server) SetPrompts(prompts []PromptTemplate) {
+	s.prompts = prompts
+}
+
+// Start serves MCP over stdin/stdout until stdin is closed
+func (s *Server) Start() error {
+	return s.Serve(os.Stdin, os.Stdout)
+}
+
+// Serve reads newline-delimited JSON-RPC messages from r and writes responses to w.
+// Messages may be of any size. Requests are processed in arrival order, while ping
+// and cancellation are answered straight from the reader so they are never queued
+// behind a long-running tool call. When the queue is full, further requests are
+// answered with CodeServerBusy rather than blocking the reader.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	var writeMu sync.Mutex
+	write := func(data []byte) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if _, err := w.Write(append(data, '\n')); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to write JSON-RPC message: %v\n", err)
+		}
+	}
+	notify := func(msg interface{}) {
+		if data, err := json.Marshal(msg); err == nil {
+			write(data)
+		}
+	}
+
+	queue := make(chan []byte, maxQueuedRequests)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for line := range queue {
+			if resp := s.HandleMessage(context.Background(), line, notify); resp != nil {
+				write(resp)
+			}
+		}
+	}()
+
+	reader := bufio.NewReader(r)
+	var readErr error
+	for {
+		line, err := reader.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			if resp, handled := s.handleImmediate(line); handled {
+				if resp != nil {
+					write(resp)
+				}
+			} else {
+				select {
+				case queue <- line:
+				default:
+					if resp := busyResponse(line); resp != nil {
+						write(resp)
+					}
+				}
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				readErr = err
+			}
+			break
 		}
+	}
+
+	close(queue)
+	<-done
+	return readErr
+}
 
+// busyResponse rejects a message the request queue has no room for. Requests,
+// including those in a batch, get CodeServerBusy; notifications are dropped.
+func busyResponse(raw []byte) []byte {
+	var reqs []JSONRPCRequest
+	batch := raw[0] == '['
+	var err error
+	if batch {
+		err = json.Unmarshal(raw, &reqs)
+	} else {
 		var req JSONRPCRequest
-		if err := json.Unmarshal(line, &req); err != nil {
-			s.sendError(nil, -32700, "Parse error")
+		err = json.Unmarshal(raw, &req)
+		reqs = append(reqs, req)
+	}
+	if err != nil {
+		return encode(errorResponse(nil, CodeParseError, "Parse error"))
+	}
+
+	var responses []*JSONRPCResponse
+	for _, req := range reqs {
+		if req.IsNotification() {
+			fmt.Fprintf(os.Stderr, "Warning: request queue full, dropping notification %s\n", req.Method)
 			continue
 		}
+		responses = append(responses, errorResponse(req.ID, CodeServerBusy, "Server busy: too many queued requests, retry later"))
+	}
+	switch {
+	case len(responses) == 0:
+		return nil
+	case batch:
+		return encode(responses)
+	default:
+		return encode(responses[0])
+	}
+}
+
+// handleImmediate answers messages that must not wait for the request queue
+func (s *Server) handleImmediate(raw []byte) ([]byte, bool) {
+	var req JSONRPCRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return nil, false
+	}
+	switch req.Method {
+	case "ping", "notifications/cancelled":
+		return s.HandleMessage(context.Background(), raw, nil), true
+	}
+	return nil, false
+}
 
-		switch req.Method {
-		case "initialize":
-			s.handleInitialize(req)
-		case "tools/list":
-			s.handleToolsList(req)
-		case "tools/call":
-			s.handleToolsCall(req)
-		case "notifications/initialized":
-			// No-op
-		default:
-			if req.ID != nil {
-				s.sendError(req.ID, -32601, "Method not found")
+// HandleMessage processes a single request, a notification or a batch and returns
+// the encoded response. It returns nil when there is nothing to send back:
+// notifications, batches made only of notifications, and cancelled requests.
+// notify receives server-initiated notifications such as progress; it may be nil.
+func (s *Server) HandleMessage(ctx context.Context, raw []byte, notify func(interface{})) []byte {
+	raw = bytes.TrimSpace(raw)
+	if !json.Valid(raw) {
+		return encode(errorResponse(nil, CodeParseError, "Parse error"))
+	}
+
+	if len(raw) > 0 && raw[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(raw, &batch); err != nil || len(batch) == 0 {
+			return encode(errorResponse(nil, CodeInvalidRequest, "Invalid Request: empty batch"))
+		}
+
+		var responses []*JSONRPCResponse
+		for _, item := range batch {
+			if resp := s.handleRequest(ctx, item, notify); resp != nil {
+				responses = append(responses, resp)
 			}
 		}
+		if len(responses) == 0 {
+			return nil
+		}
+		return encode(responses)
 	}
+
+	if resp := s.handleRequest(ctx, raw, notify); resp != nil {
+		return encode(resp)
+	}
+	return nil
 }
 
-func (s *Server) send(resp JSONRPCResponse) {
-	bytes, err := json.Marshal(resp)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to marshal JSON-RPC response: %v\n", err)
-		return
+func (s *Server) handleRequest(ctx context.Context, raw json.RawMessage, notify func(interface{})) *JSONRPCResponse {
+	var req JSONRPCRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return errorResponse(nil, CodeInvalidRequest, "Invalid Request")
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, CodeInvalidRequest, "Invalid Request: jsonrpc must be \"2.0\" and method is required")
+	}
+
+	if req.IsNotification() {
+		s.handleNotification(req)
+		return nil
 	}
-	fmt.Printf("%s\n", string(bytes))
+
+	ctx, release := s.track(ctx, req.ID)
+	defer release()
+
+	result, rpcErr := s.dispatch(ctx, req, notify)
+	if ctx.Err() != nil {
+		// The client cancelled the request and no longer expects a response
+		return nil
+	}
+	if rpcErr != nil {
+		return errorResponse(req.ID, rpcErr.Code, rpcErr.Message)
+	}
+	return &JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
 }
 
-func (s *Server) sendResult(id interface{}, result interface{}) {
-	s.send(JSONRPCResponse{
-		JSONRPC: "2.0",
-		ID:      id,
-		Result:  result,
-	})
+func (s *Server) dispatch(ctx context.Context, req JSONRPCRequest, notify func(interface{})) (interface{}, *RPCError) {
+	switch req.Method {
+	case "initialize":
+		return s.handleInitialize(req)
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": toolDefinitions()}, nil
+	case "tools/call":
+		return s.handleToolsCall(ctx, req, notify)
+	case "resources/list":
+		return s.handleResourcesList(ctx)
+	case "resources/templates/list":
+		return map[string]interface{}{"resourceTemplates": resourceTemplates()}, nil
+	case "resources/read":
+		return s.handleResourcesRead(ctx, req)
+	case "prompts/list":
+		return s.handlePromptsList()
+	case "prompts/get":
+		return s.handlePromptsGet(req)
+	default:
+		return nil, &RPCError{Code: CodeMethodNotFound, Message: "Method not found: " + req.Method}
+	}
 }
 
-func (s *Server) sendError(id interface{}, code int, message string) {
-	s.send(JSONRPCResponse{
-		JSONRPC: "2.0",
-		ID:      id,
-		Error:   &RPCError{Code: code, Message: message},
-	})
+func (s *Server) handleNotification(req JSONRPCRequest) {
+	switch req.Method {
+	case "notifications/cancelled":
+		var params struct {
+			RequestID json.RawMessage `json:"requestId"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil || len(params.RequestID) == 0 {
+			return
+		}
+		s.cancel(params.RequestID)
+	default:
+		// notifications/initialized and unknown notifications need no action
+	}
 }
 
-func (s *Server) handleInitialize(req JSONRPCRequest) {
-	s.sendResult(req.ID, map[string]interface{}{
-		"protocolVersion": "2024-11-05",
-		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{},
-		},
+// track registers a request so notifications/cancelled can reach it
+func (s *Server) track(ctx context.Context, id json.RawMessage) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	key := string(id)
+
+	s.mu.Lock()
+	s.inflight[key] = cancel
+	if s.cancelled[key] {
+		delete(s.cancelled, key)
+		cancel()
+	}
+	s.mu.Unlock()
+
+	return ctx, func() {
+		s.mu.Lock()
+		delete(s.inflight, key)
+		s.mu.Unlock()
+		cancel()
+	}
+}
+
+// cancel stops an in-flight request. A cancellation that overtakes its request
+// (still queued behind another call) is remembered and applied when it starts.
+func (s *Server) cancel(id json.RawMessage) {
+	key := string(id)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cancel, ok := s.inflight[key]; ok {
+		cancel()
+		return
+	}
+	s.cancelled[key] = true
+}
+
+func (s *Server) handleInitialize(req JSONRPCRequest) (interface{}, *RPCError) {
+	var params struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &RPCError{Code: CodeInvalidParams, Message: "Invalid params: " + err.Error()}
+		}
+	}
+
+	version := NegotiateProtocolVersion(params.ProtocolVersion)
+	s.mu.Lock()
+	s.protocolVersion = version
+	s.mu.Unlock()
+
+	capabilities := map[string]interface{}{
+		"tools":     map[string]interface{}{"listChanged": false},
+		"resources": map[string]interface{}{"subscribe": false, "listChanged": false},
+	}
+	if len(s.prompts) > 0 {
+		capabilities["prompts"] = map[string]interface{}{"listChanged": false}
+	}
+
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities":    capabilities,
 		"serverInfo": map[string]string{
 			"name":    "quint-code",
 			"version": "4.0.0",
 		},
-	})
+	}, nil
+}
+
+// NegotiateProtocolVersion echoes the client's version when supported and
+// otherwise offers the newest version the server speaks.
+func NegotiateProtocolVersion(requested string) string {
+	for _, v := range ProtocolVersions {
+		if v == requested {
+			return v
+		}
+	}
+	return ProtocolVersions[0]
+}
+
+// ProtocolVersion returns the version agreed during initialize, or "" before it
+func (s *Server) ProtocolVersion() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.protocolVersion
+}
+
+// errCancelled is never sent: responses to cancelled requests are dropped
+var errCancelled = &RPCError{Code: CodeInternalError, Message: "Request cancelled"}
+
+// acquire waits for exclusive access to Tools and the Store, giving up if ctx is
+// cancelled. It then picks up a context switch made by the CLI since the last call.
+func (s *Server) acquire(ctx context.Context) bool {
+	select {
+	case s.calls <- struct{}{}:
+	case <-ctx.Done():
+		return false
+	}
+	if ctx.Err() != nil {
+		s.release()
+		return false
+	}
+	if err := s.tools.ReloadActiveContext(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to reload active context: %v\n", err)
+	}
+	return true
+}
+
+func (s *Server) release() {
+	<-s.calls
+}
+
+func errorResponse(id json.RawMessage, code int, message string) *JSONRPCResponse {
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &RPCError{Code: code, Message: message},
+	}
+}
+
+func encode(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to marshal JSON-RPC response: %v\n", err)
+		data, _ = json.Marshal(errorResponse(nil, CodeInternalError, "Internal error"))
+	}
+	return data
 }
 
-func (s *Server) handleToolsList(req JSONRPCRequest) {
-	tools := []Tool{
+func toolDefinitions() []Tool {
+	return []Tool{
 		{
 			Name:        "quint_status",
 			Description: "Get current FPF phase and context.",
@@ -129,6 +496,19 @@ func (s *Server) handleToolsList(req JSONRPCRequest) {
 				"properties": map[string]interface{}{},
 			},
 		},
+		{
+			Name:        "quint_context",
+			Description: "Manage bounded contexts. Each context has its own holons, evidence, audit log and phase, so independent decisions can run in parallel.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"action":      map[string]interface{}{"type": "string", "enum": []interface{}{"create", "list", "switch", "archive"}},
+					"name":        map[string]string{"type": "string", "description": "Context name (create) or ID (switch, archive)"},
+					"description": map[string]string{"type": "string", "description": "What this context is about (create only)"},
+				},
+				"required": []string{"action"},
+			},
+		},
 		{
 			Name:        "quint_init",
 			Description: "Initialize FPF project structure.",
@@ -174,7 +554,24 @@ func (s *Server) handleToolsList(req JSONRPCRequest) {
 						"minimum":     1,
 						"maximum":     3,
 						"default":     3,
-						"description": "Congruence level for dependencies. CL3=same context (no penalty), CL2=similar (10% penalty), CL1=different (30% penalty).",
+						"description": "Congruence level for dependencies. CL3=same context (no penalty), CL2=similar, CL1=different. Penalty size follows the project's Φ(CL) model (.quint/config.json).",
+					},
+					"formality": map[string]interface{}{
+						"type":        "integer",
+						"minimum":     0,
+						"maximum":     9,
+						"default":     0,
+						"description": "Formality (F) of the claim: F0=informal prose, F3=structured spec, F6=executable model, F9=machine-checked proof. Propagates by weakest link.",
+					},
+					"claim_scope": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "ClaimScope (G): contexts where the claim holds, e.g. [\"api\", \"eu-region\"]. Omit for unbounded. Intersected across dependencies.",
+					},
+					"anchors": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "Code anchors the hypothesis covers: path globs, \"pkg:<import path>\" or \"symbol:Type.Method\". See quint_anchor.",
 					},
 				},
 				"required": []string{"title", "content", "scope", "kind", "rationale"},
@@ -187,10 +584,26 @@ func (s *Server) handleToolsList(req JSONRPCRequest) {
 				"type": "object",
 				"properties": map[string]interface{}{
 					"hypothesis_id": map[string]string{"type": "string"},
-					"checks_json":   map[string]string{"type": "string", "description": "JSON of checks"},
-					"verdict":       map[string]interface{}{"type": "string", "enum": []interface{}{"PASS", "FAIL", "REFINE"}},
+					"checks": map[string]interface{}{
+						"type": "array",
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"name":     map[string]string{"type": "string", "description": "What was checked, e.g. 'latency budget'"},
+								"category": map[string]interface{}{"type": "string", "enum": []interface{}{CheckTypeCheck, CheckConstraint, CheckLogicalConsistency}},
+								"result":   map[string]interface{}{"type": "string", "enum": []interface{}{"pass", "fail"}},
+								"notes":    map[string]string{"type": "string"},
+							},
+							"required":             []string{"name", "category", "result"},
+							"additionalProperties": false,
+						},
+						"minItems":    1,
+						"description": "Checks performed. Each is recorded as its own evidence item, so one failed check lowers R on its own. PASS is refused when any check failed.",
+					},
+					"verdict":   map[string]interface{}{"type": "string", "enum": []interface{}{"PASS", "FAIL", "REFINE"}},
+					"formality": map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 9, "description": "Formality (F) of the verification evidence"},
 				},
-				"required": []string{"hypothesis_id", "checks_json", "verdict"},
+				"required": []string{"hypothesis_id", "checks", "verdict"},
 			},
 		},
 		{
@@ -203,10 +616,47 @@ func (s *Server) handleToolsList(req JSONRPCRequest) {
 					"test_type":     map[string]string{"type": "string", "description": "internal or research"},
 					"result":        map[string]string{"type": "string", "description": "Test output/findings"},
 					"verdict":       map[string]interface{}{"type": "string", "enum": []interface{}{"PASS", "FAIL", "REFINE"}},
+					"formality":     map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 9, "description": "Formality (F) of the validation evidence"},
+					"artifact_kind": map[string]interface{}{
+						"type":        "string",
+						"enum":        []interface{}{assurance.ArtifactJUnit, assurance.ArtifactGoTest, assurance.ArtifactBenchmark, assurance.ArtifactLog, assurance.ArtifactFile, assurance.ArtifactCommit},
+						"description": "Kind of artifact backing the result. R drops to the decay floor if a stored file later changes.",
+					},
+					"artifact": map[string]string{"type": "string", "description": "Path to the report, benchmark output or log (copied into .quint/evidence/artifacts), or a commit SHA"},
+					"recipe":   map[string]string{"type": "string", "description": "Single-line shell command that reproduces the result (exit 0 = pass), e.g. 'go test ./cache/...'. `quint-code revalidate` re-runs it when the evidence goes stale, once the user approves it."},
 				},
 				"required": []string{"hypothesis_id", "test_type", "result", "verdict"},
 			},
 		},
+		{
+			Name:        "quint_ingest_tests",
+			Description: "Record a go test -json or JUnit report as validation evidence (L1 -> L2). The verdict follows the pass ratio: PASS when all tests pass, DEGRADE at 80% or more, FAIL below. Each test becomes its own evidence item and the report is attached as the artifact.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"hypothesis_id": map[string]string{"type": "string"},
+					"format":        map[string]interface{}{"type": "string", "enum": []interface{}{FormatGoTest, FormatJUnit}},
+					"path":          map[string]string{"type": "string", "description": "Report file, relative to the project root or absolute"},
+					"test_type":     map[string]string{"type": "string", "description": "Evidence type (default: internal)"},
+				},
+				"required": []string{"hypothesis_id", "format", "path"},
+			},
+		},
+		{
+			Name:        "quint_refine",
+			Description: "Replace a hypothesis that needs refinement (REFINE verdict) with a child hypothesis. The parent moves to invalid; the child starts in L0, keeps the parent's dependencies and decision context, and records the loopback in its lineage.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"parent_id": map[string]string{"type": "string", "description": "Hypothesis to refine (L0, L1, or invalid after a REFINE verdict)"},
+					"insight":   map[string]string{"type": "string", "description": "What verification or testing revealed"},
+					"title":     map[string]string{"type": "string", "description": "Title of the refined hypothesis"},
+					"content":   map[string]string{"type": "string", "description": "The refined hypothesis"},
+					"scope":     map[string]string{"type": "string", "description": "Defaults to the parent's scope"},
+				},
+				"required": []string{"parent_id", "insight", "title", "content"},
+			},
+		},
 		{
 			Name:        "quint_audit",
 			Description: "Record audit/trust score (R_eff).",
@@ -236,22 +686,72 @@ func (s *Server) handleToolsList(req JSONRPCRequest) {
 					"decision":        map[string]string{"type": "string"},
 					"rationale":       map[string]string{"type": "string"},
 					"consequences":    map[string]string{"type": "string"},
-					"characteristics": map[string]string{"type": "string"},
+					"characteristics": map[string]string{"type": "string", "description": "Free-text notes; recorded quint_characterize values are rendered as a comparison matrix"},
 				},
 				"required": []string{"title", "winner_id", "context", "decision", "rationale", "consequences"},
 			},
 		},
+		{
+			Name:        "quint_characterize",
+			Description: "Record a characteristic of a hypothesis (C.16 characteristic space). quint_decide compares the winner and rejected alternatives on these.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"holon_id": map[string]string{"type": "string"},
+					"name":     map[string]string{"type": "string", "description": "Characteristic, e.g. 'p99 latency' or 'license'"},
+					"scale":    map[string]interface{}{"type": "string", "enum": []interface{}{"nominal", "ordinal", "interval", "ratio"}},
+					"value":    map[string]string{"type": "string", "description": "Measured value; numeric for interval and ratio scales"},
+					"unit":     map[string]string{"type": "string", "description": "Unit of measure, e.g. 'ms'"},
+				},
+				"required": []string{"holon_id", "name", "scale", "value"},
+			},
+		},
 		{
 			Name:        "quint_actualize",
-			Description: "Reconcile the project's FPF state with recent repository changes.",
+			Description: "Reconcile the project's FPF state with recent repository changes. Reports the holons and DRRs whose code anchors match changed files, with their evidence, and context drift: dependencies, services and invariants that changed since the last run.",
 			InputSchema: map[string]interface{}{
-				"type":       "object",
-				"properties": map[string]interface{}{},
+				"type": "object",
+				"properties": map[string]interface{}{
+					"shorten_valid_until": map[string]string{"type": "boolean", "description": "Cap valid_until of evidence behind affected holons so it is re-checked soon (default: report only)"},
+					"valid_days": map[string]interface{}{
+						"type":        "integer",
+						"minimum":     1,
+						"default":     DefaultImpactValidityDays,
+						"description": "Days from now the affected evidence stays valid when shortening",
+					},
+				},
+			},
+		},
+		{
+			Name:        "quint_anchor",
+			Description: "Declare the code a hypothesis or DRR covers. quint_actualize reports it when anchored code changes.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"holon_id": map[string]string{"type": "string"},
+					"anchors": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "Code anchors: path globs (\"path:src/cache/**\", default kind), Go packages (\"pkg:github.com/org/repo/cache\") or symbols (\"symbol:Cache.Get\")",
+					},
+					"replace": map[string]string{"type": "boolean", "description": "Replace the existing anchors instead of adding to them"},
+				},
+				"required": []string{"holon_id", "anchors"},
+			},
+		},
+		{
+			Name:        "quint_doctor",
+			Description: "Check that markdown files and the database agree: layers, content hashes, evidence and DRR files, dangling relations. With fix=true, regenerate files from the DB and import rows for orphan files.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"fix": map[string]string{"type": "boolean", "description": "Repair the issues found (default: report only)"},
+				},
 			},
 		},
 		{
 			Name:        "quint_audit_tree",
-			Description: "Visualize the assurance tree for a holon, showing R scores, dependencies, and CL penalties.",
+			Description: "Visualize the assurance tree for a holon, showing the F-G-R tuple, dependencies, and CL penalties.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -262,7 +762,7 @@ func (s *Server) handleToolsList(req JSONRPCRequest) {
 		},
 		{
 			Name:        "quint_calculate_r",
-			Description: "Calculate the effective reliability (R_eff) for a holon with detailed breakdown.",
+			Description: "Calculate the assurance tuple ⟨F,G,R⟩ for a holon with detailed R_eff breakdown.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -271,9 +771,36 @@ func (s *Server) handleToolsList(req JSONRPCRequest) {
 				"required": []string{"holon_id"},
 			},
 		},
+		{
+			Name:        "quint_search",
+			Description: "Full-text search over hypotheses, DRRs and evidence of the active context. Filter by layer, kind, scope and minimum cached R_eff.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"query": map[string]string{"type": "string", "description": "Words to find (all must match). Append * for prefix search; OR and NOT are supported."},
+					"layer": map[string]interface{}{"type": "string", "enum": []interface{}{"L0", "L1", "L2", "invalid", "DRR"}},
+					"kind":  map[string]interface{}{"type": "string", "enum": []interface{}{"system", "episteme"}},
+					"scope": map[string]string{"type": "string", "description": "Substring of the holon scope"},
+					"min_r": map[string]interface{}{"type": "number", "minimum": 0, "maximum": 1, "description": "Minimum cached R_eff"},
+					"limit": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 100, "default": 20},
+				},
+				"required": []string{"query"},
+			},
+		},
+		{
+			Name:        "quint_debt",
+			Description: "Score the epistemic debt of the active context: stale evidence, changed artifacts, low-R L2 holons, waivers near expiry, unverified L0 hypotheses and rejected alternatives never verified. Lists the contributing items per holon with the trend since earlier measurements.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"holon_id": map[string]string{"type": "string", "description": "Only report this holon"},
+					"format":   map[string]interface{}{"type": "string", "enum": []interface{}{"markdown", "json"}, "default": "markdown"},
+				},
+			},
+		},
 		{
 			Name:        "quint_check_decay",
-			Description: "Check evidence freshness and manage stale decisions. Without parameters: shows freshness report. With deprecate: downgrades hypothesis. With waive: records temporary risk acceptance.",
+			Description: "Check evidence freshness and manage stale decisions. Without parameters: shows freshness report. With forecast_days: also lists holons whose R will drop below the assurance threshold. With deprecate: downgrades hypothesis. With waive: records temporary risk acceptance, honored in R_eff until it expires. With revoke_id: withdraws a waiver.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -293,56 +820,189 @@ func (s *Server) handleToolsList(req JSONRPCRequest) {
 						"type":        "string",
 						"description": "Reason for accepting stale evidence (required with waive_id)",
 					},
+					"revoke_id": map[string]string{
+						"type":        "string",
+						"description": "Evidence ID whose active waiver to revoke",
+					},
+					"forecast_days": map[string]interface{}{
+						"type":        "integer",
+						"minimum":     1,
+						"description": "Also forecast which L1/L2 holons drop below the assurance threshold within this many days",
+					},
 				},
 			},
 		},
 	}
+}
 
-	s.sendResult(req.ID, map[string]interface{}{
-		"tools": tools,
-	})
+// toolSchemas decodes each tool's input schema into its JSON form for validation
+func toolSchemas() map[string]map[string]interface{} {
+	schemas := make(map[string]map[string]interface{})
+	for _, tool := range toolDefinitions() {
+		schemas[tool.Name] = normalizeSchema(tool.InputSchema)
+	}
+	return schemas
 }
 
-func (s *Server) handleToolsCall(req JSONRPCRequest) {
+func (s *Server) handleToolsCall(ctx context.Context, req JSONRPCRequest, notify func(interface{})) (interface{}, *RPCError) {
 	var params struct {
 		Name      string                 `json:"name"`
 		Arguments map[string]interface{} `json:"arguments"`
+		requestMeta
 	}
 	if err := json.Unmarshal(req.Params, &params); err != nil {
-		s.sendError(req.ID, -32700, "Invalid params")
-		return
+		return nil, &RPCError{Code: CodeInvalidParams, Message: "Invalid params: " + err.Error()}
+	}
+	if params.Name == "" {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: "Invalid params: tool name is required"}
+	}
+
+	schema, ok := s.schemas[params.Name]
+	if !ok {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: "Unknown tool: " + params.Name}
+	}
+	if err := validateArguments(schema, params.Arguments); err != nil {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: fmt.Sprintf("Invalid arguments for %s: %v", params.Name, err)}
+	}
+
+	if !s.acquire(ctx) {
+		return nil, errCancelled
+	}
+	defer s.release()
+
+	progress := func(done float64, message string) {
+		if notify == nil || len(params.Meta.ProgressToken) == 0 {
+			return
+		}
+		notify(JSONRPCNotification{
+			JSONRPC: "2.0",
+			Method:  "notifications/progress",
+			Params: map[string]interface{}{
+				"progressToken": params.Meta.ProgressToken,
+				"progress":      done,
+				"total":         1,
+				"message":       message,
+			},
+		})
+	}
+
+	progress(0, "Running "+params.Name)
+	result := s.callTool(params.Name, params.Arguments)
+	progress(1, "Finished "+params.Name)
+
+	return result, nil
+}
+
+func (s *Server) handleResourcesList(ctx context.Context) (interface{}, *RPCError) {
+	if !s.acquire(ctx) {
+		return nil, errCancelled
+	}
+	defer s.release()
+
+	resources, err := s.tools.ListResources()
+	if err != nil {
+		return nil, &RPCError{Code: CodeInternalError, Message: err.Error()}
+	}
+	return map[string]interface{}{"resources": resources}, nil
+}
+
+func (s *Server) handleResourcesRead(ctx context.Context, req JSONRPCRequest) (interface{}, *RPCError) {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: "Invalid params: " + err.Error()}
 	}
+	if params.URI == "" {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: "Invalid params: uri is required"}
+	}
+
+	if !s.acquire(ctx) {
+		return nil, errCancelled
+	}
+	defer s.release()
+
+	contents, err := s.tools.ReadResource(params.URI)
+	if errors.Is(err, ErrResourceNotFound) {
+		return nil, &RPCError{Code: CodeResourceNotFound, Message: "Resource not found: " + params.URI}
+	}
+	if err != nil {
+		return nil, &RPCError{Code: CodeInternalError, Message: err.Error()}
+	}
+	return map[string]interface{}{"contents": []ResourceContents{contents}}, nil
+}
 
+func (s *Server) handlePromptsList() (interface{}, *RPCError) {
+	prompts := make([]Prompt, 0, len(s.prompts))
+	for _, p := range s.prompts {
+		prompts = append(prompts, p.Prompt)
+	}
+	return map[string]interface{}{"prompts": prompts}, nil
+}
+
+func (s *Server) handlePromptsGet(req JSONRPCRequest) (interface{}, *RPCError) {
+	var params struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: "Invalid params: " + err.Error()}
+	}
+
+	for _, p := range s.prompts {
+		if p.Name != params.Name {
+			continue
+		}
+		return map[string]interface{}{
+			"description": p.Description,
+			"messages": []PromptMessage{{
+				Role:    "user",
+				Content: ContentItem{Type: "text", Text: p.Render(params.Arguments[PromptArgumentsName])},
+			}},
+		}, nil
+	}
+	return nil, &RPCError{Code: CodeInvalidParams, Message: "Unknown prompt: " + params.Name}
+}
+
+// callTool runs a tool whose arguments already passed schema validation.
+// Tool failures are reported in the result, not as protocol errors.
+func (s *Server) callTool(name string, arguments map[string]interface{}) CallToolResult {
 	arg := func(k string) string {
-		if v, ok := params.Arguments[k].(string); ok {
+		if v, ok := arguments[k].(string); ok {
 			return v
 		}
 		return ""
 	}
 
 	args := make(map[string]string)
-	for k, v := range params.Arguments {
+	for k, v := range arguments {
 		if s, ok := v.(string); ok {
 			args[k] = s
 		}
 	}
 
-	if precondErr := s.tools.CheckPreconditions(params.Name, args); precondErr != nil {
-		s.tools.AuditLog(params.Name, "precondition_failed", "agent", "", "BLOCKED", args, precondErr.Error())
-		s.sendResult(req.ID, CallToolResult{
+	if precondErr := s.tools.CheckPreconditions(name, args); precondErr != nil {
+		s.tools.AuditLog(name, "precondition_failed", "agent", "", "BLOCKED", args, precondErr.Error())
+		return CallToolResult{
 			Content: []ContentItem{{Type: "text", Text: precondErr.Error()}},
 			IsError: true,
-		})
-		return
+		}
 	}
 
 	var output string
 	var err error
 
-	switch params.Name {
+	switch name {
 	case "quint_status":
 		st := s.tools.FSM.State.Phase
-		output = string(st)
+		output = fmt.Sprintf("%s (context: %s)", st, s.tools.ContextID())
+
+	case "quint_context":
+		if s.shared && arg("action") == "switch" {
+			err = fmt.Errorf("cannot switch context over a shared HTTP session: it would redirect every other session's writes; run `quint-code context switch %s` instead", arg("name"))
+			break
+		}
+		output, err = s.tools.ManageContext(arg("action"), arg("name"), arg("description"))
 
 	case "quint_init":
 		res := s.tools.InitProject()
@@ -350,26 +1010,32 @@ func (s *Server) handleToolsCall(req JSONRPCRequest) {
 			err = res
 		} else {
 			s.tools.FSM.State.Phase = PhaseAbduction
-			if saveErr := s.tools.FSM.SaveState("default"); saveErr != nil {
+			if saveErr := s.tools.FSM.SaveState(s.tools.ContextID()); saveErr != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", saveErr)
 			}
 			output = "Initialized. Phase: ABDUCTION"
 		}
 
 	case "quint_actualize":
-		output, err = s.tools.Actualize()
+		shorten, _ := arguments["shorten_valid_until"].(bool)
+		days, _ := arguments["valid_days"].(float64)
+		output, err = s.tools.Actualize(ActualizeOptions{ShortenValidity: shorten, ValidityDays: int(days)})
+
+	case "quint_anchor":
+		replace, _ := arguments["replace"].(bool)
+		output, err = s.tools.SetAnchors(arg("holon_id"), stringSlice(arguments["anchors"]), replace)
 
 	case "quint_record_context":
 		output, err = s.tools.RecordContext(arg("vocabulary"), arg("invariants"))
 
 	case "quint_propose":
 		s.tools.FSM.State.Phase = PhaseAbduction
-		if saveErr := s.tools.FSM.SaveState("default"); saveErr != nil {
+		if saveErr := s.tools.FSM.SaveState(s.tools.ContextID()); saveErr != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", saveErr)
 		}
 		decisionContext := arg("decision_context")
 		var dependsOn []string
-		if deps, ok := params.Arguments["depends_on"].([]interface{}); ok {
+		if deps, ok := arguments["depends_on"].([]interface{}); ok {
 			for _, d := range deps {
 				if s, ok := d.(string); ok {
 					dependsOn = append(dependsOn, s)
@@ -377,21 +1043,53 @@ func (s *Server) handleToolsCall(req JSONRPCRequest) {
 			}
 		}
 		dependencyCL := 3
-		if cl, ok := params.Arguments["dependency_cl"].(float64); ok {
+		if cl, ok := arguments["dependency_cl"].(float64); ok {
 			dependencyCL = int(cl)
 		}
-		output, err = s.tools.ProposeHypothesis(arg("title"), arg("content"), arg("scope"), arg("kind"), arg("rationale"), decisionContext, dependsOn, dependencyCL)
+		// The hypothesis, its claim and its anchors are recorded together or not at all
+		err = s.tools.transact(func() error {
+			var err error
+			if output, err = s.tools.ProposeHypothesis(arg("title"), arg("content"), arg("scope"), arg("kind"), arg("rationale"), decisionContext, dependsOn, dependencyCL); err != nil {
+				return err
+			}
+			formality, hasFormality := arguments["formality"].(float64)
+			claimScope := stringSlice(arguments["claim_scope"])
+			if hasFormality || len(claimScope) > 0 {
+				if err := s.tools.RecordClaim(s.tools.Slugify(arg("title")), int(formality), claimScope); err != nil {
+					return err
+				}
+			}
+			if anchors := stringSlice(arguments["anchors"]); len(anchors) > 0 {
+				_, err = s.tools.SetAnchors(s.tools.Slugify(arg("title")), anchors, false)
+			}
+			return err
+		})
 
 	case "quint_verify":
 		s.tools.FSM.State.Phase = PhaseDeduction
-		if saveErr := s.tools.FSM.SaveState("default"); saveErr != nil {
+		if saveErr := s.tools.FSM.SaveState(s.tools.ContextID()); saveErr != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", saveErr)
 		}
-		output, err = s.tools.VerifyHypothesis(arg("hypothesis_id"), arg("checks_json"), arg("verdict"))
+		var checks []VerificationCheck
+		if checks, err = ParseVerificationChecks(arguments["checks"]); err != nil {
+			break
+		}
+		// The verdict and the formality of its checks are recorded together
+		err = s.tools.transact(func() error {
+			var evidenceIDs []string
+			var err error
+			if output, evidenceIDs, err = s.tools.VerifyHypothesis(arg("hypothesis_id"), checks, arg("verdict")); err != nil {
+				return err
+			}
+			if formality, ok := arguments["formality"].(float64); ok {
+				return s.tools.RecordVerificationFormality(evidenceIDs, int(formality))
+			}
+			return nil
+		})
 
 	case "quint_test":
 		s.tools.FSM.State.Phase = PhaseInduction
-		if saveErr := s.tools.FSM.SaveState("default"); saveErr != nil {
+		if saveErr := s.tools.FSM.SaveState(s.tools.ContextID()); saveErr != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", saveErr)
 		}
 
@@ -400,7 +1098,41 @@ func (s *Server) handleToolsCall(req JSONRPCRequest) {
 			assLevel = "L1"
 		}
 
-		output, err = s.tools.ManageEvidence(PhaseInduction, "add", arg("hypothesis_id"), arg("test_type"), arg("result"), arg("verdict"), assLevel, "test-runner", "")
+		// The promotion stands only if the formality, artifact and recipe are
+		// recorded too; otherwise a retry would find the hypothesis gone from L1
+		err = s.tools.transact(func() error {
+			var evidenceID string
+			var err error
+			if output, evidenceID, err = s.tools.ManageEvidence(PhaseInduction, "add", arg("hypothesis_id"), arg("test_type"), arg("result"), arg("verdict"), assLevel, "test-runner", ""); err != nil {
+				return err
+			}
+			if formality, ok := arguments["formality"].(float64); ok {
+				if err := s.tools.RecordEvidenceFormality(evidenceID, int(formality)); err != nil {
+					return err
+				}
+			}
+			if arg("artifact") != "" {
+				attached, err := s.tools.AttachArtifact(evidenceID, arg("artifact_kind"), arg("artifact"))
+				if err != nil {
+					return err
+				}
+				output += "\n" + attached
+			}
+			if arg("recipe") != "" {
+				return s.tools.RecordEvidenceRecipe(evidenceID, arg("recipe"))
+			}
+			return nil
+		})
+
+	case "quint_ingest_tests":
+		s.tools.FSM.State.Phase = PhaseInduction
+		if saveErr := s.tools.FSM.SaveState(s.tools.ContextID()); saveErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", saveErr)
+		}
+		output, err = s.tools.IngestTestResults(arg("hypothesis_id"), arg("format"), arg("path"), arg("test_type"))
+
+	case "quint_refine":
+		output, err = s.tools.RefineHypothesis(arg("parent_id"), arg("insight"), arg("title"), arg("content"), arg("scope"))
 
 	case "quint_audit":
 		output, err = s.tools.AuditEvidence(arg("hypothesis_id"), arg("risks"))
@@ -408,7 +1140,7 @@ func (s *Server) handleToolsCall(req JSONRPCRequest) {
 	case "quint_decide":
 		s.tools.FSM.State.Phase = PhaseDecision
 		var rejectedIDs []string
-		if rids, ok := params.Arguments["rejected_ids"].([]interface{}); ok {
+		if rids, ok := arguments["rejected_ids"].([]interface{}); ok {
 			for _, r := range rids {
 				if s, ok := r.(string); ok {
 					rejectedIDs = append(rejectedIDs, s)
@@ -418,32 +1150,78 @@ func (s *Server) handleToolsCall(req JSONRPCRequest) {
 		output, err = s.tools.FinalizeDecision(arg("title"), arg("winner_id"), rejectedIDs, arg("context"), arg("decision"), arg("rationale"), arg("consequences"), arg("characteristics"))
 		if err == nil {
 			s.tools.FSM.State.Phase = PhaseIdle
-			if saveErr := s.tools.FSM.SaveState("default"); saveErr != nil {
+			if saveErr := s.tools.FSM.SaveState(s.tools.ContextID()); saveErr != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", saveErr)
 			}
 		}
 
+	case "quint_characterize":
+		output, err = s.tools.Characterize(arg("holon_id"), arg("name"), arg("scale"), arg("value"), arg("unit"))
+
+	case "quint_doctor":
+		fix, _ := arguments["fix"].(bool)
+		var report *DoctorReport
+		if report, err = s.tools.Doctor(fix); err == nil {
+			output = report.String()
+		}
+
 	case "quint_audit_tree":
 		output, err = s.tools.VisualizeAudit(arg("holon_id"))
 
 	case "quint_calculate_r":
 		output, err = s.tools.CalculateR(arg("holon_id"))
 
+	case "quint_search":
+		opts := SearchOptions{Layer: arg("layer"), Kind: arg("kind"), Scope: arg("scope")}
+		if minR, ok := arguments["min_r"].(float64); ok {
+			opts.MinR = minR
+		}
+		if limit, ok := arguments["limit"].(float64); ok {
+			opts.Limit = int(limit)
+		}
+		output, err = s.tools.Search(arg("query"), opts)
+
+	case "quint_debt":
+		output, err = s.tools.EpistemicDebt(DebtOptions{HolonID: arg("holon_id"), Format: arg("format")})
+
 	case "quint_check_decay":
+		if arg("revoke_id") != "" {
+			output, err = s.tools.RevokeWaiver(arg("revoke_id"))
+			break
+		}
 		output, err = s.tools.CheckDecay(arg("deprecate"), arg("waive_id"), arg("waive_until"), arg("waive_rationale"))
+		if days, ok := arguments["forecast_days"].(float64); ok && err == nil && arg("deprecate") == "" && arg("waive_id") == "" {
+			var forecast string
+			if forecast, err = s.tools.ForecastDecay(int(days)); err == nil {
+				output += "\n---\n\n" + forecast
+			}
+		}
 
 	default:
-		err = fmt.Errorf("unknown tool: %s", params.Name)
+		err = fmt.Errorf("unknown tool: %s", name)
 	}
 
 	if err != nil {
-		s.sendResult(req.ID, CallToolResult{
+		return CallToolResult{
 			Content: []ContentItem{{Type: "text", Text: err.Error()}},
 			IsError: true,
-		})
-	} else {
-		s.sendResult(req.ID, CallToolResult{
-			Content: []ContentItem{{Type: "text", Text: output}},
-		})
+		}
+	}
+	return CallToolResult{
+		Content: []ContentItem{{Type: "text", Text: output}},
+	}
+}
+
+func stringSlice(v interface{}) []string {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
 	}
+	return result
 }
@@ -0,0 +1,316 @@
+package fpf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// serve runs the server over the given newline-delimited input and returns every message written back
+func serve(t *testing.T, s *Server, input string) []map[string]interface{} {
+	t.Helper()
+	var out bytes.Buffer
+	if err := s.Serve(strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	var messages []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var msg map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("server wrote invalid JSON %q: %v", line, err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+func errorCode(msg map[string]interface{}) int {
+	e, ok := msg["error"].(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	code, _ := e["code"].(float64)
+	return int(code)
+}
+
+func TestServer_NegotiatesProtocolVersion(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	tests := []struct {
+		requested string
+		expected  string
+	}{
+		{"2024-11-05", "2024-11-05"},
+		{"2025-03-26", "2025-03-26"},
+		{"1999-01-01", ProtocolVersions[0]},
+	}
+
+	for _, tt := range tests {
+		s := NewServer(tools)
+		input := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"` + tt.requested + `","capabilities":{},"clientInfo":{"name":"test","version":"0"}}}` + "\n"
+		messages := serve(t, s, input)
+		if len(messages) != 1 {
+			t.Fatalf("expected 1 response, got %d", len(messages))
+		}
+		result := messages[0]["result"].(map[string]interface{})
+		if result["protocolVersion"] != tt.expected {
+			t.Errorf("requested %s: expected %s, got %v", tt.requested, tt.expected, result["protocolVersion"])
+		}
+		if s.ProtocolVersion() != tt.expected {
+			t.Errorf("server kept %q, expected %q", s.ProtocolVersion(), tt.expected)
+		}
+	}
+}
+
+func TestServer_PingAndNotifications(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	s := NewServer(tools)
+
+	input := `{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n" +
+		`{"jsonrpc":"2.0","id":"p1","method":"ping"}` + "\n"
+	messages := serve(t, s, input)
+
+	if len(messages) != 1 {
+		t.Fatalf("expected only the ping response, got %d messages", len(messages))
+	}
+	if messages[0]["id"] != "p1" {
+		t.Errorf("expected id p1, got %v", messages[0]["id"])
+	}
+	if _, ok := messages[0]["result"].(map[string]interface{}); !ok {
+		t.Errorf("expected empty result object, got %v", messages[0])
+	}
+}
+
+func TestServer_LargeMessage(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	s := NewServer(tools)
+
+	vocabulary := strings.Repeat("term ", 40000) // ~200KB, well past bufio.Scanner's 64KB limit
+	input := `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"quint_record_context","arguments":{"vocabulary":"` + vocabulary + `","invariants":"none"}}}` + "\n"
+	messages := serve(t, s, input)
+
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 response, got %d", len(messages))
+	}
+	result, ok := messages[0]["result"].(map[string]interface{})
+	if !ok || result["isError"] == true {
+		t.Fatalf("expected successful tool result, got %v", messages[0])
+	}
+}
+
+func TestServer_Batch(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	s := NewServer(tools)
+
+	input := `[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":2,"method":"tools/list"},{"jsonrpc":"2.0","id":3,"method":"nope"}]` + "\n"
+
+	var out bytes.Buffer
+	if err := s.Serve(strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	var responses []map[string]interface{}
+	if err := json.Unmarshal(bytes.TrimSpace(out.Bytes()), &responses); err != nil {
+		t.Fatalf("expected a JSON array response, got %q: %v", out.String(), err)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("expected 3 responses (notification omitted), got %d", len(responses))
+	}
+	if errorCode(responses[2]) != CodeMethodNotFound {
+		t.Errorf("expected -32601 for unknown method, got %v", responses[2])
+	}
+
+	if resp := s.HandleMessage(context.Background(), []byte(`[]`), nil); !strings.Contains(string(resp), "-32600") {
+		t.Errorf("expected -32600 for empty batch, got %s", resp)
+	}
+}
+
+func TestServer_ProtocolErrors(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	s := NewServer(tools)
+
+	tests := []struct {
+		name    string
+		message string
+		code    int
+	}{
+		{"parse error", `{"jsonrpc":"2.0","id":1,`, CodeParseError},
+		{"missing method", `{"jsonrpc":"2.0","id":1}`, CodeInvalidRequest},
+		{"wrong version", `{"jsonrpc":"1.0","id":1,"method":"ping"}`, CodeInvalidRequest},
+		{"params not an object", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":[1,2]}`, CodeInvalidParams},
+		{"unknown tool", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"quint_nope"}}`, CodeInvalidParams},
+		{"missing required argument", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"quint_audit_tree","arguments":{}}}`, CodeInvalidParams},
+		{"wrong argument type", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"quint_calculate_r","arguments":{"holon_id":42}}}`, CodeInvalidParams},
+		{"enum violation", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"quint_context","arguments":{"action":"delete"}}}`, CodeInvalidParams},
+		{"out of range", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"quint_propose","arguments":{"title":"t","content":"c","scope":"s","kind":"system","rationale":"r","dependency_cl":5}}}`, CodeInvalidParams},
+		{"array item type", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"quint_propose","arguments":{"title":"t","content":"c","scope":"s","kind":"system","rationale":"r","depends_on":[1]}}}`, CodeInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var msg map[string]interface{}
+			resp := s.HandleMessage(context.Background(), []byte(tt.message), nil)
+			if err := json.Unmarshal(resp, &msg); err != nil {
+				t.Fatalf("invalid response %q: %v", resp, err)
+			}
+			if errorCode(msg) != tt.code {
+				t.Errorf("expected code %d, got %v", tt.code, msg)
+			}
+		})
+	}
+}
+
+func TestServer_ProgressNotifications(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	s := NewServer(tools)
+
+	var notifications []JSONRPCNotification
+	notify := func(msg interface{}) {
+		if n, ok := msg.(JSONRPCNotification); ok {
+			notifications = append(notifications, n)
+		}
+	}
+
+	resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"quint_status","arguments":{},"_meta":{"progressToken":"tok"}}}`), notify)
+	if !strings.Contains(string(resp), "context: default") {
+		t.Fatalf("unexpected response: %s", resp)
+	}
+
+	if len(notifications) != 2 {
+		t.Fatalf("expected start and finish progress notifications, got %d", len(notifications))
+	}
+	for _, n := range notifications {
+		if n.Method != "notifications/progress" {
+			t.Errorf("expected notifications/progress, got %s", n.Method)
+		}
+	}
+
+	notifications = nil
+	s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"quint_status","arguments":{}}}`), notify)
+	if len(notifications) != 0 {
+		t.Errorf("expected no progress without a progress token, got %d", len(notifications))
+	}
+}
+
+func TestServer_Cancellation(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	s := NewServer(tools)
+
+	// The cancellation overtakes its request, as it does when the request is still queued
+	s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":5,"reason":"user abort"}}`), nil)
+
+	if resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"quint_status","arguments":{}}}`), nil); resp != nil {
+		t.Errorf("expected no response for a cancelled request, got %s", resp)
+	}
+
+	if resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"quint_status","arguments":{}}}`), nil); resp == nil {
+		t.Error("expected a response for a request that was not cancelled")
+	}
+}
+
+// lockedBuffer lets a test read what Serve wrote while it is still running
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestServer_BusyQueue(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	s := NewServer(tools)
+
+	// A long-running call holds the tools, so every tools/call queues up
+	s.calls <- struct{}{}
+
+	calls := maxQueuedRequests + 3
+	var input strings.Builder
+	for i := 1; i <= calls; i++ {
+		fmt.Fprintf(&input, `{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"quint_status","arguments":{}}}`+"\n", i)
+	}
+	input.WriteString(`{"jsonrpc":"2.0","id":"p1","method":"ping"}` + "\n")
+
+	var out lockedBuffer
+	done := make(chan error)
+	go func() { done <- s.Serve(strings.NewReader(input.String()), &out) }()
+
+	// The reader answers the ping past the full queue instead of blocking
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), `"id":"p1"`) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the ping to be answered while the queue is full")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	<-s.calls
+	if err := <-done; err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	busy := 0
+	answered := make(map[float64]int)
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var msg map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("server wrote invalid JSON %q: %v", line, err)
+		}
+		if id, ok := msg["id"].(float64); ok {
+			answered[id]++
+		}
+		if errorCode(msg) == CodeServerBusy {
+			busy++
+		}
+	}
+	if busy == 0 {
+		t.Error("expected requests beyond the queue to be rejected as busy")
+	}
+	for i := 1; i <= calls; i++ {
+		if answered[float64(i)] != 1 {
+			t.Errorf("expected exactly one response to request %d, got %d", i, answered[float64(i)])
+		}
+	}
+}
+
+func TestServer_FollowsContextSwitch(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	s := NewServer(tools)
+	if _, err := tools.CreateContext("Caching", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// The CLI switches through its own Tools on the same database
+	fsm, err := LoadState(DefaultContextID, tools.DB.GetRawDB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewTools(fsm, tempDir, tools.DB).SwitchContext("caching"); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"quint_status","arguments":{}}}`), nil)
+	if !strings.Contains(string(resp), "context: caching") {
+		t.Errorf("expected the server to follow the switch, got %s", resp)
+	}
+	if tools.ContextID() != "caching" {
+		t.Errorf("expected tools to write to caching, got %s", tools.ContextID())
+	}
+}
@@ -3,6 +3,7 @@ package fpf
 import (
 	"context"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -10,7 +11,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/m0n0x41d/quint-code/assurance"
@@ -25,6 +28,18 @@ type Tools struct {
 	FSM     *FSM
 	RootDir string
 	DB      *db.Store
+
+	uow *unitOfWork // set while a transact call is running
+
+	configMu sync.Mutex
+	config   *cachedConfig // last config.json read, reused while the file is unchanged
+}
+
+// cachedConfig is a parsed config.json with the file state it was read from
+type cachedConfig struct {
+	modTime time.Time
+	size    int64
+	cfg     assurance.Config
 }
 
 func NewTools(fsm *FSM, rootDir string, database *db.Store) *Tools {
@@ -37,17 +52,64 @@ func NewTools(fsm *FSM, rootDir string, database *db.Store) *Tools {
 		}
 	}
 
-	return &Tools{
+	t := &Tools{
 		FSM:     fsm,
 		RootDir: rootDir,
 		DB:      database,
 	}
+	t.loadAssuranceConfig()
+	return t
 }
 
 func (t *Tools) GetFPFDir() string {
 	return filepath.Join(t.RootDir, ".quint")
 }
 
+// ConfigPath returns the per-project settings file
+func (t *Tools) ConfigPath() string {
+	return filepath.Join(t.GetFPFDir(), "config.json")
+}
+
+// loadAssuranceConfig reads .quint/config.json and shares it, with the project
+// root, with the FSM gate. The file is only parsed again when its mtime or size
+// changes, so an invalid config is reported once and replaced by the defaults.
+func (t *Tools) loadAssuranceConfig() assurance.Config {
+	var modTime time.Time
+	size := int64(-1)
+	if info, err := os.Stat(t.ConfigPath()); err == nil {
+		modTime, size = info.ModTime(), info.Size()
+	}
+
+	t.configMu.Lock()
+	cached := t.config
+	if cached == nil || !cached.modTime.Equal(modTime) || cached.size != size {
+		cfg, err := assurance.LoadConfig(t.ConfigPath())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v (using defaults)\n", err)
+		}
+		cached = &cachedConfig{modTime: modTime, size: size, cfg: cfg}
+		t.config = cached
+	}
+	t.configMu.Unlock()
+
+	cfg := cached.cfg
+	if t.FSM != nil {
+		t.FSM.AssuranceConfig = cfg
+		t.FSM.RootDir = t.RootDir
+	}
+	return cfg
+}
+
+// newCalculator creates an assurance calculator with the current project config
+func (t *Tools) newCalculator() *assurance.Calculator {
+	calc, err := assurance.NewFromConfig(t.DB.GetRawDB(), t.loadAssuranceConfig())
+	if err != nil {
+		calc = assurance.New(t.DB.GetRawDB())
+	}
+	calc.RootDir = t.RootDir
+	return calc
+}
+
 func (t *Tools) AuditLog(toolName, operation, actor, targetID, result string, input interface{}, details string) {
 	if t.DB == nil {
 		return
@@ -64,7 +126,7 @@ func (t *Tools) AuditLog(toolName, operation, actor, targetID, result string, in
 
 	id := uuid.New().String()
 	ctx := context.Background()
-	if err := t.DB.InsertAuditLog(ctx, id, toolName, operation, actor, targetID, inputHash, result, details, "default"); err != nil {
+	if err := t.DB.InsertAuditLog(ctx, id, toolName, operation, actor, targetID, inputHash, result, details, t.ContextID()); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to insert audit log: %v\n", err)
 	}
 }
@@ -78,23 +140,31 @@ func (t *Tools) MoveHypothesis(hypothesisID, sourceLevel, destLevel string) (str
 	srcPath := filepath.Join(t.GetFPFDir(), "knowledge", sourceLevel, hypothesisID+".md")
 	destPath := filepath.Join(t.GetFPFDir(), "knowledge", destLevel, hypothesisID+".md")
 
+	if err := t.checkHolonContext(hypothesisID); err != nil {
+		t.AuditLog("quint_move", "move_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"from": sourceLevel, "to": destLevel}, err.Error())
+		return "", err
+	}
 	if _, err := os.Stat(srcPath); os.IsNotExist(err) {
 		t.AuditLog("quint_move", "move_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"from": sourceLevel, "to": destLevel}, "not found")
 		return "", fmt.Errorf("hypothesis %s not found in %s", hypothesisID, sourceLevel)
 	}
 
-	if err := os.Rename(srcPath, destPath); err != nil {
-		t.AuditLog("quint_move", "move_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"from": sourceLevel, "to": destLevel}, err.Error())
-		return "", fmt.Errorf("failed to move hypothesis from %s to %s: %v", sourceLevel, destLevel, err)
-	}
-
-	if t.DB != nil {
-		if err := t.DB.UpdateHolonLayer(context.Background(), hypothesisID, destLevel); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to update holon layer in DB: %v\n", err)
+	err := t.transact(func() error {
+		if err := t.renameFile(srcPath, destPath); err != nil {
+			return fmt.Errorf("failed to move hypothesis from %s to %s: %v", sourceLevel, destLevel, err)
+		}
+		if t.DB != nil {
+			if err := t.DB.UpdateHolonLayer(context.Background(), hypothesisID, destLevel); err != nil {
+				return fmt.Errorf("failed to update holon layer in DB: %v", err)
+			}
 		}
+		t.AuditLog("quint_move", "move_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"from": sourceLevel, "to": destLevel}, "")
+		return nil
+	})
+	if err != nil {
+		t.AuditLog("quint_move", "move_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"from": sourceLevel, "to": destLevel}, err.Error())
+		return "", err
 	}
-
-	t.AuditLog("quint_move", "move_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"from": sourceLevel, "to": destLevel}, "")
 	return destPath, nil
 }
 
@@ -108,6 +178,7 @@ func (t *Tools) InitProject() error {
 		"knowledge/L2",
 		"knowledge/invalid",
 		"agents",
+		"waivers",
 	}
 
 	for _, d := range dirs {
@@ -141,7 +212,10 @@ func (t *Tools) RecordContext(vocabulary, invariants string) (string, error) {
 	invFormatted := formatInvariants(invariants)
 
 	content := fmt.Sprintf("# Bounded Context\n\n## Vocabulary\n\n%s\n\n## Invariants\n\n%s\n", vocabFormatted, invFormatted)
-	path := filepath.Join(t.GetFPFDir(), "context.md")
+	if err := t.ensureContextDir(); err != nil {
+		return "", err
+	}
+	path := t.ContextFilePath()
 
 	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		return "", err
@@ -255,66 +329,141 @@ func (t *Tools) ProposeHypothesis(title, content, scope, kind, rationale string,
 
 	body := fmt.Sprintf("\n# Hypothesis: %s\n\n%s\n\n## Rationale\n%s", title, content, rationale)
 	fields := map[string]string{
-		"scope": scope,
-		"kind":  kind,
-	}
-
-	if err := WriteWithHash(path, fields, body); err != nil {
-		t.AuditLog("quint_propose", "create_hypothesis", "agent", slug, "ERROR", map[string]string{"title": title, "kind": kind}, err.Error())
-		return "", err
+		"scope":   scope,
+		"kind":    kind,
+		"context": t.ContextID(),
 	}
 
-	if t.DB != nil {
-		if err := t.DB.CreateHolon(context.Background(), slug, "hypothesis", kind, "L0", title, body, "default", scope, ""); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to create holon in DB: %v\n", err)
+	err := t.transact(func() error {
+		if t.DB == nil {
+			return t.writeProjection(path, fields, body)
 		}
-	}
 
-	ctx := context.Background()
-
-	if decisionContext != "" && t.DB != nil {
-		if _, err := t.DB.GetHolon(ctx, decisionContext); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: decision_context '%s' not found, skipping MemberOf\n", decisionContext)
-		} else {
-			if err := t.createRelation(ctx, slug, "memberOf", decisionContext, 3); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to create MemberOf relation: %v\n", err)
-			}
+		ctx := context.Background()
+		// IDs and knowledge files are shared by all contexts
+		if existing, err := t.DB.GetHolon(ctx, slug); err == nil && existing.ContextID != t.ContextID() {
+			return fmt.Errorf("hypothesis ID %s is already used in context %s; holon IDs are shared across contexts, so choose a different title", slug, existing.ContextID)
 		}
-	}
-
-	if len(dependsOn) > 0 && t.DB != nil {
-		if dependencyCL < 1 || dependencyCL > 3 {
-			dependencyCL = 3
+		if err := t.checkHolonContext(append([]string{decisionContext}, dependsOn...)...); err != nil {
+			return err
+		}
+		if err := t.DB.CreateHolon(ctx, slug, "hypothesis", kind, "L0", title, body, t.ContextID(), scope, ""); err != nil {
+			return fmt.Errorf("failed to create holon in DB: %v", err)
 		}
 
-		relationType := "componentOf"
-		if kind == "episteme" {
-			relationType = "constituentOf"
+		if decisionContext != "" {
+			if _, err := t.DB.GetHolon(ctx, decisionContext); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: decision_context '%s' not found, skipping MemberOf\n", decisionContext)
+			} else if err := t.createRelation(ctx, slug, "memberOf", decisionContext, 3); err != nil {
+				return fmt.Errorf("failed to create MemberOf relation: %v", err)
+			}
 		}
 
-		for _, depID := range dependsOn {
-			if _, err := t.DB.GetHolon(ctx, depID); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: dependency '%s' not found, skipping\n", depID)
-				continue
+		if len(dependsOn) > 0 {
+			if dependencyCL < 1 || dependencyCL > 3 {
+				dependencyCL = 3
 			}
 
-			if cyclic, _ := t.wouldCreateCycle(ctx, depID, slug); cyclic {
-				fmt.Fprintf(os.Stderr, "Warning: dependency on '%s' would create cycle, skipping\n", depID)
-				continue
+			relationType := "componentOf"
+			if kind == "episteme" {
+				relationType = "constituentOf"
 			}
 
-			if err := t.createRelation(ctx, depID, relationType, slug, dependencyCL); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to create %s relation to %s: %v\n",
-					relationType, depID, err)
+			for _, depID := range dependsOn {
+				if _, err := t.DB.GetHolon(ctx, depID); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: dependency '%s' not found, skipping\n", depID)
+					continue
+				}
+
+				if cyclic, _ := t.wouldCreateCycle(ctx, depID, slug); cyclic {
+					fmt.Fprintf(os.Stderr, "Warning: dependency on '%s' would create cycle, skipping\n", depID)
+					continue
+				}
+
+				if err := t.createRelation(ctx, depID, relationType, slug, dependencyCL); err != nil {
+					return fmt.Errorf("failed to create %s relation to %s: %v", relationType, depID, err)
+				}
 			}
 		}
-	}
 
-	t.AuditLog("quint_propose", "create_hypothesis", "agent", slug, "SUCCESS", map[string]string{"title": title, "kind": kind, "scope": scope}, "")
+		// Rendered from the DB so the frontmatter records the relations just created
+		if err := t.writeHolonProjection(ctx, slug); err != nil {
+			return err
+		}
+
+		t.AuditLog("quint_propose", "create_hypothesis", "agent", slug, "SUCCESS", map[string]string{"title": title, "kind": kind, "scope": scope}, "")
+		return nil
+	})
+	if err != nil {
+		t.AuditLog("quint_propose", "create_hypothesis", "agent", slug, "ERROR", map[string]string{"title": title, "kind": kind}, err.Error())
+		return "", err
+	}
 
 	return path, nil
 }
 
+// RecordClaim stores Formality (F) and ClaimScope (G) for a holon.
+// An empty claimScope leaves the claim unbounded.
+func (t *Tools) RecordClaim(holonID string, formality int, claimScope []string) error {
+	if formality < 0 || formality > assurance.MaxFormality {
+		return fmt.Errorf("formality must be between 0 and %d, got %d", assurance.MaxFormality, formality)
+	}
+	if t.DB == nil {
+		return fmt.Errorf("DB not initialized")
+	}
+
+	ctx := context.Background()
+	g := assurance.NewClaimScope(claimScope)
+	// The row and its projection change together
+	err := t.transact(func() error {
+		if err := t.DB.UpdateHolonClaim(ctx, holonID, formality, g.Encode()); err != nil {
+			return fmt.Errorf("failed to record claim for %s: %v", holonID, err)
+		}
+		if err := t.writeHolonProjection(ctx, holonID); err != nil {
+			return fmt.Errorf("failed to project claim of %s: %v", holonID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	t.AuditLog("quint_propose", "record_claim", "agent", holonID, "SUCCESS",
+		map[string]string{"formality": fmt.Sprintf("%d", formality), "claim_scope": g.String()}, "")
+	return nil
+}
+
+// RecordEvidenceFormality stores the Formality (F) of an evidence item
+func (t *Tools) RecordEvidenceFormality(evidenceID string, formality int) error {
+	return t.transact(func() error {
+		return t.recordEvidenceFormality(context.Background(), evidenceID, formality)
+	})
+}
+
+func (t *Tools) recordEvidenceFormality(ctx context.Context, id string, formality int) error {
+	if formality < 0 || formality > assurance.MaxFormality {
+		return fmt.Errorf("formality must be between 0 and %d, got %d", assurance.MaxFormality, formality)
+	}
+	if t.DB == nil {
+		return fmt.Errorf("DB not initialized")
+	}
+	if err := t.DB.UpdateEvidenceFormality(ctx, id, formality); err != nil {
+		return err
+	}
+	e, err := t.DB.GetEvidenceByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("evidence %s not found: %v", id, err)
+	}
+	fields, body := evidenceProjection(e)
+	return t.writeProjection(filepath.Join(t.GetFPFDir(), "evidence", id), fields, body)
+}
+
+// evidenceFileName names new evidence by today's date. It is called once per
+// record; later steps must use the returned ID, not rebuild it.
+func evidenceFileName(evidenceType, targetID string) string {
+	return fmt.Sprintf("%s-%s-%s.md", time.Now().Format("2006-01-02"), evidenceType, targetID)
+}
+
 func (t *Tools) createRelation(ctx context.Context, sourceID, relationType, targetID string, cl int) error {
 	if sourceID == targetID {
 		return fmt.Errorf("holon cannot relate to itself")
@@ -359,86 +508,48 @@ func (t *Tools) isReachable(ctx context.Context, from, to string, visited map[st
 	return false, nil
 }
 
-func (t *Tools) VerifyHypothesis(hypothesisID, checksJSON, verdict string) (string, error) {
-	defer t.RecordWork("VerifyHypothesis", time.Now())
-
-	carrierRef := "internal-logic"
-	if t.DB != nil {
-		holon, err := t.DB.GetHolon(context.Background(), hypothesisID)
-		if err == nil && holon.Kind.Valid {
-			switch holon.Kind.String {
-			case "system":
-				carrierRef = "internal-logic"
-			case "episteme":
-				carrierRef = "formal-logic"
-			}
-		}
-	}
-
-	switch strings.ToLower(verdict) {
-	case "pass":
-		_, err := t.MoveHypothesis(hypothesisID, "L0", "L1")
-		if err != nil {
-			t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"verdict": verdict}, err.Error())
-			return "", err
-		}
-
-		evidenceContent := fmt.Sprintf("Verification Checks:\n%s", checksJSON)
-		if _, err := t.ManageEvidence(PhaseDeduction, "add", hypothesisID, "verification", evidenceContent, "pass", "L1", carrierRef, ""); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to record verification evidence for %s: %v\n", hypothesisID, err)
-		}
-
-		t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"verdict": "PASS", "result": "L1"}, "")
-		return fmt.Sprintf("Hypothesis %s (kind: %s) promoted to L1", hypothesisID, carrierRef), nil
-	case "fail":
-		_, err := t.MoveHypothesis(hypothesisID, "L0", "invalid")
-		if err != nil {
-			t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"verdict": verdict}, err.Error())
-			return "", err
-		}
-		t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"verdict": "FAIL", "result": "invalid"}, "")
-		return fmt.Sprintf("Hypothesis %s moved to invalid", hypothesisID), nil
-	case "refine":
-		t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"verdict": "REFINE", "result": "L0"}, "")
-		return fmt.Sprintf("Hypothesis %s requires refinement (staying in L0)", hypothesisID), nil
-	default:
-		return "", fmt.Errorf("unknown verdict: %s", verdict)
-	}
-}
-
 func (t *Tools) AuditEvidence(hypothesisID, risks string) (string, error) {
 	defer t.RecordWork("AuditEvidence", time.Now())
-	_, err := t.ManageEvidence(PhaseDecision, "add", hypothesisID, "audit_report", risks, "pass", "L2", "auditor", "")
+	_, _, err := t.ManageEvidence(PhaseDecision, "add", hypothesisID, "audit_report", risks, "pass", "L2", "auditor", "")
 	return "Audit recorded for " + hypothesisID, err
 }
 
-func (t *Tools) ManageEvidence(currentPhase Phase, action, targetID, evidenceType, content, verdict, assuranceLevel, carrierRef, validUntil string) (string, error) {
+// ManageEvidence adds evidence to a holon, moving it between layers as the verdict
+// demands, or lists its evidence ("check"). For "add" it also returns the ID of the
+// evidence it wrote.
+func (t *Tools) ManageEvidence(currentPhase Phase, action, targetID, evidenceType, content, verdict, assuranceLevel, carrierRef, validUntil string) (string, string, error) {
 	defer t.RecordWork("ManageEvidence", time.Now())
 
 	if validUntil == "" && action != "check" {
-		validUntil = time.Now().AddDate(0, 0, 90).Format("2006-01-02")
+		validUntil = defaultValidUntil()
 	}
 	ctx := context.Background()
 
+	if targetID != "all" {
+		if err := t.checkHolonContext(targetID); err != nil {
+			return "", "", err
+		}
+	}
+
 	if action == "check" {
 		if t.DB == nil {
-			return "", fmt.Errorf("DB not initialized")
+			return "", "", fmt.Errorf("DB not initialized")
 		}
 		if targetID == "all" {
-			return "Global evidence audit not implemented yet. Please specify a target_id.", nil
+			return "Global evidence audit not implemented yet. Please specify a target_id.", "", nil
 		}
 		ev, err := t.DB.GetEvidence(ctx, targetID)
 		if err != nil {
-			return "", err
+			return "", "", err
 		}
 		var report string
 		for _, e := range ev {
 			report += fmt.Sprintf("- [%s] %s (L:%s, Ref:%s): %s\n", e.Verdict, e.Type, e.AssuranceLevel.String, e.CarrierRef.String, e.Content)
 		}
 		if report == "" {
-			return "No evidence found for " + targetID, nil
+			return "No evidence found for " + targetID, "", nil
 		}
-		return report, nil
+		return report, "", nil
 	}
 
 	shouldPromote := false
@@ -459,65 +570,86 @@ func (t *Tools) ManageEvidence(currentPhase Phase, action, targetID, evidenceTyp
 		}
 	}
 
-	var moveErr error
-	if (normalizedVerdict == "pass") && shouldPromote {
-		switch currentPhase {
-		case PhaseDeduction:
-			_, moveErr = t.MoveHypothesis(targetID, "L0", "L1")
-		case PhaseInduction:
-			if _, err := os.Stat(filepath.Join(t.GetFPFDir(), "knowledge", "L0", targetID+".md")); err == nil {
-				return "", fmt.Errorf("hypothesis %s is still in L0: run /q2-verify to promote it to L1 before testing", targetID)
+	if (normalizedVerdict == "pass") && shouldPromote && currentPhase == PhaseInduction {
+		if _, err := os.Stat(filepath.Join(t.GetFPFDir(), "knowledge", "L0", targetID+".md")); err == nil {
+			return "", "", fmt.Errorf("hypothesis %s is still in L0: run /q2-verify to promote it to L1 before testing", targetID)
+		}
+	}
+
+	id := evidenceFileName(evidenceType, targetID)
+	var path string
+
+	// The layer move and the evidence record succeed or fail together
+	err := t.transact(func() error {
+		var moveErr error
+		if (normalizedVerdict == "pass") && shouldPromote {
+			switch currentPhase {
+			case PhaseDeduction:
+				_, moveErr = t.MoveHypothesis(targetID, "L0", "L1")
+			case PhaseInduction:
+				_, moveErr = t.MoveHypothesis(targetID, "L1", "L2")
+			}
+		} else if normalizedVerdict == "fail" || normalizedVerdict == "refine" {
+			switch currentPhase {
+			case PhaseDeduction:
+				_, moveErr = t.MoveHypothesis(targetID, "L0", "invalid")
+			case PhaseInduction:
+				_, moveErr = t.MoveHypothesis(targetID, "L1", "invalid")
 			}
-			_, moveErr = t.MoveHypothesis(targetID, "L1", "L2")
 		}
-	} else if normalizedVerdict == "fail" || normalizedVerdict == "refine" {
-		switch currentPhase {
-		case PhaseDeduction:
-			_, moveErr = t.MoveHypothesis(targetID, "L0", "invalid")
-		case PhaseInduction:
-			_, moveErr = t.MoveHypothesis(targetID, "L1", "invalid")
+		if moveErr != nil {
+			return fmt.Errorf("failed to move hypothesis: %v", moveErr)
 		}
-	}
 
-	if moveErr != nil {
-		return "", fmt.Errorf("failed to move hypothesis: %v", moveErr)
+		var err error
+		path, err = t.recordEvidence(id, targetID, evidenceType, content, normalizedVerdict, assuranceLevel, carrierRef, validUntil)
+		return err
+	})
+	if err != nil {
+		return "", "", err
 	}
 
-	date := time.Now().Format("2006-01-02")
-	filename := fmt.Sprintf("%s-%s-%s.md", date, evidenceType, targetID)
-	path := filepath.Join(t.GetFPFDir(), "evidence", filename)
+	if !shouldPromote && verdict == "PASS" {
+		return path + " (Evidence recorded, but Assurance Level insufficient for promotion)", id, nil
+	}
+	return path, id, nil
+}
 
-	body := fmt.Sprintf("\n%s", content)
+// recordEvidence writes an evidence file and its row, linked to the target by verifiedBy.
+// It does not move the target; callers run it inside their unit of work.
+func (t *Tools) recordEvidence(id, targetID, evidenceType, content, verdict, assuranceLevel, carrierRef, validUntil string) (string, error) {
+	path := filepath.Join(t.GetFPFDir(), "evidence", id)
 	fields := map[string]string{
-		"id":              filename,
+		"id":              id,
 		"type":            evidenceType,
 		"target":          targetID,
-		"verdict":         normalizedVerdict,
+		"verdict":         verdict,
 		"assurance_level": assuranceLevel,
 		"carrier_ref":     carrierRef,
 		"valid_until":     validUntil,
-		"date":            date,
+		"date":            time.Now().Format("2006-01-02"),
 	}
-
-	if err := WriteWithHash(path, fields, body); err != nil {
+	if err := t.writeProjection(path, fields, "\n"+content); err != nil {
 		return "", err
 	}
 
 	if t.DB != nil {
-		if err := t.DB.AddEvidence(ctx, filename, targetID, evidenceType, content, normalizedVerdict, assuranceLevel, carrierRef, validUntil); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to add evidence to DB: %v\n", err)
+		ctx := context.Background()
+		if err := t.DB.AddEvidence(ctx, id, targetID, evidenceType, content, verdict, assuranceLevel, carrierRef, validUntil); err != nil {
+			return "", fmt.Errorf("failed to add evidence to DB: %v", err)
 		}
-		if err := t.DB.Link(ctx, filename, targetID, "verifiedBy"); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to link evidence in DB: %v\n", err)
+		if err := t.DB.Link(ctx, id, targetID, "verifiedBy"); err != nil {
+			return "", fmt.Errorf("failed to link evidence in DB: %v", err)
 		}
 	}
-
-	if !shouldPromote && verdict == "PASS" {
-		return path + " (Evidence recorded, but Assurance Level insufficient for promotion)", nil
-	}
 	return path, nil
 }
 
+// defaultValidUntil is the expiry of evidence recorded without one
+func defaultValidUntil() string {
+	return time.Now().AddDate(0, 0, 90).Format("2006-01-02")
+}
+
 func (t *Tools) RefineLoopback(currentPhase Phase, parentID, insight, newTitle, newContent, scope string) (string, error) {
 	defer t.RecordWork("RefineLoopback", time.Now())
 
@@ -531,34 +663,195 @@ func (t *Tools) RefineLoopback(currentPhase Phase, parentID, insight, newTitle,
 		return "", fmt.Errorf("loopback not applicable from phase %s", currentPhase)
 	}
 
-	if _, err := t.MoveHypothesis(parentID, parentLevel, "invalid"); err != nil {
-		return "", fmt.Errorf("failed to move parent hypothesis to invalid: %v", err)
+	childID := t.Slugify(newTitle)
+	if childID == parentID {
+		return "", fmt.Errorf("refined hypothesis needs a title different from its parent %s", parentID)
 	}
 
-	rationale := fmt.Sprintf(`{"source": "loopback", "parent_id": "%s", "insight": "%s"}`, parentID, insight)
-	childPath, err := t.ProposeHypothesis(newTitle, newContent, scope, "system", rationale, "", nil, 3)
-	if err != nil {
-		return "", fmt.Errorf("failed to create child hypothesis: %v", err)
+	// The child inherits kind and scope from the parent when it is tracked in the DB
+	ctx := context.Background()
+	kind := "system"
+	parentInDB := false
+	if t.DB != nil {
+		if parent, err := t.DB.GetHolon(ctx, parentID); err == nil {
+			parentInDB = true
+			if parent.Kind.Valid && parent.Kind.String != "" {
+				kind = parent.Kind.String
+			}
+			if scope == "" && parent.Scope.Valid {
+				scope = parent.Scope.String
+			}
+		}
 	}
 
-	logFile := filepath.Join(t.GetFPFDir(), "sessions", fmt.Sprintf("loopback-%d.md", time.Now().Unix()))
-	logContent := fmt.Sprintf("# Loopback Event\n\nParent: %s (moved to invalid)\nInsight: %s\nChild: %s\n", parentID, insight, childPath)
-	if err := os.WriteFile(logFile, []byte(logContent), 0644); err != nil {
-		return "", fmt.Errorf("failed to write loopback log file: %v", err)
+	// The parent's invalidation, the child and the loopback record form one unit of work
+	var childPath string
+	err := t.transact(func() error {
+		// A REFINE verdict from quint_test has already invalidated the parent
+		invalidPath := filepath.Join(t.GetFPFDir(), "knowledge", "invalid", parentID+".md")
+		if _, err := os.Stat(invalidPath); os.IsNotExist(err) {
+			if _, err := t.MoveHypothesis(parentID, parentLevel, "invalid"); err != nil {
+				return fmt.Errorf("failed to move parent hypothesis to invalid: %v", err)
+			}
+		}
+
+		rationale := fmt.Sprintf(`{"source": "loopback", "parent_id": "%s", "insight": "%s"}`, parentID, insight)
+		var err error
+		childPath, err = t.ProposeHypothesis(newTitle, newContent, scope, kind, rationale, "", nil, 3)
+		if err != nil {
+			return fmt.Errorf("failed to create child hypothesis: %v", err)
+		}
+
+		if parentInDB {
+			if err := t.recordLoopback(ctx, currentPhase, parentID, childID, insight); err != nil {
+				return err
+			}
+		}
+
+		logFile := filepath.Join(t.GetFPFDir(), "sessions", fmt.Sprintf("loopback-%d.md", time.Now().Unix()))
+		logContent := fmt.Sprintf("# Loopback Event\n\nParent: %s (moved to invalid)\nInsight: %s\nChild: %s\n", parentID, insight, childPath)
+		if err := t.writeFile(logFile, []byte(logContent)); err != nil {
+			return fmt.Errorf("failed to write loopback log file: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
 
 	return childPath, nil
 }
 
+// recordLoopback links the child to its parent, carries over the parent's
+// dependencies and decision context memberships, and stores the loopback so
+// the lineage survives without the session log.
+func (t *Tools) recordLoopback(ctx context.Context, phase Phase, parentID, childID, insight string) error {
+	if err := t.DB.SetHolonParent(ctx, childID, parentID); err != nil {
+		return fmt.Errorf("failed to link %s to parent %s: %v", childID, parentID, err)
+	}
+
+	relations, err := t.DB.GetHolonRelations(ctx, parentID)
+	if err != nil {
+		return fmt.Errorf("failed to query relations of %s: %v", parentID, err)
+	}
+	for _, r := range relations {
+		cl := 3
+		if r.CongruenceLevel.Valid {
+			cl = int(r.CongruenceLevel.Int64)
+		}
+
+		var err error
+		switch {
+		case r.TargetID == parentID && (r.RelationType == "componentOf" || r.RelationType == "constituentOf"):
+			err = t.createRelation(ctx, r.SourceID, r.RelationType, childID, cl)
+		case r.SourceID == parentID && (r.RelationType == "dependsOn" || r.RelationType == "memberOf"):
+			err = t.createRelation(ctx, childID, r.RelationType, r.TargetID, cl)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to carry %s relation over to %s: %v", r.RelationType, childID, err)
+		}
+	}
+
+	if err := t.DB.CreateLoopback(ctx, uuid.New().String(), parentID, childID, insight, string(phase), t.ContextID()); err != nil {
+		return fmt.Errorf("failed to record loopback: %v", err)
+	}
+	if err := t.writeHolonProjection(ctx, childID); err != nil {
+		return fmt.Errorf("failed to project lineage of %s: %v", childID, err)
+	}
+
+	t.AuditLog("quint_refine", "loopback", "agent", childID, "SUCCESS",
+		map[string]string{"parent_id": parentID, "insight": insight, "phase": string(phase)}, "")
+	return nil
+}
+
+// RefineHypothesis replaces a hypothesis that needs refinement with a child
+// hypothesis. The loopback phase follows the parent's layer: L0 parents loop
+// back from deduction, L1 parents (or ones a REFINE verdict already moved to
+// invalid) from induction.
+func (t *Tools) RefineHypothesis(parentID, insight, title, content, scope string) (string, error) {
+	if err := t.checkHolonContext(parentID); err != nil {
+		return "", err
+	}
+	phase := t.FSM.State.Phase
+	if phase != PhaseDeduction {
+		phase = PhaseInduction
+	}
+	if t.DB != nil {
+		if parent, err := t.DB.GetHolon(context.Background(), parentID); err == nil {
+			switch parent.Layer {
+			case "L0":
+				phase = PhaseDeduction
+			case "L1":
+				phase = PhaseInduction
+			}
+		}
+	}
+
+	childPath, err := t.RefineLoopback(phase, parentID, insight, title, content, scope)
+	if err != nil {
+		return "", err
+	}
+
+	childID := t.Slugify(title)
+	result := fmt.Sprintf("Hypothesis %s moved to invalid and refined into %s (%s)", parentID, childID, childPath)
+	if t.DB != nil {
+		if lineage, err := t.Lineage(childID); err == nil {
+			result += "\n\n" + lineage
+		}
+	}
+	return result, nil
+}
+
+// Lineage renders the chain of refinements that led to a holon, oldest first,
+// with the insight recorded for each loopback.
+func (t *Tools) Lineage(holonID string) (string, error) {
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+
+	ctx := context.Background()
+	lineage, err := t.DB.GetHolonLineage(ctx, holonID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load lineage of %s: %v", holonID, err)
+	}
+	if len(lineage) == 0 {
+		return "", fmt.Errorf("holon %s not found", holonID)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Lineage of %s\n\n", holonID))
+	for i, h := range lineage {
+		if i > 0 {
+			if lb, err := t.DB.GetLoopbackByChild(ctx, h.ID); err == nil {
+				sb.WriteString(fmt.Sprintf("   ↳ refined (%s): %s\n", lb.Phase, lb.Insight))
+			}
+		}
+		sb.WriteString(fmt.Sprintf("%d. [%s] %s: %s\n", i+1, h.Layer, h.ID, h.Title))
+	}
+	return sb.String(), nil
+}
+
 func (t *Tools) FinalizeDecision(title, winnerID string, rejectedIDs []string, decisionContext, decision, rationale, consequences, characteristics string) (string, error) {
 	defer t.RecordWork("FinalizeDecision", time.Now())
+	if err := t.checkHolonContext(append([]string{winnerID}, rejectedIDs...)...); err != nil {
+		return "", err
+	}
 
 	body := fmt.Sprintf("\n# %s\n\n", title)
 	body += fmt.Sprintf("## Context\n%s\n\n", decisionContext)
 	body += fmt.Sprintf("## Decision\n**Selected Option:** %s\n\n%s\n\n", winnerID, decision)
 	body += fmt.Sprintf("## Rationale\n%s\n\n", rationale)
-	if characteristics != "" {
-		body += fmt.Sprintf("### Characteristic Space (C.16)\n%s\n\n", characteristics)
+	matrix := t.CharacteristicMatrix(winnerID, rejectedIDs)
+	if characteristics != "" || matrix != "" {
+		body += "### Characteristic Space (C.16)\n"
+		if characteristics != "" {
+			body += characteristics + "\n\n"
+		}
+		if matrix != "" {
+			body += matrix + "\n"
+		}
 	}
 	body += fmt.Sprintf("## Consequences\n%s\n", consequences)
 
@@ -570,43 +863,78 @@ func (t *Tools) FinalizeDecision(title, winnerID string, rejectedIDs []string, d
 	fields := map[string]string{
 		"type":      "DRR",
 		"winner_id": winnerID,
+		"context":   t.ContextID(),
 		"created":   now.Format(time.RFC3339),
 	}
-
-	if err := WriteWithHash(drrPath, fields, body); err != nil {
-		t.AuditLog("quint_decide", "finalize_decision", "agent", winnerID, "ERROR", map[string]string{"title": title}, err.Error())
-		return "", err
+	var rejected []string
+	for _, rejID := range rejectedIDs {
+		if rejID != "" && rejID != winnerID {
+			rejected = append(rejected, rejID)
+		}
+	}
+	if len(rejected) > 0 {
+		sort.Strings(rejected)
+		fields["rejected_ids"] = strings.Join(rejected, ", ")
+	}
+	// The decision covers the code its winner is anchored to
+	var anchors []string
+	if t.DB != nil && winnerID != "" {
+		if relations, err := t.DB.GetHolonRelations(context.Background(), winnerID); err == nil {
+			anchors = anchorsOf(winnerID, relations)
+		}
+	}
+	if len(anchors) > 0 {
+		fields["anchors"] = strings.Join(anchors, ", ")
 	}
 
-	if t.DB != nil {
-		ctx := context.Background()
-		drrID := t.Slugify(title)
-		if err := t.DB.CreateHolon(ctx, drrID, "DRR", "", "DRR", title, body, "default", "", winnerID); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to create DRR holon in DB: %v\n", err)
+	err := t.transact(func() error {
+		if err := t.writeProjection(drrPath, fields, body); err != nil {
+			return err
 		}
 
-		// Create selects relation: DRR → winner
-		if winnerID != "" {
-			if err := t.createRelation(ctx, drrID, "selects", winnerID, 3); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to create selects relation: %v\n", err)
+		if t.DB != nil {
+			ctx := context.Background()
+			drrID := t.Slugify(title)
+			if err := t.DB.CreateHolon(ctx, drrID, "DRR", "", "DRR", title, body, t.ContextID(), "", winnerID); err != nil {
+				return fmt.Errorf("failed to create DRR holon %s in DB: %v", drrID, err)
+			}
+
+			// Create selects relation: DRR → winner
+			if winnerID != "" {
+				if err := t.createRelation(ctx, drrID, "selects", winnerID, 3); err != nil {
+					return fmt.Errorf("failed to create selects relation: %v", err)
+				}
 			}
-		}
 
-		// Create rejects relations: DRR → each rejected alternative
-		for _, rejID := range rejectedIDs {
-			if rejID != "" && rejID != winnerID {
-				if err := t.createRelation(ctx, drrID, "rejects", rejID, 3); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to create rejects relation to %s: %v\n", rejID, err)
+			// Create rejects relations: DRR → each rejected alternative
+			for _, rejID := range rejectedIDs {
+				if rejID != "" && rejID != winnerID {
+					if err := t.createRelation(ctx, drrID, "rejects", rejID, 3); err != nil {
+						return fmt.Errorf("failed to create rejects relation to %s: %v", rejID, err)
+					}
+				}
+			}
+
+			for _, anchor := range anchors {
+				if err := t.createRelation(ctx, drrID, RelationAnchoredTo, anchor, 3); err != nil {
+					return fmt.Errorf("failed to anchor DRR to %s: %v", anchor, err)
 				}
 			}
 		}
-	}
 
-	if winnerID != "" {
-		_, err := t.MoveHypothesis(winnerID, "L1", "L2")
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "WARNING: Failed to move winner hypothesis %s to L2: %v\n", winnerID, err)
+		// Winners are usually in L2 already; only L1 winners get promoted
+		if winnerID != "" {
+			if _, err := os.Stat(filepath.Join(t.GetFPFDir(), "knowledge", "L1", winnerID+".md")); err == nil {
+				if _, err := t.MoveHypothesis(winnerID, "L1", "L2"); err != nil {
+					return fmt.Errorf("failed to move winner hypothesis %s to L2: %v", winnerID, err)
+				}
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		t.AuditLog("quint_decide", "finalize_decision", "agent", winnerID, "ERROR", map[string]string{"title": title}, err.Error())
+		return "", err
 	}
 
 	t.AuditLog("quint_decide", "finalize_decision", "agent", winnerID, "SUCCESS", map[string]string{"title": title, "drr": drrName}, "")
@@ -620,18 +948,18 @@ func (t *Tools) RunDecay() error {
 	}
 
 	ctx := context.Background()
-	ids, err := t.DB.ListAllHolonIDs(ctx)
+	holons, err := t.DB.ListHolonsByContext(ctx, t.ContextID())
 	if err != nil {
 		return err
 	}
 
-	calc := assurance.New(t.DB.GetRawDB())
+	calc := t.newCalculator()
 	updatedCount := 0
 
-	for _, id := range ids {
-		_, err := calc.CalculateReliability(ctx, id)
+	for _, h := range holons {
+		_, err := calc.CalculateReliability(ctx, h.ID)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error calculating R for %s: %v\n", id, err)
+			fmt.Fprintf(os.Stderr, "Error calculating R for %s: %v\n", h.ID, err)
 			continue
 		}
 		updatedCount++
@@ -650,9 +978,23 @@ func (t *Tools) VisualizeAudit(rootID string) (string, error) {
 	if rootID == "all" {
 		return "Please specify a root ID for the audit tree.", nil
 	}
+	if err := t.checkHolonContext(rootID); err != nil {
+		return "", err
+	}
+
+	calc := t.newCalculator()
+	tree, err := t.buildAuditTree(rootID, 0, calc)
+	if err != nil {
+		return "", err
+	}
 
-	calc := assurance.New(t.DB.GetRawDB())
-	return t.buildAuditTree(rootID, 0, calc)
+	// Refined hypotheses also show where they came from
+	if holon, err := t.DB.GetHolon(context.Background(), rootID); err == nil && holon.ParentID.Valid {
+		if lineage, err := t.Lineage(rootID); err == nil {
+			tree += "\n" + lineage
+		}
+	}
+	return tree, nil
 }
 
 func (t *Tools) buildAuditTree(holonID string, level int, calc *assurance.Calculator) (string, error) {
@@ -663,7 +1005,7 @@ func (t *Tools) buildAuditTree(holonID string, level int, calc *assurance.Calcul
 	}
 
 	indent := strings.Repeat("  ", level)
-	tree := fmt.Sprintf("%s[%s R:%.2f] %s\n", indent, holonID, report.FinalScore, t.getHolonTitle(holonID))
+	tree := fmt.Sprintf("%s[%s R:%.2f F:%d G:%s] %s\n", indent, holonID, report.FinalScore, report.Formality, report.ClaimScope, t.getHolonTitle(holonID))
 
 	if len(report.Factors) > 0 {
 		for _, f := range report.Factors {
@@ -716,7 +1058,17 @@ func (t *Tools) getHolonTitle(id string) string {
 	return title
 }
 
-func (t *Tools) Actualize() (string, error) {
+// DefaultImpactValidityDays caps the validity of evidence affected by code
+// changes when no ValidityDays is given
+const DefaultImpactValidityDays = 7
+
+// ActualizeOptions control how Actualize reacts to changed code
+type ActualizeOptions struct {
+	ShortenValidity bool // cap valid_until of evidence whose holon is anchored to changed code
+	ValidityDays    int  // days from now the shortened evidence stays valid
+}
+
+func (t *Tools) Actualize(opts ActualizeOptions) (string, error) {
 	var report strings.Builder
 	fpfDir := filepath.Join(t.RootDir, ".fpf")
 	quintDir := t.GetFPFDir()
@@ -756,7 +1108,7 @@ func (t *Tools) Actualize() (string, error) {
 		if lastCommit == "" {
 			report.WriteString(fmt.Sprintf("RECONCILIATION: Initializing baseline commit to %s\n", currentCommit))
 			t.FSM.State.LastCommit = currentCommit
-			if err := t.FSM.SaveState("default"); err != nil {
+			if err := t.FSM.SaveState(t.ContextID()); err != nil {
 				report.WriteString(fmt.Sprintf("Warning: Failed to save state: %v\n", err))
 			}
 		} else if currentCommit != lastCommit {
@@ -764,16 +1116,29 @@ func (t *Tools) Actualize() (string, error) {
 			diffCmd := exec.Command("git", "diff", "--name-status", lastCommit, "HEAD")
 			diffCmd.Dir = t.RootDir
 			diffOutput, err := diffCmd.Output()
+			checked := true
 			if err == nil {
 				report.WriteString("Changed files:\n")
 				report.WriteString(string(diffOutput))
+				if t.DB != nil {
+					if opts.ValidityDays <= 0 {
+						opts.ValidityDays = DefaultImpactValidityDays
+					}
+					if err := t.reportAnchorImpact(&report, lastCommit, currentCommit, parseNameStatus(string(diffOutput)), opts); err != nil {
+						// Keep the baseline so the next actualize checks these changes again
+						report.WriteString(fmt.Sprintf("Warning: %v; baseline stays at %s\n", err, lastCommit))
+						checked = false
+					}
+				}
 			} else {
 				report.WriteString(fmt.Sprintf("Warning: Failed to get diff: %v\n", err))
 			}
 
-			t.FSM.State.LastCommit = currentCommit
-			if err := t.FSM.SaveState("default"); err != nil {
-				report.WriteString(fmt.Sprintf("Warning: Failed to save state: %v\n", err))
+			if checked {
+				t.FSM.State.LastCommit = currentCommit
+				if err := t.FSM.SaveState(t.ContextID()); err != nil {
+					report.WriteString(fmt.Sprintf("Warning: Failed to save state: %v\n", err))
+				}
 			}
 		} else {
 			report.WriteString("RECONCILIATION: No changes detected (Clean).\n")
@@ -782,6 +1147,15 @@ func (t *Tools) Actualize() (string, error) {
 		report.WriteString("RECONCILIATION: Not a git repository or git error.\n")
 	}
 
+	if t.DB != nil {
+		drift, err := t.CheckContextDrift()
+		if err != nil {
+			report.WriteString(fmt.Sprintf("Warning: Failed to check context drift: %v\n", err))
+		} else {
+			report.WriteString(FormatDriftReport(drift))
+		}
+	}
+
 	return report.String(), nil
 }
 
@@ -797,16 +1171,22 @@ func (t *Tools) CalculateR(holonID string) (string, error) {
 	if t.DB == nil {
 		return "", fmt.Errorf("DB not initialized")
 	}
+	if err := t.checkHolonContext(holonID); err != nil {
+		return "", err
+	}
 
-	calc := assurance.New(t.DB.GetRawDB())
-	report, err := calc.CalculateReliability(context.Background(), holonID)
+	calc := t.newCalculator()
+	report, err := calc.CalculateAssurance(context.Background(), holonID)
 	if err != nil {
 		return "", err
 	}
 
 	var result strings.Builder
 	result.WriteString(fmt.Sprintf("## Reliability Report: %s\n\n", holonID))
+	result.WriteString(fmt.Sprintf("**Assurance: %s**\n", report.Tuple()))
 	result.WriteString(fmt.Sprintf("**R_eff: %.2f**\n", report.FinalScore))
+	result.WriteString(fmt.Sprintf("- Formality (F): F%d\n", report.Formality))
+	result.WriteString(fmt.Sprintf("- Claim Scope (G): %s\n", report.ClaimScope))
 	result.WriteString(fmt.Sprintf("- Self Score: %.2f\n", report.SelfScore))
 	if report.WeakestLink != "" {
 		result.WriteString(fmt.Sprintf("- Weakest Link: %s\n", report.WeakestLink))
@@ -814,6 +1194,15 @@ func (t *Tools) CalculateR(holonID string) (string, error) {
 	if report.DecayPenalty > 0 {
 		result.WriteString(fmt.Sprintf("- Decay Penalty: %.2f\n", report.DecayPenalty))
 	}
+	result.WriteString(fmt.Sprintf("- Penalty Model Φ(CL): %s\n", report.PenaltyModel))
+	result.WriteString(fmt.Sprintf("- Evidence Aggregation: %s\n", report.Aggregation))
+	if len(report.Projection) > 0 {
+		parts := make([]string, len(report.Projection))
+		for i, p := range report.Projection {
+			parts[i] = fmt.Sprintf("%dd: %.2f", p.Days, p.R)
+		}
+		result.WriteString(fmt.Sprintf("- Projected R: %s\n", strings.Join(parts, ", ")))
+	}
 	if len(report.Factors) > 0 {
 		result.WriteString("\n**Factors:**\n")
 		for _, f := range report.Factors {
@@ -891,12 +1280,24 @@ func (t *Tools) createWaiver(evidenceID, until, rationale string) (string, error
 	}
 
 	id := uuid.New().String()
-	if err := t.DB.CreateWaiver(ctx, id, evidenceID, "user", untilTime, rationale); err != nil {
-		return "", fmt.Errorf("failed to create waiver: %v", err)
+	waiverDir := filepath.Join(t.GetFPFDir(), "waivers")
+	err = t.transact(func() error {
+		if err := t.DB.CreateWaiver(ctx, id, evidenceID, "user", untilTime, rationale); err != nil {
+			return fmt.Errorf("failed to create waiver: %v", err)
+		}
+		if err := os.MkdirAll(waiverDir, 0755); err != nil {
+			return err
+		}
+		fields, body := waiverProjection(db.Waiver{ID: id, EvidenceID: evidenceID, WaivedBy: "user", WaivedUntil: untilTime, Rationale: rationale})
+		return t.writeProjection(filepath.Join(waiverDir, id+".md"), fields, body)
+	})
+	if err != nil {
+		return "", err
 	}
 
 	t.AuditLog("quint_check_decay", "waive", "user", evidenceID, "SUCCESS",
 		map[string]string{"until": until, "rationale": rationale}, "")
+	t.recalculateForEvidence(evidenceID)
 
 	return fmt.Sprintf(`Waiver recorded:
 - Evidence: %s
@@ -907,6 +1308,66 @@ func (t *Tools) createWaiver(evidenceID, until, rationale string) (string, error
    Set a reminder to run /q3-validate before then.`, evidenceID, until, rationale, until), nil
 }
 
+// RevokeWaiver withdraws the active waivers of a piece of evidence before they
+// expire, so the evidence decays again
+func (t *Tools) RevokeWaiver(evidenceID string) (string, error) {
+	defer t.RecordWork("RevokeWaiver", time.Now())
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+
+	ctx := context.Background()
+	waivers, err := t.DB.GetWaiversByEvidence(ctx, evidenceID)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	var revoked []string
+	waiverDir := filepath.Join(t.GetFPFDir(), "waivers")
+	err = t.transact(func() error {
+		for _, w := range waivers {
+			if w.RevokedAt.Valid || !w.WaivedUntil.After(now) {
+				continue
+			}
+			if err := t.DB.RevokeWaiver(ctx, w.ID, now); err != nil {
+				return fmt.Errorf("failed to revoke waiver: %v", err)
+			}
+			w.RevokedAt = sql.NullTime{Time: now, Valid: true}
+			fields, body := waiverProjection(w)
+			if err := t.writeProjection(filepath.Join(waiverDir, w.ID+".md"), fields, body); err != nil {
+				return err
+			}
+			revoked = append(revoked, w.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.AuditLog("quint_check_decay", "revoke_waiver", "user", evidenceID, "ERROR", nil, err.Error())
+		return "", err
+	}
+	if len(revoked) == 0 {
+		return "", fmt.Errorf("no active waiver for evidence %s", evidenceID)
+	}
+
+	t.AuditLog("quint_check_decay", "revoke_waiver", "user", evidenceID, "SUCCESS",
+		map[string]string{"waivers": strings.Join(revoked, ", ")}, "")
+	t.recalculateForEvidence(evidenceID)
+
+	return fmt.Sprintf("Waiver revoked for %s.\n\nThe evidence counts as EXPIRED again and decays in R_eff.\nRefresh it with /q3-validate.", evidenceID), nil
+}
+
+// recalculateForEvidence refreshes the cached R of the holon an evidence item supports
+func (t *Tools) recalculateForEvidence(evidenceID string) {
+	e, err := t.DB.GetEvidenceByID(context.Background(), evidenceID)
+	if err != nil {
+		return
+	}
+	if _, err := t.newCalculator().CalculateReliability(context.Background(), e.HolonID); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to recalculate R for %s: %v\n", e.HolonID, err)
+	}
+}
+
 func (t *Tools) generateFreshnessReport() (string, error) {
 	ctx := context.Background()
 	rawDB := t.DB.GetRawDB()
@@ -918,28 +1379,33 @@ func (t *Tools) generateFreshnessReport() (string, error) {
 			h.title,
 			h.layer,
 			e.type as evidence_type,
+			COALESCE(e.recipe, '') != '' as has_recipe,
 			CAST(JULIANDAY('now') - JULIANDAY(substr(e.valid_until, 1, 10)) AS INTEGER) as days_overdue
 		FROM evidence e
 		JOIN holons h ON e.holon_id = h.id
 		LEFT JOIN (
 			SELECT evidence_id, MAX(waived_until) as latest_waiver
 			FROM waivers
+			WHERE revoked_at IS NULL
 			GROUP BY evidence_id
 		) w ON e.id = w.evidence_id
-		WHERE e.valid_until IS NOT NULL
+		WHERE h.context_id = ?
+		  AND e.valid_until IS NOT NULL
 		  AND substr(e.valid_until, 1, 10) < date('now')
 		  AND (w.latest_waiver IS NULL OR w.latest_waiver < datetime('now'))
 		ORDER BY h.id, days_overdue DESC
-	`)
+	`, t.ContextID())
 	if err != nil {
 		return "", err
 	}
 	defer rows.Close() //nolint:errcheck
 
 	type evidenceInfo struct {
-		ID          string
-		Type        string
-		DaysOverdue int
+		ID        string
+		Type      string
+		Status    string
+		Details   string
+		HasRecipe bool
 	}
 
 	staleHolons := make(map[string][]evidenceInfo)
@@ -948,16 +1414,41 @@ func (t *Tools) generateFreshnessReport() (string, error) {
 
 	for rows.Next() {
 		var evidenceID, holonID, title, layer, evidenceType string
+		var hasRecipe bool
 		var daysOverdue int
-		if err := rows.Scan(&evidenceID, &holonID, &title, &layer, &evidenceType, &daysOverdue); err != nil {
+		if err := rows.Scan(&evidenceID, &holonID, &title, &layer, &evidenceType, &hasRecipe, &daysOverdue); err != nil {
 			continue
 		}
 		holonTitles[holonID] = title
 		holonLayers[holonID] = layer
 		staleHolons[holonID] = append(staleHolons[holonID], evidenceInfo{
-			ID:          evidenceID,
-			Type:        evidenceType,
-			DaysOverdue: daysOverdue,
+			ID:        evidenceID,
+			Type:      evidenceType,
+			Status:    "EXPIRED",
+			Details:   fmt.Sprintf("%d days overdue", daysOverdue),
+			HasRecipe: hasRecipe,
+		})
+	}
+
+	staleArtifacts, err := t.staleArtifacts(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, a := range staleArtifacts {
+		if _, ok := holonTitles[a.HolonID]; !ok {
+			holon, err := t.DB.GetHolon(ctx, a.HolonID)
+			if err != nil {
+				continue
+			}
+			holonTitles[a.HolonID] = holon.Title
+			holonLayers[a.HolonID] = holon.Layer
+		}
+		staleHolons[a.HolonID] = append(staleHolons[a.HolonID], evidenceInfo{
+			ID:        a.Evidence,
+			Type:      a.Type,
+			Status:    "ARTIFACT " + a.Status,
+			Details:   a.Ref,
+			HasRecipe: a.HasRecipe,
 		})
 	}
 
@@ -967,9 +1458,9 @@ func (t *Tools) generateFreshnessReport() (string, error) {
 		FROM waivers w
 		JOIN evidence e ON w.evidence_id = e.id
 		JOIN holons h ON e.holon_id = h.id
-		WHERE w.waived_until > datetime('now')
+		WHERE h.context_id = ? AND w.waived_until > datetime('now') AND w.revoked_at IS NULL
 		ORDER BY w.waived_until ASC
-	`)
+	`, t.ContextID())
 	if err != nil {
 		return "", err
 	}
@@ -998,7 +1489,7 @@ func (t *Tools) generateFreshnessReport() (string, error) {
 	result.WriteString("## Evidence Freshness Report\n\n")
 
 	if len(staleHolons) == 0 {
-		result.WriteString("### All holons FRESH ✓\n\nNo expired evidence found.\n")
+		result.WriteString("### All holons FRESH ✓\n\nNo expired evidence or changed artifacts found.\n")
 	} else {
 		result.WriteString(fmt.Sprintf("### STALE (%d holons require action)\n\n", len(staleHolons)))
 
@@ -1007,9 +1498,15 @@ func (t *Tools) generateFreshnessReport() (string, error) {
 			result.WriteString("| ID | Type | Status | Details |\n")
 			result.WriteString("|-----|------|--------|--------|\n")
 			for _, item := range evidenceItems {
-				result.WriteString(fmt.Sprintf("| %s | %s | EXPIRED | %d days overdue |\n", item.ID, item.Type, item.DaysOverdue))
+				result.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", item.ID, item.Type, item.Status, item.Details))
 			}
 			result.WriteString("\nActions:\n")
+			for _, item := range evidenceItems {
+				if item.HasRecipe {
+					result.WriteString(fmt.Sprintf("  → quint-code revalidate --holon %s (re-run stored recipes)\n", holonID))
+					break
+				}
+			}
 			result.WriteString(fmt.Sprintf("  → /q3-validate %s (refresh)\n", holonID))
 			result.WriteString(fmt.Sprintf("  → /q-decay --deprecate %s (downgrade)\n", holonID))
 			result.WriteString("  → /q-decay --waive <evidence_id> --until <date> --rationale \"...\"\n\n")
@@ -1036,3 +1533,68 @@ func (t *Tools) generateFreshnessReport() (string, error) {
 
 	return result.String(), nil
 }
+
+// ForecastDecay lists the L1 and L2 holons of the active context whose R is at
+// or above the assurance threshold today but will drop below it within days,
+// as their evidence decays
+func (t *Tools) ForecastDecay(days int) (string, error) {
+	defer t.RecordWork("ForecastDecay", time.Now())
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+	if days <= 0 {
+		return "", fmt.Errorf("forecast_days must be positive")
+	}
+
+	threshold := 0.8
+	if t.FSM != nil {
+		threshold = t.FSM.GetAssuranceThreshold()
+	}
+
+	ctx := context.Background()
+	holons, err := t.DB.ListHolonsByContext(ctx, t.ContextID())
+	if err != nil {
+		return "", err
+	}
+
+	type drop struct {
+		id, title, layer string
+		now, then        float64
+		day              int
+	}
+	var drops []drop
+	calc := t.newCalculator()
+	for _, h := range holons {
+		if h.Layer != "L1" && h.Layer != "L2" {
+			continue
+		}
+		now, err := calc.ProjectReliability(ctx, h.ID, time.Now())
+		if err != nil || now < threshold {
+			continue
+		}
+		day, r, ok, err := calc.ForecastDrop(ctx, h.ID, threshold, days)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			drops = append(drops, drop{id: h.ID, title: h.Title, layer: h.Layer, now: now, then: r, day: day})
+		}
+	}
+	sort.SliceStable(drops, func(i, j int) bool { return drops[i].day < drops[j].day })
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("## Decay Forecast (next %d days, threshold R %.2f)\n\n", days, threshold))
+	if len(drops) == 0 {
+		result.WriteString("No holon drops below the threshold in this window.\n")
+		return result.String(), nil
+	}
+
+	result.WriteString("| Holon | Layer | R now | Below threshold on | R then |\n")
+	result.WriteString("|-------|-------|-------|--------------------|--------|\n")
+	for _, d := range drops {
+		result.WriteString(fmt.Sprintf("| %s (%s) | %s | %.2f | %s (in %d days) | %.2f |\n",
+			d.title, d.id, d.layer, d.now, time.Now().AddDate(0, 0, d.day).Format("2006-01-02"), d.day, d.then))
+	}
+	result.WriteString("\nRefresh their evidence with /q3-validate before then, or waive it with a documented rationale.\n")
+	return result.String(), nil
+}
@@ -7,10 +7,14 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/m0n0x41d/quint-code/db"
 )
 
+// passingChecks is a minimal successful verification
+var passingChecks = []VerificationCheck{{Name: "logic", Category: CheckLogicalConsistency, Result: "pass"}}
+
 // Helper to create a dummy Tools instance for testing
 func setupTools(t *testing.T) (*Tools, *FSM, string) {
 	tempDir := t.TempDir()
@@ -191,7 +195,7 @@ func TestManageEvidence(t *testing.T) {
 				}
 			}
 
-			evidencePath, err := tools.ManageEvidence(tt.currentPhase, "add", tt.targetID, tt.evidenceType, tt.content, tt.verdict, tt.assuranceLevel, "file://carrier", "2025-12-31")
+			evidencePath, evidenceID, err := tools.ManageEvidence(tt.currentPhase, "add", tt.targetID, tt.evidenceType, tt.content, tt.verdict, tt.assuranceLevel, "file://carrier", "2025-12-31")
 
 			if (err != nil) != tt.expectErr {
 				t.Errorf("ManageEvidence() error = %v, expectErr %v", err, tt.expectErr)
@@ -205,6 +209,9 @@ func TestManageEvidence(t *testing.T) {
 			if _, err := os.Stat(evidencePath); os.IsNotExist(err) {
 				t.Errorf("Evidence file was not created at %s", evidencePath)
 			}
+			if filepath.Base(evidencePath) != evidenceID {
+				t.Errorf("Expected evidence ID %s to name %s", evidenceID, evidencePath)
+			}
 
 			// Verify hypothesis move
 			if tt.expectedMove {
@@ -269,6 +276,91 @@ func TestRefineLoopback(t *testing.T) {
 	}
 }
 
+func TestRefineHypothesis(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+
+	if _, err := tools.ProposeHypothesis("Shared Cache", "Redis cluster", "global", "system", "{}", "", nil, 3); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, err := tools.ProposeHypothesis("Caching Decision", "Pick a cache", "global", "system", "{}", "", nil, 3); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, err := tools.ProposeHypothesis("Session Store", "Keep sessions in the shared cache", "api", "episteme", "{}", "caching-decision", []string{"shared-cache"}, 2); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+
+	result, err := tools.RefineHypothesis("session-store", "Sessions outlive cache evictions", "Durable Session Store", "Persist sessions with a cache in front", "")
+	if err != nil {
+		t.Fatalf("RefineHypothesis failed: %v", err)
+	}
+	if !strings.Contains(result, "durable-session-store") || !strings.Contains(result, "Sessions outlive cache evictions") {
+		t.Errorf("Expected result to show the child and its lineage, got:\n%s", result)
+	}
+
+	parent, _ := tools.DB.GetHolon(ctx, "session-store")
+	if parent.Layer != "invalid" {
+		t.Errorf("Expected parent in invalid, got %s", parent.Layer)
+	}
+
+	child, err := tools.DB.GetHolon(ctx, "durable-session-store")
+	if err != nil {
+		t.Fatalf("Child holon not created: %v", err)
+	}
+	if child.ParentID.String != "session-store" || child.Kind.String != "episteme" || child.Scope.String != "api" {
+		t.Errorf("Expected child to inherit parent, kind and scope, got parent=%s kind=%s scope=%s",
+			child.ParentID.String, child.Kind.String, child.Scope.String)
+	}
+
+	relations, err := tools.DB.GetHolonRelations(ctx, "durable-session-store")
+	if err != nil {
+		t.Fatalf("GetHolonRelations failed: %v", err)
+	}
+	found := map[string]int64{}
+	for _, r := range relations {
+		found[r.SourceID+" "+r.RelationType+" "+r.TargetID] = r.CongruenceLevel.Int64
+	}
+	if cl, ok := found["shared-cache constituentOf durable-session-store"]; !ok || cl != 2 {
+		t.Errorf("Expected dependency on shared-cache with CL 2, got %v", found)
+	}
+	if _, ok := found["durable-session-store memberOf caching-decision"]; !ok {
+		t.Errorf("Expected membership in caching-decision, got %v", found)
+	}
+
+	loopback, err := tools.DB.GetLoopbackByChild(ctx, "durable-session-store")
+	if err != nil {
+		t.Fatalf("Loopback not recorded: %v", err)
+	}
+	if loopback.ParentID != "session-store" || loopback.Phase != string(PhaseDeduction) {
+		t.Errorf("Unexpected loopback: %+v", loopback)
+	}
+
+	tree, err := tools.VisualizeAudit("durable-session-store")
+	if err != nil {
+		t.Fatalf("VisualizeAudit failed: %v", err)
+	}
+	if !strings.Contains(tree, "Lineage of durable-session-store") || !strings.Contains(tree, "[invalid] session-store") {
+		t.Errorf("Expected audit tree to include lineage, got:\n%s", tree)
+	}
+
+	if _, err := tools.RefineHypothesis("durable-session-store", "x", "Durable Session Store", "same title", ""); err == nil {
+		t.Error("Expected error when the child reuses the parent's title")
+	}
+
+	// A REFINE verdict during validation has already moved the parent to invalid
+	_, _ = tools.ProposeHypothesis("Edge Cache", "Cache at the CDN", "static", "system", "{}", "", nil, 3)
+	_, _ = tools.MoveHypothesis("edge-cache", "L0", "L1")
+	if _, _, err := tools.ManageEvidence(PhaseInduction, "add", "edge-cache", "internal", "Stale content", "REFINE", "L1", "test-runner", ""); err != nil {
+		t.Fatalf("ManageEvidence failed: %v", err)
+	}
+	if _, err := tools.RefineHypothesis("edge-cache", "Needs purge hooks", "Edge Cache With Purge", "Purge on deploy", ""); err != nil {
+		t.Fatalf("RefineHypothesis of an invalidated parent failed: %v", err)
+	}
+	if lb, err := tools.DB.GetLoopbackByChild(ctx, "edge-cache-with-purge"); err != nil || lb.Phase != string(PhaseInduction) {
+		t.Errorf("Expected induction loopback for edge-cache, got %+v (err %v)", lb, err)
+	}
+}
+
 func TestFinalizeDecision(t *testing.T) {
 
 	tools, fsm, tempDir := setupTools(t)
@@ -333,7 +425,7 @@ func TestVerifyHypothesis(t *testing.T) {
 
 	// Case 1: PASS -> Promote to L1
 	fsm.State.Phase = PhaseDeduction
-	msg, err := tools.VerifyHypothesis(hypoID, `{"check":"ok"}`, "PASS")
+	msg, _, err := tools.VerifyHypothesis(hypoID, passingChecks, "PASS")
 	if err != nil {
 		t.Errorf("VerifyHypothesis(PASS) failed: %v", err)
 	}
@@ -352,7 +444,7 @@ func TestVerifyHypothesis(t *testing.T) {
 		t.Fatalf("Failed to create dummy L0 hypothesis 2: %v", err)
 	}
 
-	msg, err = tools.VerifyHypothesis(hypoID2, `{"check":"bad"}`, "FAIL")
+	msg, _, err = tools.VerifyHypothesis(hypoID2, []VerificationCheck{{Name: "constraints", Category: CheckConstraint, Result: "fail"}}, "FAIL")
 	if err != nil {
 		t.Errorf("VerifyHypothesis(FAIL) failed: %v", err)
 	}
@@ -399,7 +491,7 @@ func TestCalculateR(t *testing.T) {
 	ctx := context.Background()
 
 	// Create a holon with evidence
-	err := tools.DB.CreateHolon(ctx, "calc-r-test", "hypothesis", "system", "L1", "Test Holon", "Content", "ctx", "global", "")
+	err := tools.DB.CreateHolon(ctx, "calc-r-test", "hypothesis", "system", "L1", "Test Holon", "Content", tools.ContextID(), "global", "")
 	if err != nil {
 		t.Fatalf("Failed to create holon: %v", err)
 	}
@@ -433,7 +525,7 @@ func TestCalculateR_WithDecay(t *testing.T) {
 	ctx := context.Background()
 
 	// Create a holon with expired evidence
-	err := tools.DB.CreateHolon(ctx, "decay-r-test", "hypothesis", "system", "L1", "Decay Test", "Content", "ctx", "global", "")
+	err := tools.DB.CreateHolon(ctx, "decay-r-test", "hypothesis", "system", "L1", "Decay Test", "Content", tools.ContextID(), "global", "")
 	if err != nil {
 		t.Fatalf("Failed to create holon: %v", err)
 	}
@@ -461,7 +553,7 @@ func TestCheckDecay_NoExpired(t *testing.T) {
 	ctx := context.Background()
 
 	// Create a holon with fresh evidence
-	err := tools.DB.CreateHolon(ctx, "fresh-holon", "hypothesis", "system", "L2", "Fresh", "Content", "ctx", "global", "")
+	err := tools.DB.CreateHolon(ctx, "fresh-holon", "hypothesis", "system", "L2", "Fresh", "Content", tools.ContextID(), "global", "")
 	if err != nil {
 		t.Fatalf("Failed to create holon: %v", err)
 	}
@@ -489,7 +581,7 @@ func TestCheckDecay_WithExpired(t *testing.T) {
 	ctx := context.Background()
 
 	// Create a holon with expired evidence
-	err := tools.DB.CreateHolon(ctx, "stale-holon", "hypothesis", "system", "L2", "Stale Holon", "Content", "ctx", "global", "")
+	err := tools.DB.CreateHolon(ctx, "stale-holon", "hypothesis", "system", "L2", "Stale Holon", "Content", tools.ContextID(), "global", "")
 	if err != nil {
 		t.Fatalf("Failed to create holon: %v", err)
 	}
@@ -526,7 +618,7 @@ func TestCheckDecay_Deprecate(t *testing.T) {
 
 	// Create L2 holon with file
 	holonID := "deprecate-test"
-	err := tools.DB.CreateHolon(ctx, holonID, "hypothesis", "system", "L2", "Deprecate Test", "Content", "ctx", "global", "")
+	err := tools.DB.CreateHolon(ctx, holonID, "hypothesis", "system", "L2", "Deprecate Test", "Content", tools.ContextID(), "global", "")
 	if err != nil {
 		t.Fatalf("Failed to create holon: %v", err)
 	}
@@ -566,7 +658,7 @@ func TestCheckDecay_Waive(t *testing.T) {
 	// Create holon with expired evidence
 	holonID := "waive-test-holon"
 	evidenceID := "waive-test-evidence"
-	err := tools.DB.CreateHolon(ctx, holonID, "hypothesis", "system", "L2", "Waive Test", "Content", "ctx", "global", "")
+	err := tools.DB.CreateHolon(ctx, holonID, "hypothesis", "system", "L2", "Waive Test", "Content", tools.ContextID(), "global", "")
 	if err != nil {
 		t.Fatalf("Failed to create holon: %v", err)
 	}
@@ -612,6 +704,54 @@ func TestCheckDecay_Waive(t *testing.T) {
 	}
 }
 
+func TestRevokeWaiver(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+
+	if err := tools.DB.CreateHolon(ctx, "revoke-holon", "hypothesis", "system", "L2", "Revoke Test", "Content", tools.ContextID(), "global", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.DB.AddEvidence(ctx, "revoke-evidence", "revoke-holon", "test", "Old test", "pass", "L2", "test-runner", "2020-01-01"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tools.CheckDecay("", "revoke-evidence", "2099-12-31", "Re-run next sprint"); err != nil {
+		t.Fatalf("Waive failed: %v", err)
+	}
+	if holon, _ := tools.DB.GetHolon(ctx, "revoke-holon"); holon.CachedRScore.Float64 != 1.0 {
+		t.Errorf("Expected the waiver to restore R to 1.0, got %v", holon.CachedRScore)
+	}
+
+	result, err := tools.RevokeWaiver("revoke-evidence")
+	if err != nil {
+		t.Fatalf("RevokeWaiver failed: %v", err)
+	}
+	if !strings.Contains(result, "Waiver revoked") {
+		t.Errorf("Unexpected result: %s", result)
+	}
+	if holon, _ := tools.DB.GetHolon(ctx, "revoke-holon"); holon.CachedRScore.Float64 != 0.1 {
+		t.Errorf("Expected R to decay again after revocation, got %v", holon.CachedRScore)
+	}
+
+	report, _ := tools.CheckDecay("", "", "", "")
+	if !strings.Contains(report, "STALE") || strings.Contains(report, "WAIVED") {
+		t.Errorf("Expected the evidence to be STALE again, got: %s", report)
+	}
+
+	waivers, _ := tools.DB.GetWaiversByEvidence(ctx, "revoke-evidence")
+	if len(waivers) != 1 || !waivers[0].RevokedAt.Valid {
+		t.Fatalf("Expected the waiver to be kept with revoked_at, got %+v", waivers)
+	}
+	content, err := os.ReadFile(filepath.Join(tools.GetFPFDir(), "waivers", waivers[0].ID+".md"))
+	if err != nil || !strings.Contains(string(content), "revoked_at: ") {
+		t.Errorf("Expected revoked_at in the waiver file, got %s (%v)", content, err)
+	}
+
+	if _, err := tools.RevokeWaiver("revoke-evidence"); err == nil || !strings.Contains(err.Error(), "no active waiver") {
+		t.Errorf("Expected a second revocation to fail, got %v", err)
+	}
+}
+
 func TestCheckDecay_WaiveMissingParams(t *testing.T) {
 	tools, _, _ := setupTools(t)
 
@@ -634,7 +774,7 @@ func TestCheckDecay_DeprecateL0Fails(t *testing.T) {
 
 	// Create L0 holon
 	holonID := "l0-deprecate-test"
-	err := tools.DB.CreateHolon(ctx, holonID, "hypothesis", "system", "L0", "L0 Test", "Content", "ctx", "global", "")
+	err := tools.DB.CreateHolon(ctx, holonID, "hypothesis", "system", "L0", "L0 Test", "Content", tools.ContextID(), "global", "")
 	if err != nil {
 		t.Fatalf("Failed to create holon: %v", err)
 	}
@@ -649,12 +789,63 @@ func TestCheckDecay_DeprecateL0Fails(t *testing.T) {
 	}
 }
 
+func TestForecastDecay(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+
+	config := `{"evidence_decay": {"types": {"test": {"curve": "linear", "days": 20}}}}`
+	if err := os.WriteFile(tools.ConfigPath(), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// fading's evidence expires in 10 days, then loses R over 20 more; steady's holds
+	for _, h := range []struct{ id, validUntil string }{
+		{"fading", time.Now().AddDate(0, 0, 10).Format("2006-01-02")},
+		{"steady", "2099-12-31"},
+	} {
+		if err := tools.DB.CreateHolon(ctx, h.id, "hypothesis", "system", "L2", h.id, "Content", tools.ContextID(), "global", ""); err != nil {
+			t.Fatal(err)
+		}
+		if err := tools.DB.AddEvidence(ctx, "e-"+h.id, h.id, "test", "Benchmark", "pass", "L2", "test-runner", h.validUntil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := tools.ForecastDecay(7)
+	if err != nil {
+		t.Fatalf("ForecastDecay failed: %v", err)
+	}
+	if !strings.Contains(result, "No holon drops below the threshold") {
+		t.Errorf("Expected no drop within 7 days, got: %s", result)
+	}
+
+	// Linear decay to 0.1 crosses R 0.8 about 4.4 days past valid_until
+	result, err = tools.ForecastDecay(30)
+	if err != nil {
+		t.Fatalf("ForecastDecay failed: %v", err)
+	}
+	if !strings.Contains(result, "(fading)") || strings.Contains(result, "(steady)") {
+		t.Errorf("Expected only fading in the forecast, got: %s", result)
+	}
+	if !strings.Contains(result, "(in 14 days)") && !strings.Contains(result, "(in 15 days)") {
+		t.Errorf("Expected fading to drop in 14-15 days, got: %s", result)
+	}
+
+	report, err := tools.CalculateR("fading")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(report, "Projected R: 7d: 1.00, 30d: 0.10, 90d: 0.10") {
+		t.Errorf("Expected the projection in the report, got: %s", report)
+	}
+}
+
 func TestVisualizeAudit(t *testing.T) {
 	tools, _, _ := setupTools(t)
 	ctx := context.Background()
 
 	// Create a holon
-	err := tools.DB.CreateHolon(ctx, "audit-viz-test", "hypothesis", "system", "L2", "Audit Viz Test", "Content", "ctx", "global", "")
+	err := tools.DB.CreateHolon(ctx, "audit-viz-test", "hypothesis", "system", "L2", "Audit Viz Test", "Content", tools.ContextID(), "global", "")
 	if err != nil {
 		t.Fatalf("Failed to create holon: %v", err)
 	}
@@ -1003,3 +1194,175 @@ func TestFormatInvariants(t *testing.T) {
 		t.Errorf("Expected line 3 to start with '3. Telethon', got: %s", lines[2])
 	}
 }
+
+func TestRecordClaim_ShowsTuple(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	if _, err := tools.ProposeHypothesis("Formal Claim", "content", "api", "system", "{}", "", nil, 3); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if err := tools.RecordClaim("formal-claim", 4, []string{"API", "eu"}); err != nil {
+		t.Fatalf("RecordClaim failed: %v", err)
+	}
+	if err := tools.RecordClaim("formal-claim", 12, nil); err == nil {
+		t.Error("Expected out-of-range formality to fail")
+	}
+
+	holon, _ := tools.DB.GetHolon(context.Background(), "formal-claim")
+	if holon.Formality.Int64 != 4 || holon.ClaimScope.String != `["api","eu"]` {
+		t.Errorf("Unexpected stored claim: F=%v G=%v", holon.Formality, holon.ClaimScope)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tools.GetFPFDir(), "knowledge", "L0", "formal-claim.md"))
+	if !strings.Contains(string(content), "formality: F4") {
+		t.Errorf("Expected formality in frontmatter, got:\n%s", content)
+	}
+	if _, tampered, _, _, _ := ValidateFile(filepath.Join(tools.GetFPFDir(), "knowledge", "L0", "formal-claim.md")); tampered {
+		t.Error("Frontmatter update should not invalidate the content hash")
+	}
+
+	result, err := tools.CalculateR("formal-claim")
+	if err != nil {
+		t.Fatalf("CalculateR failed: %v", err)
+	}
+	if !strings.Contains(result, "F4") || !strings.Contains(result, "{api, eu}") {
+		t.Errorf("Expected F-G-R tuple in output, got: %s", result)
+	}
+
+	tree, err := tools.VisualizeAudit("formal-claim")
+	if err != nil {
+		t.Fatalf("VisualizeAudit failed: %v", err)
+	}
+	if !strings.Contains(tree, "F:4 G:{api, eu}") {
+		t.Errorf("Expected F and G in audit tree, got: %s", tree)
+	}
+}
+
+func TestCalculateR_UsesConfiguredPenalty(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+
+	config := `{"congruence_penalty": {"preset": "fpf"}}`
+	if err := os.WriteFile(tools.ConfigPath(), []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	_ = tools.DB.CreateHolon(ctx, "whole", "hypothesis", "system", "L2", "Whole", "content", "default", "global", "")
+	_ = tools.DB.CreateHolon(ctx, "part", "hypothesis", "system", "L2", "Part", "content", "default", "global", "")
+	_ = tools.DB.AddEvidence(ctx, "e-whole", "whole", "test", "ok", "pass", "L2", "test-runner", "2099-01-01")
+	_ = tools.DB.AddEvidence(ctx, "e-part", "part", "test", "ok", "pass", "L2", "test-runner", "2099-01-01")
+	_ = tools.DB.CreateRelation(ctx, "part", "componentOf", "whole", 2)
+
+	result, err := tools.CalculateR("whole")
+	if err != nil {
+		t.Fatalf("CalculateR failed: %v", err)
+	}
+	if !strings.Contains(result, "R_eff: 0.50") {
+		t.Errorf("Expected FPF CL2 penalty (R_eff 0.50), got: %s", result)
+	}
+	if !strings.Contains(result, "Penalty Model Φ(CL): fpf") {
+		t.Errorf("Expected penalty model in report, got: %s", result)
+	}
+}
+
+func TestLoadAssuranceConfig_Cached(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	if err := os.WriteFile(tools.ConfigPath(), []byte(`{"congruence_penalty": {"preset": "bogus"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tools.loadAssuranceConfig()
+	first := tools.config
+	tools.newCalculator()
+	if tools.config != first {
+		t.Error("Expected an unchanged config.json not to be parsed (and reported) again")
+	}
+
+	if err := os.WriteFile(tools.ConfigPath(), []byte(`{"congruence_penalty": {"preset": "fpf"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if cfg := tools.loadAssuranceConfig(); cfg.CongruencePenalty.Preset != "fpf" {
+		t.Errorf("Expected the edited config to be picked up, got %+v", cfg.CongruencePenalty)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	if _, err := tools.ProposeHypothesis("Redis Caching", "Cache sessions in Redis", "api", "system", "{}", "", nil, 3); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, err := tools.ProposeHypothesis("Review Process", "Pair review for schema changes", "team", "episteme", "{}", "", nil, 3); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+
+	output, err := tools.Search("redis", SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if !strings.Contains(output, "| redis-caching | holon |") || strings.Contains(output, "review-process") {
+		t.Errorf("unexpected search output:\n%s", output)
+	}
+
+	output, _ = tools.Search("redis", SearchOptions{Kind: "episteme"})
+	if !strings.Contains(output, "No matches.") || !strings.Contains(output, "kind=episteme") {
+		t.Errorf("expected no episteme matches with the filter shown:\n%s", output)
+	}
+
+	if _, err := tools.Search("  ", SearchOptions{}); err == nil {
+		t.Error("expected error for empty query")
+	}
+}
+
+func TestCharacterize(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	_, _ = tools.ProposeHypothesis("Redis", "Use Redis", "api", "system", "{}", "", nil, 3)
+	_, _ = tools.ProposeHypothesis("Memcached", "Use Memcached", "api", "system", "{}", "", nil, 3)
+
+	tests := []struct {
+		name, holon, characteristic, scale, value, unit string
+		wantErr                                         bool
+	}{
+		{"Ratio", "redis", "p99 latency", "ratio", "2.5", "ms", false},
+		{"Nominal", "redis", "license", "nominal", "BSD", "", false},
+		{"RatioOther", "memcached", "p99 latency", "ratio", "1.8", "ms", false},
+		{"NonNumericInterval", "redis", "uptime", "interval", "high", "", true},
+		{"NegativeRatio", "redis", "cost", "ratio", "-1", "USD", true},
+		{"UnknownScale", "redis", "cost", "absolute", "1", "", true},
+		{"UnknownHolon", "missing", "cost", "ratio", "1", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tools.Characterize(tt.holon, tt.characteristic, tt.scale, tt.value, tt.unit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Characterize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Re-recording a characteristic supersedes the previous value
+	if _, err := tools.Characterize("redis", "p99 latency", "ratio", "2.1", "ms"); err != nil {
+		t.Fatalf("Characterize failed: %v", err)
+	}
+
+	drrPath, err := tools.FinalizeDecision("Cache Choice", "redis", []string{"memcached"}, "Context", "Redis", "Rationale", "Consequences", "Latency dominates")
+	if err != nil {
+		t.Fatalf("FinalizeDecision failed: %v", err)
+	}
+	content, err := os.ReadFile(drrPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drr := string(content)
+	for _, want := range []string{
+		"Latency dominates",
+		"| Characteristic | Scale | redis (selected) | memcached (rejected) |",
+		"| p99 latency | ratio | 2.1 ms | 1.8 ms |",
+		"| license | nominal | BSD | - |",
+	} {
+		if !strings.Contains(drr, want) {
+			t.Errorf("DRR missing %q:\n%s", want, drr)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package fpf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// unitOfWork keeps the markdown projection and the database in step. While it
+// runs, DB changes go through one transaction and file changes are staged;
+// both are committed together or both rolled back.
+type unitOfWork struct {
+	ops     []fileOp
+	applied int
+}
+
+// fileOp moves src to dest when the unit of work commits. For writes, src is a
+// staged temp file next to dest; removals have no src. An existing dest is kept
+// as backup until the DB commits, so the operation can be undone.
+type fileOp struct {
+	src    string
+	dest   string
+	staged bool
+	remove bool
+	backup string
+}
+
+// transact runs fn as one unit of work. Nested calls join the running unit,
+// so composite operations (e.g. a loopback) commit or fail as a whole.
+func (t *Tools) transact(fn func() error) error {
+	if t.uow != nil {
+		return fn()
+	}
+
+	uow := &unitOfWork{}
+	t.uow = uow
+	defer func() { t.uow = nil }()
+
+	run := func() error {
+		if err := fn(); err != nil {
+			return err
+		}
+		return uow.apply()
+	}
+
+	var err error
+	if t.DB == nil {
+		err = run()
+	} else {
+		base := t.DB
+		err = base.WithTx(context.Background(), func(tx *db.Store) error {
+			t.DB = tx
+			defer func() { t.DB = base }()
+			return run()
+		})
+	}
+
+	if err != nil {
+		if undoErr := uow.rollback(); undoErr != nil {
+			return fmt.Errorf("%w (restoring files failed: %v)", err, undoErr)
+		}
+		return err
+	}
+	uow.finish()
+	return nil
+}
+
+// writeProjection writes a hashed projection file, staged when a unit of work is running
+func (t *Tools) writeProjection(path string, fields map[string]string, body string) error {
+	return t.writeFile(path, []byte(renderWithHash(fields, body)))
+}
+
+// writeFile writes a file, staged when a unit of work is running
+func (t *Tools) writeFile(path string, data []byte) error {
+	if t.uow == nil {
+		return os.WriteFile(path, data, 0644)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	t.uow.ops = append(t.uow.ops, fileOp{src: tmp.Name(), dest: path, staged: true})
+	return nil
+}
+
+// renameFile moves a file, deferred to commit when a unit of work is running
+func (t *Tools) renameFile(src, dest string) error {
+	if t.uow == nil {
+		return os.Rename(src, dest)
+	}
+	t.uow.ops = append(t.uow.ops, fileOp{src: src, dest: dest})
+	return nil
+}
+
+// removeFile deletes a file, deferred to commit when a unit of work is running
+func (t *Tools) removeFile(path string) error {
+	if t.uow == nil {
+		return os.Remove(path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	t.uow.ops = append(t.uow.ops, fileOp{dest: path, remove: true})
+	return nil
+}
+
+// apply moves every staged file into place, backing up what it replaces
+func (u *unitOfWork) apply() error {
+	for i := range u.ops {
+		op := &u.ops[i]
+		if _, err := os.Stat(op.dest); err == nil {
+			op.backup = fmt.Sprintf("%s.%d.bak", op.dest, i)
+			if err := os.Rename(op.dest, op.backup); err != nil {
+				op.backup = ""
+				return fmt.Errorf("failed to back up %s: %v", op.dest, err)
+			}
+		}
+		if op.remove {
+			// The backup is the removal; finish deletes it
+			u.applied++
+			continue
+		}
+		if err := os.Rename(op.src, op.dest); err != nil {
+			if op.backup != "" {
+				_ = os.Rename(op.backup, op.dest)
+				op.backup = ""
+			}
+			return fmt.Errorf("failed to move %s into place: %v", op.dest, err)
+		}
+		u.applied++
+	}
+	return nil
+}
+
+// rollback undoes applied operations in reverse order and drops staged files
+func (u *unitOfWork) rollback() error {
+	var errs []error
+	for i := u.applied - 1; i >= 0; i-- {
+		op := u.ops[i]
+		var err error
+		switch {
+		case op.remove:
+		case op.staged:
+			err = os.Remove(op.dest)
+		default:
+			err = os.Rename(op.dest, op.src)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if op.backup != "" {
+			if err := os.Rename(op.backup, op.dest); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	for _, op := range u.ops[u.applied:] {
+		if op.staged {
+			_ = os.Remove(op.src)
+		}
+	}
+	u.applied = 0
+	return errors.Join(errs...)
+}
+
+// finish drops the backups once both stores are committed
+func (u *unitOfWork) finish() {
+	for _, op := range u.ops {
+		if op.backup != "" {
+			_ = os.Remove(op.backup)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package fpf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// leftovers lists staged temp files and backups a unit of work failed to clean up
+func leftovers(t *testing.T, root string) []string {
+	t.Helper()
+	var found []string
+	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err == nil && (strings.HasSuffix(path, ".tmp") || strings.HasSuffix(path, ".bak")) {
+			found = append(found, path)
+		}
+		return nil
+	})
+	return found
+}
+
+func TestTransact_ProposeRollsBackOnDBFailure(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+
+	path, err := tools.ProposeHypothesis("Redis Cache", "Original content", "api", "system", "{}", "", nil, 3)
+	if err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	original, _ := os.ReadFile(path)
+
+	// Same slug: the holon insert fails, so the rewritten file must be restored
+	if _, err := tools.ProposeHypothesis("Redis Cache", "Replacement content", "api", "system", "{}", "", nil, 3); err == nil {
+		t.Fatal("Expected duplicate proposal to fail")
+	}
+
+	current, _ := os.ReadFile(path)
+	if string(current) != string(original) {
+		t.Errorf("Expected original projection to be restored, got:\n%s", current)
+	}
+	holon, _ := tools.DB.GetHolon(ctx, "redis-cache")
+	if !strings.Contains(holon.Content, "Original content") {
+		t.Errorf("Expected DB content to be unchanged, got %q", holon.Content)
+	}
+	if files := leftovers(t, tempDir); len(files) > 0 {
+		t.Errorf("Expected no staged files left, got %v", files)
+	}
+}
+
+func TestTransact_EvidenceRollsBackMove(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+
+	if _, err := tools.ProposeHypothesis("Queue", "Use a queue", "api", "system", "{}", "", nil, 3); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, err := tools.DB.GetRawDB().Exec("DROP TABLE evidence"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := tools.VerifyHypothesis("queue", passingChecks, "PASS"); err == nil {
+		t.Fatal("Expected verification to fail when evidence cannot be stored")
+	}
+
+	if _, err := os.Stat(filepath.Join(tempDir, ".quint", "knowledge", "L0", "queue.md")); err != nil {
+		t.Errorf("Expected hypothesis to stay in L0: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, ".quint", "knowledge", "L1", "queue.md")); err == nil {
+		t.Error("Expected no L1 projection after rollback")
+	}
+	if holon, _ := tools.DB.GetHolon(ctx, "queue"); holon.Layer != "L0" {
+		t.Errorf("Expected DB layer L0 after rollback, got %s", holon.Layer)
+	}
+	evidence, _ := filepath.Glob(filepath.Join(tempDir, ".quint", "evidence", "*.md"))
+	if len(evidence) != 0 {
+		t.Errorf("Expected no evidence files after rollback, got %v", evidence)
+	}
+	if files := leftovers(t, tempDir); len(files) > 0 {
+		t.Errorf("Expected no staged files left, got %v", files)
+	}
+}
+
+func TestTransact_VerifyRecordsEvidence(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+
+	if _, err := tools.ProposeHypothesis("Queue", "Use a queue", "api", "system", "{}", "", nil, 3); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, _, err := tools.VerifyHypothesis("queue", passingChecks, "PASS"); err != nil {
+		t.Fatalf("VerifyHypothesis failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tempDir, ".quint", "knowledge", "L1", "queue.md")); err != nil {
+		t.Errorf("Expected hypothesis in L1: %v", err)
+	}
+	if holon, _ := tools.DB.GetHolon(ctx, "queue"); holon.Layer != "L1" {
+		t.Errorf("Expected DB layer L1, got %s", holon.Layer)
+	}
+	evidence, _ := tools.DB.GetEvidence(ctx, "queue")
+	if len(evidence) != 1 || evidence[0].Type != "verification" {
+		t.Errorf("Expected one verification evidence row, got %+v", evidence)
+	}
+}
+
+func TestTransact_DecisionRollsBack(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+
+	drrPath, err := tools.FinalizeDecision("Cache Choice", "redis", nil, "Context", "First", "Rationale", "Consequences", "")
+	if err != nil {
+		t.Fatalf("FinalizeDecision failed: %v", err)
+	}
+	original, _ := os.ReadFile(drrPath)
+
+	if _, err := tools.FinalizeDecision("Cache Choice", "redis", nil, "Context", "Second", "Rationale", "Consequences", ""); err == nil {
+		t.Fatal("Expected a second decision with the same title to fail")
+	}
+	current, _ := os.ReadFile(drrPath)
+	if string(current) != string(original) {
+		t.Errorf("Expected first DRR to be restored, got:\n%s", current)
+	}
+	if files := leftovers(t, tempDir); len(files) > 0 {
+		t.Errorf("Expected no staged files left, got %v", files)
+	}
+}
+
+func TestTransact_TestToolRollsBackOnBadArtifact(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+	server := NewServer(tools)
+
+	if _, err := tools.ProposeHypothesis("Queue", "Use a queue", "api", "system", "{}", "", nil, 3); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, _, err := tools.VerifyHypothesis("queue", passingChecks, "PASS"); err != nil {
+		t.Fatalf("VerifyHypothesis failed: %v", err)
+	}
+
+	args := map[string]interface{}{
+		"hypothesis_id": "queue", "test_type": "internal", "result": "Load test passed", "verdict": "PASS",
+		"formality": float64(2), "artifact_kind": "gotest", "artifact": "missing.json",
+	}
+	if result := server.callTool("quint_test", args); !result.IsError {
+		t.Fatalf("Expected a missing artifact to fail quint_test, got %+v", result)
+	}
+	if holon, _ := tools.DB.GetHolon(ctx, "queue"); holon.Layer != "L1" {
+		t.Errorf("Expected the promotion to roll back, got layer %s", holon.Layer)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, ".quint", "knowledge", "L1", "queue.md")); err != nil {
+		t.Errorf("Expected hypothesis to stay in L1: %v", err)
+	}
+
+	delete(args, "artifact")
+	delete(args, "artifact_kind")
+	if result := server.callTool("quint_test", args); result.IsError {
+		t.Fatalf("Expected the retry to succeed, got %+v", result)
+	}
+	if holon, _ := tools.DB.GetHolon(ctx, "queue"); holon.Layer != "L2" {
+		t.Errorf("Expected the retry to promote to L2, got %s", holon.Layer)
+	}
+}
+
+func TestTransact_RemoveFile(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	path := filepath.Join(tempDir, "stray.md")
+	if err := os.WriteFile(path, []byte("stray"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := tools.transact(func() error {
+		if err := tools.removeFile(path); err != nil {
+			return err
+		}
+		return os.ErrInvalid
+	})
+	if err == nil {
+		t.Fatal("Expected the unit of work to fail")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected the file to survive a rollback: %v", err)
+	}
+
+	if err := tools.transact(func() error { return tools.removeFile(path) }); err != nil {
+		t.Fatalf("transact failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected the file to be removed on commit")
+	}
+	if files := leftovers(t, tempDir); len(files) > 0 {
+		t.Errorf("Expected no staged files left, got %v", files)
+	}
+}
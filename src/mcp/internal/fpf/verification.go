@@ -0,0 +1,200 @@
+package fpf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Categories of a deductive verification check
+const (
+	CheckTypeCheck          = "type-check"
+	CheckConstraint         = "constraint"
+	CheckLogicalConsistency = "logical-consistency"
+)
+
+// CheckCategories lists the accepted check categories
+var CheckCategories = []string{CheckTypeCheck, CheckConstraint, CheckLogicalConsistency}
+
+// VerificationCheck is one check performed by quint_verify. Each check is
+// recorded as its own evidence item, so a failed check lowers R on its own.
+type VerificationCheck struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Result   string `json:"result"` // pass or fail
+	Notes    string `json:"notes,omitempty"`
+}
+
+// ParseVerificationChecks decodes the checks argument of quint_verify: a list
+// of check objects, or the same list encoded as a JSON string
+func ParseVerificationChecks(v interface{}) ([]VerificationCheck, error) {
+	data, ok := v.(string)
+	if !ok {
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid checks: %v", err)
+		}
+		data = string(encoded)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(data)))
+	dec.DisallowUnknownFields()
+	var checks []VerificationCheck
+	if err := dec.Decode(&checks); err != nil {
+		return nil, fmt.Errorf("checks must be a list of {name, category, result, notes}: %v", err)
+	}
+	return checks, nil
+}
+
+// normalizeChecks validates checks and lowercases their category and result.
+// Names must be unique per call: each one names an evidence record.
+func (t *Tools) normalizeChecks(checks []VerificationCheck) ([]VerificationCheck, error) {
+	if len(checks) == 0 {
+		return nil, fmt.Errorf("at least one verification check is required")
+	}
+
+	seen := make(map[string]bool)
+	normalized := make([]VerificationCheck, len(checks))
+	for i, c := range checks {
+		c.Name = strings.TrimSpace(c.Name)
+		c.Category = strings.ToLower(strings.TrimSpace(c.Category))
+		c.Result = strings.ToLower(strings.TrimSpace(c.Result))
+		c.Notes = strings.TrimSpace(c.Notes)
+
+		slug := t.Slugify(c.Name)
+		if slug == "" {
+			return nil, fmt.Errorf("check %d has no name", i+1)
+		}
+		if seen[slug] {
+			return nil, fmt.Errorf("duplicate check %q", c.Name)
+		}
+		seen[slug] = true
+
+		switch c.Category {
+		case CheckTypeCheck, CheckConstraint, CheckLogicalConsistency:
+		default:
+			return nil, fmt.Errorf("check %q: category must be one of %s, got %q", c.Name, strings.Join(CheckCategories, ", "), c.Category)
+		}
+		if c.Result != "pass" && c.Result != "fail" {
+			return nil, fmt.Errorf("check %q: result must be pass or fail, got %q", c.Name, c.Result)
+		}
+		normalized[i] = c
+	}
+	return normalized, nil
+}
+
+// verificationEvidenceID names the evidence record of one check
+func (t *Tools) verificationEvidenceID(hypothesisID string, check VerificationCheck) string {
+	return evidenceFileName("verification-"+t.Slugify(check.Name), hypothesisID)
+}
+
+func verificationContent(c VerificationCheck) string {
+	content := fmt.Sprintf("Verification check: %s\nCategory: %s\nResult: %s", c.Name, c.Category, strings.ToUpper(c.Result))
+	if c.Notes != "" {
+		content += "\n\n" + c.Notes
+	}
+	return content
+}
+
+// RecordVerificationFormality sets the formality (F) of the check evidence VerifyHypothesis recorded
+func (t *Tools) RecordVerificationFormality(evidenceIDs []string, formality int) error {
+	return t.transact(func() error {
+		for _, id := range evidenceIDs {
+			if err := t.recordEvidenceFormality(context.Background(), id, formality); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// VerifyHypothesis records a verdict on an L0 hypothesis with the checks behind
+// it. PASS promotes to L1 and is refused when any check failed, FAIL moves to
+// invalid, REFINE keeps it in L0; every check is recorded as verification
+// evidence, and the IDs of those records are returned.
+func (t *Tools) VerifyHypothesis(hypothesisID string, checks []VerificationCheck, verdict string) (string, []string, error) {
+	defer t.RecordWork("VerifyHypothesis", time.Now())
+
+	checks, err := t.normalizeChecks(checks)
+	if err == nil {
+		err = t.checkHolonContext(hypothesisID)
+	}
+	if err != nil {
+		t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"verdict": verdict}, err.Error())
+		return "", nil, err
+	}
+
+	carrierRef := "internal-logic"
+	if t.DB != nil {
+		holon, err := t.DB.GetHolon(context.Background(), hypothesisID)
+		if err == nil && holon.Kind.Valid {
+			switch holon.Kind.String {
+			case "system":
+				carrierRef = "internal-logic"
+			case "episteme":
+				carrierRef = "formal-logic"
+			}
+		}
+	}
+
+	failed := 0
+	for _, c := range checks {
+		if c.Result == "fail" {
+			failed++
+		}
+	}
+
+	verdict = strings.ToUpper(verdict)
+	if verdict == "PASS" && failed > 0 {
+		err := fmt.Errorf("verdict PASS contradicts %d of %d failed checks: use REFINE or FAIL, or fix the hypothesis and verify again", failed, len(checks))
+		t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"verdict": verdict}, err.Error())
+		return "", nil, err
+	}
+	var dest, message string
+	switch verdict {
+	case "PASS":
+		dest = "L1"
+		message = fmt.Sprintf("Hypothesis %s (kind: %s) promoted to L1", hypothesisID, carrierRef)
+	case "FAIL":
+		dest = "invalid"
+		message = fmt.Sprintf("Hypothesis %s moved to invalid", hypothesisID)
+	case "REFINE":
+		message = fmt.Sprintf("Hypothesis %s requires refinement (staying in L0). Use quint_refine to replace it with a refined hypothesis.", hypothesisID)
+	default:
+		return "", nil, fmt.Errorf("unknown verdict: %s", verdict)
+	}
+
+	var evidenceIDs []string
+	validUntil := defaultValidUntil()
+	err = t.transact(func() error {
+		if dest != "" {
+			if _, err := t.MoveHypothesis(hypothesisID, "L0", dest); err != nil {
+				return err
+			}
+		}
+		for _, c := range checks {
+			id := t.verificationEvidenceID(hypothesisID, c)
+			if _, err := t.recordEvidence(id, hypothesisID, "verification",
+				verificationContent(c), c.Result, "L1", carrierRef, validUntil); err != nil {
+				return err
+			}
+			evidenceIDs = append(evidenceIDs, id)
+		}
+		return nil
+	})
+	if err != nil {
+		t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"verdict": verdict}, err.Error())
+		return "", nil, err
+	}
+
+	result := dest
+	if result == "" {
+		result = "L0"
+	}
+	t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "SUCCESS",
+		map[string]string{"verdict": verdict, "result": result, "checks": fmt.Sprint(len(checks)), "failed": fmt.Sprint(failed)}, "")
+	return message, evidenceIDs, nil
+}
@@ -0,0 +1,123 @@
+package fpf
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/m0n0x41d/quint-code/assurance"
+)
+
+func TestParseVerificationChecks(t *testing.T) {
+	fromMCP := []interface{}{
+		map[string]interface{}{"name": "types", "category": "type-check", "result": "pass"},
+		map[string]interface{}{"name": "budget", "category": "constraint", "result": "fail", "notes": "p99 over 50ms"},
+	}
+	checks, err := ParseVerificationChecks(fromMCP)
+	if err != nil {
+		t.Fatalf("ParseVerificationChecks failed: %v", err)
+	}
+	if len(checks) != 2 || checks[1].Notes != "p99 over 50ms" {
+		t.Errorf("Unexpected checks: %+v", checks)
+	}
+
+	if checks, err := ParseVerificationChecks(`[{"name": "types", "category": "type-check", "result": "pass"}]`); err != nil || len(checks) != 1 {
+		t.Errorf("Expected a JSON string to parse, got %+v (%v)", checks, err)
+	}
+
+	for _, input := range []interface{}{`{"logic_check": "passed"}`, `[{"name": "x", "verdict": "pass"}]`, 42} {
+		if _, err := ParseVerificationChecks(input); err == nil {
+			t.Errorf("Expected %v to be rejected", input)
+		}
+	}
+}
+
+func TestVerifyHypothesis_RejectsInvalidChecks(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	_, _ = tools.ProposeHypothesis("Redis", "Use Redis", "api", "system", "{}", "", nil, 3)
+
+	tests := []struct {
+		checks []VerificationCheck
+		want   string
+	}{
+		{nil, "at least one"},
+		{[]VerificationCheck{{Name: "logic", Category: "vibes", Result: "pass"}}, "category must be one of"},
+		{[]VerificationCheck{{Name: "logic", Category: CheckConstraint, Result: "passed"}}, "result must be pass or fail"},
+		{[]VerificationCheck{{Name: " ", Category: CheckConstraint, Result: "pass"}}, "has no name"},
+		{[]VerificationCheck{
+			{Name: "Logic", Category: CheckLogicalConsistency, Result: "pass"},
+			{Name: "logic", Category: CheckConstraint, Result: "pass"},
+		}, "duplicate check"},
+		{[]VerificationCheck{
+			{Name: "Types line up", Category: CheckTypeCheck, Result: "pass"},
+			{Name: "Latency budget", Category: CheckConstraint, Result: "fail"},
+		}, "verdict PASS contradicts 1 of 2 failed checks"},
+	}
+	for _, tt := range tests {
+		_, _, err := tools.VerifyHypothesis("redis", tt.checks, "PASS")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expected error containing %q for %+v, got %v", tt.want, tt.checks, err)
+		}
+	}
+
+	if holon, _ := tools.DB.GetHolon(context.Background(), "redis"); holon.Layer != "L0" {
+		t.Errorf("Expected a rejected verification to leave the hypothesis in L0, got %s", holon.Layer)
+	}
+	if evidence, _ := tools.DB.GetEvidence(context.Background(), "redis"); len(evidence) != 0 {
+		t.Errorf("Expected a rejected verification to record no evidence, got %d", len(evidence))
+	}
+}
+
+func TestVerifyHypothesis_EachCheckIsEvidence(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+	_, _ = tools.ProposeHypothesis("Redis", "Use Redis", "api", "system", "{}", "", nil, 3)
+
+	checks := []VerificationCheck{
+		{Name: "Types line up", Category: "Type-Check", Result: "PASS"},
+		{Name: "Latency budget", Category: CheckConstraint, Result: "fail", Notes: "p99 is 80ms, budget 50ms"},
+	}
+	msg, evidenceIDs, err := tools.VerifyHypothesis("redis", checks, "REFINE")
+	if err != nil {
+		t.Fatalf("VerifyHypothesis failed: %v", err)
+	}
+	if !strings.Contains(msg, "requires refinement") {
+		t.Errorf("Unexpected message: %q", msg)
+	}
+
+	evidence, err := tools.DB.GetEvidence(ctx, "redis")
+	if err != nil || len(evidence) != 2 {
+		t.Fatalf("Expected one evidence item per check, got %d (%v)", len(evidence), err)
+	}
+	byID := make(map[string]string)
+	for _, e := range evidence {
+		if e.Type != "verification" {
+			t.Errorf("Expected verification evidence, got %s", e.Type)
+		}
+		byID[e.ID] = e.Verdict
+	}
+	if byID[evidenceFileName("verification-types-line-up", "redis")] != "pass" || byID[evidenceFileName("verification-latency-budget", "redis")] != "fail" {
+		t.Errorf("Unexpected evidence verdicts: %v", byID)
+	}
+
+	report, err := assurance.New(tools.DB.GetRawDB()).CalculateReliability(ctx, "redis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.SelfScore != 0.5 {
+		t.Errorf("Expected the failed check to halve R, got %.2f", report.SelfScore)
+	}
+
+	if len(evidenceIDs) != len(checks) {
+		t.Fatalf("Expected one evidence ID per check, got %v", evidenceIDs)
+	}
+	if err := tools.RecordVerificationFormality(evidenceIDs, 3); err != nil {
+		t.Fatalf("RecordVerificationFormality failed: %v", err)
+	}
+	for _, id := range evidenceIDs {
+		e, _ := tools.DB.GetEvidenceByID(ctx, id)
+		if e.Formality.Int64 != 3 {
+			t.Errorf("Expected F3 on %s, got %v", e.ID, e.Formality)
+		}
+	}
+}
@@ -16,18 +16,30 @@ SELECT title FROM holons WHERE id = ? LIMIT 1;
 -- name: ListAllHolonIDs :many
 SELECT id FROM holons;
 
+-- name: ListAllHolons :many
+SELECT * FROM holons ORDER BY id ASC;
+
 -- name: ListHolonsByLayer :many
 SELECT * FROM holons WHERE layer = ? ORDER BY created_at DESC;
 
 -- name: UpdateHolonLayer :exec
 UPDATE holons SET layer = ?, updated_at = ? WHERE id = ?;
 
+-- name: UpdateHolonClaim :exec
+UPDATE holons SET formality = ?, claim_scope = ?, updated_at = ? WHERE id = ?;
+
 -- name: UpdateHolonRScore :exec
 UPDATE holons SET cached_r_score = ?, updated_at = ? WHERE id = ?;
 
+-- name: SetHolonParent :exec
+UPDATE holons SET parent_id = ?, updated_at = ? WHERE id = ?;
+
 -- name: GetHolonsByParent :many
 SELECT * FROM holons WHERE parent_id = ? ORDER BY created_at DESC;
 
+-- name: ListHolonsByContext :many
+SELECT * FROM holons WHERE context_id = ? ORDER BY created_at ASC, id ASC;
+
 -- name: CountHolonsByLayer :many
 SELECT layer, COUNT(*) as count FROM holons WHERE context_id = ? GROUP BY layer;
 
@@ -49,7 +61,24 @@ SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cach
 
 -- name: AddEvidence :exec
 INSERT INTO evidence (id, holon_id, type, content, verdict, assurance_level, carrier_ref, valid_until, created_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+    holon_id = excluded.holon_id, type = excluded.type, content = excluded.content, verdict = excluded.verdict,
+    assurance_level = excluded.assurance_level, carrier_ref = excluded.carrier_ref, valid_until = excluded.valid_until,
+    created_at = excluded.created_at, formality = excluded.formality,
+    artifact_kind = NULL, artifact_ref = NULL, artifact_hash = NULL, artifact_source = NULL, recipe = NULL;
+
+-- name: UpdateEvidenceFormality :exec
+UPDATE evidence SET formality = ? WHERE id = ?;
+
+-- name: UpdateEvidenceArtifact :exec
+UPDATE evidence SET artifact_kind = ?, artifact_ref = ?, artifact_hash = ?, artifact_source = ? WHERE id = ?;
+
+-- name: UpdateEvidenceRecipe :exec
+UPDATE evidence SET recipe = ? WHERE id = ?;
+
+-- name: UpdateEvidenceValidUntil :exec
+UPDATE evidence SET valid_until = ? WHERE id = ?;
 
 -- name: GetEvidenceByHolon :many
 SELECT * FROM evidence WHERE holon_id = ? ORDER BY created_at DESC;
@@ -57,11 +86,15 @@ SELECT * FROM evidence WHERE holon_id = ? ORDER BY created_at DESC;
 -- name: GetEvidenceWithCarrier :many
 SELECT * FROM evidence WHERE carrier_ref IS NOT NULL AND carrier_ref != '';
 
+-- name: ListAllEvidence :many
+SELECT * FROM evidence ORDER BY id ASC;
+
 -- Relation queries
 
 -- name: AddRelation :exec
 INSERT INTO relations (source_id, target_id, relation_type, created_at)
-VALUES (?, ?, ?, ?);
+VALUES (?, ?, ?, ?)
+ON CONFLICT(source_id, target_id, relation_type) DO NOTHING;
 
 -- name: CreateRelation :exec
 INSERT INTO relations (source_id, relation_type, target_id, congruence_level)
@@ -72,6 +105,29 @@ DO UPDATE SET congruence_level = excluded.congruence_level;
 -- name: GetRelationsByTarget :many
 SELECT * FROM relations WHERE target_id = ? AND relation_type = ?;
 
+-- name: ListAllRelations :many
+SELECT * FROM relations ORDER BY source_id ASC, target_id ASC, relation_type ASC;
+
+-- name: DeleteRelation :exec
+DELETE FROM relations WHERE source_id = ? AND target_id = ? AND relation_type = ?;
+
+-- Reindex: the projected tables are rebuilt from the markdown files
+
+-- name: DeleteAllHolons :exec
+DELETE FROM holons;
+
+-- name: DeleteAllEvidence :exec
+DELETE FROM evidence;
+
+-- name: DeleteAllRelations :exec
+DELETE FROM relations;
+
+-- name: DeleteAllWaivers :exec
+DELETE FROM waivers;
+
+-- name: GetHolonRelations :many
+SELECT * FROM relations WHERE source_id = ? OR target_id = ?;
+
 -- name: GetComponentsOf :many
 SELECT source_id, congruence_level FROM relations
 WHERE target_id = ? AND relation_type = 'componentOf';
@@ -104,7 +160,7 @@ INSERT INTO characteristics (id, holon_id, name, scale, value, unit, created_at)
 VALUES (?, ?, ?, ?, ?, ?, ?);
 
 -- name: GetCharacteristics :many
-SELECT * FROM characteristics WHERE holon_id = ?;
+SELECT * FROM characteristics WHERE holon_id = ? ORDER BY created_at ASC, id ASC;
 
 -- Audit log queries
 
@@ -127,16 +183,72 @@ SELECT * FROM audit_log ORDER BY timestamp DESC LIMIT ?;
 INSERT INTO waivers (id, evidence_id, waived_by, waived_until, rationale, created_at)
 VALUES (?, ?, ?, ?, ?, ?);
 
+-- name: RevokeWaiver :exec
+UPDATE waivers SET revoked_at = ? WHERE id = ?;
+
 -- name: GetActiveWaiverForEvidence :one
 SELECT * FROM waivers
-WHERE evidence_id = ? AND waived_until > datetime('now')
+WHERE evidence_id = ? AND waived_until > datetime('now') AND revoked_at IS NULL
 ORDER BY waived_until DESC LIMIT 1;
 
 -- name: GetWaiversByEvidence :many
 SELECT * FROM waivers WHERE evidence_id = ? ORDER BY created_at DESC;
 
 -- name: GetAllActiveWaivers :many
-SELECT * FROM waivers WHERE waived_until > datetime('now') ORDER BY waived_until ASC;
+SELECT * FROM waivers WHERE waived_until > datetime('now') AND revoked_at IS NULL ORDER BY waived_until ASC;
 
 -- name: GetEvidenceByID :one
 SELECT * FROM evidence WHERE id = ? LIMIT 1;
+
+-- name: ListEvidenceByContext :many
+SELECT e.* FROM evidence e
+JOIN holons h ON h.id = e.holon_id
+WHERE h.context_id = ?
+ORDER BY e.created_at ASC, e.id ASC;
+
+-- Loopback queries
+
+-- name: CreateLoopback :exec
+INSERT INTO loopbacks (id, parent_id, child_id, insight, phase, context_id, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetLoopbackByChild :one
+SELECT * FROM loopbacks WHERE child_id = ? ORDER BY created_at DESC LIMIT 1;
+
+-- Context queries
+
+-- name: CreateContext :exec
+INSERT INTO contexts (id, title, description, status, created_at)
+VALUES (?, ?, ?, 'open', ?);
+
+-- name: GetContext :one
+SELECT * FROM contexts WHERE id = ? LIMIT 1;
+
+-- name: ListContexts :many
+SELECT * FROM contexts ORDER BY created_at ASC, id ASC;
+
+-- name: ArchiveContext :exec
+UPDATE contexts SET status = 'archived', archived_at = ? WHERE id = ?;
+
+-- Context snapshot queries
+
+-- name: ListContextSnapshots :many
+SELECT * FROM context_snapshots WHERE context_id = ? ORDER BY source;
+
+-- name: UpsertContextSnapshot :exec
+INSERT INTO context_snapshots (context_id, source, content, content_hash, taken_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(context_id, source) DO UPDATE SET content = excluded.content, content_hash = excluded.content_hash, taken_at = excluded.taken_at;
+
+-- name: DeleteContextSnapshot :exec
+DELETE FROM context_snapshots WHERE context_id = ? AND source = ?;
+
+-- Settings queries
+
+-- name: GetSetting :one
+SELECT value FROM settings WHERE key = ? LIMIT 1;
+
+-- name: SetSetting :exec
+INSERT INTO settings (key, value, updated_at)
+VALUES (?, ?, ?)
+ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at;
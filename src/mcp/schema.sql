@@ -13,7 +13,9 @@ CREATE TABLE holons (
     parent_id TEXT REFERENCES holons(id),
     cached_r_score REAL DEFAULT 0.0 CHECK(cached_r_score BETWEEN 0.0 AND 1.0),
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    formality INTEGER DEFAULT 0 CHECK(formality BETWEEN 0 AND 9),
+    claim_scope TEXT
 );
 
 CREATE TABLE evidence (
@@ -26,6 +28,12 @@ CREATE TABLE evidence (
     carrier_ref TEXT,
     valid_until DATETIME,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    formality INTEGER CHECK(formality BETWEEN 0 AND 9),
+    artifact_kind TEXT,
+    artifact_ref TEXT,
+    artifact_hash TEXT,
+    artifact_source TEXT,
+    recipe TEXT,
     FOREIGN KEY(holon_id) REFERENCES holons(id)
 );
 
@@ -81,6 +89,7 @@ CREATE TABLE waivers (
     waived_until DATETIME NOT NULL,
     rationale TEXT NOT NULL,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    revoked_at DATETIME,
     FOREIGN KEY(evidence_id) REFERENCES evidence(id)
 );
 
@@ -94,7 +103,54 @@ CREATE TABLE fpf_state (
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE TABLE contexts (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL,
+    description TEXT,
+    status TEXT NOT NULL DEFAULT 'open' CHECK(status IN ('open', 'archived')),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    archived_at DATETIME
+);
+
+CREATE TABLE settings (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Last actualized state of context.md and project manifests, per context (migration #14)
+CREATE TABLE context_snapshots (
+    context_id TEXT NOT NULL,
+    source TEXT NOT NULL,
+    content TEXT NOT NULL,
+    content_hash TEXT NOT NULL,
+    taken_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (context_id, source)
+);
+
+-- Refinement loopbacks: an invalidated parent hypothesis and the child that replaced it
+CREATE TABLE loopbacks (
+    id TEXT PRIMARY KEY,
+    parent_id TEXT NOT NULL,
+    child_id TEXT NOT NULL,
+    insight TEXT NOT NULL,
+    phase TEXT NOT NULL,
+    context_id TEXT NOT NULL DEFAULT 'default',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Full-text index over holons, DRRs and evidence; kept in sync by triggers (migration #9)
+CREATE VIRTUAL TABLE search_index USING fts5(
+    doc_id UNINDEXED,
+    doc_type UNINDEXED,
+    holon_id UNINDEXED,
+    title,
+    content,
+    tokenize = 'porter unicode61'
+);
+
 -- Indexes for WLNK traversal
 CREATE INDEX IF NOT EXISTS idx_relations_target ON relations(target_id, relation_type);
 CREATE INDEX IF NOT EXISTS idx_relations_source ON relations(source_id, relation_type);
 CREATE INDEX IF NOT EXISTS idx_waivers_evidence ON waivers(evidence_id);
+CREATE INDEX IF NOT EXISTS idx_loopbacks_child ON loopbacks(child_id);